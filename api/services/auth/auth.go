@@ -5,15 +5,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"runtime"
 
 	"encore.dev"
 	esqldb "encore.dev/storage/sqldb"
 	"github.com/ardanlabs/conf/v3"
+	"github.com/ardanlabs/encore/app/domain/accountapp"
+	"github.com/ardanlabs/encore/app/domain/apikeyapp"
+	"github.com/ardanlabs/encore/app/domain/authapp"
+	"github.com/ardanlabs/encore/app/domain/lockoutapp"
 	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/ardanlabs/encore/business/domain/accountbus/stores/accountdb"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/apikeybus/stores/apikeydb"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/domain/auditbus/stores/auditdb"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus/stores/lockoutdb"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus/stores/tokendb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/delegate/stores/delegatedb"
+	"github.com/ardanlabs/encore/business/sdk/mailer"
+	"github.com/ardanlabs/encore/business/sdk/outbox"
+	"github.com/ardanlabs/encore/business/sdk/outbox/stores/outboxdb"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/keystore"
 	"github.com/ardanlabs/encore/foundation/logger"
@@ -36,22 +56,42 @@ var secrets struct {
 //
 //encore:service
 type Service struct {
-	log     *logger.Logger
-	db      *sqlx.DB
-	auth    *auth.Auth
-	userBus *userbus.Business
+	log        *logger.Logger
+	db         *sqlx.DB
+	auth       *auth.Auth
+	apiKeyBus  *apikeybus.Business
+	lockoutBus *lockoutbus.Business
+	authApp    *authapp.App
+	apiKeyApp  *apikeyapp.App
+	lockoutApp *lockoutapp.App
+	accountApp *accountapp.App
 }
 
 // NewService is called to create a new encore Service.
 func NewService(log *logger.Logger, db *sqlx.DB, ath *auth.Auth) (*Service, error) {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
+	// Events are written to the same outbox as the sales service, so its
+	// relay job publishes them in order with the events written there.
+	outbox := outbox.New(log, sqldb.NewBeginner(db), outboxdb.NewStore(log, db))
+	delegate := delegate.New(log, sqldb.NewBeginner(db), outbox, delegatedb.NewStore(log, db), nil)
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, db))
+	userBus := userbus.NewBusiness(log, auditBus, delegate, userdb.NewStore(log, db))
+	tokenBus := tokenbus.NewBusiness(log, delegate, tokendb.NewStore(log, db))
+	apiKeyBus := apikeybus.NewBusiness(log, userBus, apikeydb.NewStore(log, db))
+	lockoutBus := lockoutbus.NewBusiness(log, userBus, delegate, lockoutdb.NewStore(log, db))
+
+	// Mail is written to the log until a mail provider is configured.
+	accountBus := accountbus.NewBusiness(log, userBus, mailer.NewLogMailer(log), accountdb.NewStore(log, db))
 
 	s := Service{
-		log:     log,
-		db:      db,
-		auth:    ath,
-		userBus: userBus,
+		log:        log,
+		db:         db,
+		auth:       ath,
+		apiKeyBus:  apiKeyBus,
+		lockoutBus: lockoutBus,
+		authApp:    authapp.NewApp(ath, userBus, tokenBus, lockoutBus),
+		apiKeyApp:  apikeyapp.NewApp(apiKeyBus),
+		lockoutApp: lockoutapp.NewApp(lockoutBus, userBus),
+		accountApp: accountapp.NewApp(accountBus, userBus, tokenBus),
 	}
 
 	return &s, nil
@@ -98,8 +138,10 @@ func startup(log *logger.Logger) (*sqlx.DB, *auth.Auth, error) {
 	cfg := struct {
 		conf.Version
 		Auth struct {
-			ActiveKID string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
-			Issuer    string `conf:"default:service project"`
+			KeysFolder  string   `conf:"default:zarf/keys/"`
+			ActiveKID   string   `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
+			RetiredKIDs []string `conf:"default:"`
+			Issuer      string   `conf:"default:service project"`
 		}
 		DB struct {
 			MaxIdleConns int `conf:"default:0"`
@@ -154,11 +196,43 @@ func startup(log *logger.Logger) (*sqlx.DB, *auth.Auth, error) {
 
 	// Load the private keys files from disk. We can assume some system like
 	// Vault has created these files already. How that happens is not our
-	// concern.
+	// concern. A deployment without the keys folder has no key files.
 
 	ks := keystore.New()
-	if err := ks.LoadKey(secrets.KeyID, secrets.KeyPEM); err != nil {
-		return nil, nil, fmt.Errorf("reading keys: %w", err)
+	if err := ks.LoadDirectory(os.DirFS(cfg.Auth.KeysFolder), "."); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("reading keys: %w", err)
+		}
+		log.Info(ctx, "initService", "status", "no keys folder", "folder", cfg.Auth.KeysFolder)
+	}
+
+	// New tokens are signed with the active key. The keys that were active
+	// before keep verifying the tokens they signed until they are retired.
+	// The key provided as a secret is active when it's the configured key or
+	// no key file was found for the configured key.
+
+	activeKID := cfg.Auth.ActiveKID
+	_, err = ks.PublicKey(activeKID)
+	fileActive := err == nil
+
+	if secrets.KeyPEM != "" {
+		if err := ks.LoadKey(secrets.KeyID, secrets.KeyPEM, keystore.StateRetiring); err != nil {
+			return nil, nil, fmt.Errorf("reading secret key: %w", err)
+		}
+
+		if !fileActive {
+			activeKID = secrets.KeyID
+		}
+	}
+
+	if err := ks.SetState(activeKID, keystore.StateActive); err != nil {
+		return nil, nil, fmt.Errorf("activating key[%s]: %w", activeKID, err)
+	}
+
+	for _, kid := range cfg.Auth.RetiredKIDs {
+		if err := ks.SetState(kid, keystore.StateRetired); err != nil {
+			return nil, nil, fmt.Errorf("retiring key[%s]: %w", kid, err)
+		}
 	}
 
 	authCfg := auth.Config{
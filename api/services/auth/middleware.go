@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"encore.dev/middleware"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+)
+
+// =============================================================================
+// Global middleware functions
+
+//lint:ignore U1000 "called by encore"
+//encore:middleware target=all
+func (s *Service) tenancy(req middleware.Request, next middleware.Next) middleware.Response {
+	req, err := mid.Tenancy(req)
+	if err != nil {
+		return errs.NewResponse(errs.Unauthenticated, err)
+	}
+
+	return next(req)
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/cron"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// retention is how long expired tokens are kept around before they are
+// permanently removed.
+const retention = 24 * time.Hour
+
+// We need a single job which will permanently remove the refresh tokens and
+// denylist entries that are no longer useful.
+var _ = cron.NewJob("purge-tokens", cron.JobConfig{
+	Title:    "Purge expired refresh tokens and revoked access tokens",
+	Every:    24 * cron.Hour,
+	Endpoint: PurgeTokens,
+})
+
+// PurgeTokens permanently removes the refresh tokens and denylist entries
+// that expired longer ago than the retention window. The job acts for the
+// system so the tokens of every tenant are purged.
+//
+//encore:api private method=POST path=/v1/auth/purge
+func (s *Service) PurgeTokens(ctx context.Context) error {
+	ctx = sqldb.WithSystem(ctx)
+
+	n, err := s.authApp.Purge(ctx, retention)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info(ctx, "purge-tokens", "tokens", n)
+
+	return nil
+}
@@ -5,21 +5,32 @@ import (
 	"strings"
 
 	eauth "encore.dev/beta/auth"
+	"github.com/ardanlabs/encore/app/domain/accountapp"
+	"github.com/ardanlabs/encore/app/domain/apikeyapp"
+	"github.com/ardanlabs/encore/app/domain/authapp"
+	"github.com/ardanlabs/encore/app/domain/lockoutapp"
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/google/uuid"
 )
 
 // =============================================================================
-// JWT or Basic Athentication handling
+// JWT, Basic or ApiKey Athentication handling
 
 type authParams struct {
 	Authorization string `header:"Authorization"`
+	ForwardedFor  string `header:"X-Forwarded-For"`
 }
 
 //lint:ignore U1000 "called by encore"
 //encore:authhandler
 func (s *Service) AuthHandler(ctx context.Context, ap *authParams) (eauth.UID, *auth.Claims, error) {
+
+	// The tenant of the caller isn't known until it's authenticated.
+	ctx = sqldb.WithSystem(ctx)
+
 	parts := strings.Split(ap.Authorization, " ")
 
 	switch parts[0] {
@@ -27,7 +38,10 @@ func (s *Service) AuthHandler(ctx context.Context, ap *authParams) (eauth.UID, *
 		return mid.Bearer(ctx, s.auth, ap.Authorization)
 
 	case "Basic":
-		return mid.Basic(ctx, s.auth, s.userBus, ap.Authorization)
+		return mid.Basic(ctx, s.auth, s.lockoutBus, ap.Authorization, mid.ClientIP(ap.ForwardedFor))
+
+	case "ApiKey":
+		return mid.APIKey(ctx, s.auth, s.apiKeyBus, ap.Authorization)
 	}
 
 	return "", nil, errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action")
@@ -55,12 +69,188 @@ func (s *Service) UserToken(ctx context.Context, kid string) (token, error) {
 	return token{tkn}, nil
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:api public method=GET path=/.well-known/jwks.json
+func (s *Service) JWKS(ctx context.Context) (auth.JWKS, error) {
+	jwks, err := s.auth.JWKS()
+	if err != nil {
+		return auth.JWKS{}, errs.New(errs.Internal, err)
+	}
+
+	return jwks, nil
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api public method=POST path=/v1/auth/login
+func (s *Service) Login(ctx context.Context, app authapp.Login) (authapp.Token, error) {
+
+	// The tenant of the caller isn't known until it's authenticated.
+	ctx = sqldb.WithSystem(ctx)
+
+	return s.authApp.Login(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api public method=POST path=/v1/auth/refresh
+func (s *Service) Refresh(ctx context.Context, app authapp.Refresh) (authapp.Token, error) {
+
+	// The tenant of the caller isn't known until the refresh token is found.
+	ctx = sqldb.WithSystem(ctx)
+
+	return s.authApp.Refresh(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/auth/logout
+func (s *Service) Logout(ctx context.Context, app authapp.Logout) error {
+	claims := eauth.Data().(*auth.Claims)
+
+	return s.authApp.Logout(ctx, *claims, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api public method=POST path=/v1/users/password-reset
+func (s *Service) PasswordReset(ctx context.Context, app accountapp.PasswordReset) error {
+
+	// The tenant of the caller isn't known until the user is found.
+	ctx = sqldb.WithSystem(ctx)
+
+	return s.accountApp.RequestPasswordReset(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api public method=POST path=/v1/users/password-reset/confirm
+func (s *Service) PasswordResetConfirm(ctx context.Context, app accountapp.ConfirmPasswordReset) error {
+
+	// The tenant of the caller isn't known until the token is found.
+	ctx = sqldb.WithSystem(ctx)
+
+	return s.accountApp.ConfirmPasswordReset(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/users/verify-email
+func (s *Service) VerifyEmail(ctx context.Context) error {
+	userID, err := claimsUserID()
+	if err != nil {
+		return err
+	}
+
+	return s.accountApp.RequestVerification(ctx, userID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api public method=POST path=/v1/users/verify-email/confirm
+func (s *Service) VerifyEmailConfirm(ctx context.Context, app accountapp.ConfirmVerification) error {
+
+	// The tenant of the caller isn't known until the token is found.
+	ctx = sqldb.WithSystem(ctx)
+
+	return s.accountApp.ConfirmVerification(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/apikeys
+func (s *Service) APIKeyCreate(ctx context.Context, app apikeyapp.NewAPIKey) (apikeyapp.MintedAPIKey, error) {
+	userID, err := claimsUserID()
+	if err != nil {
+		return apikeyapp.MintedAPIKey{}, err
+	}
+
+	return s.apiKeyApp.Create(ctx, userID, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/apikeys
+func (s *Service) APIKeyQuery(ctx context.Context) (apikeyapp.APIKeys, error) {
+	userID, err := claimsUserID()
+	if err != nil {
+		return apikeyapp.APIKeys{}, err
+	}
+
+	return s.apiKeyApp.Query(ctx, userID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/apikeys/:keyID
+func (s *Service) APIKeyQueryByID(ctx context.Context, keyID string) (apikeyapp.APIKey, error) {
+	userID, err := claimsUserID()
+	if err != nil {
+		return apikeyapp.APIKey{}, err
+	}
+
+	return s.apiKeyApp.QueryByID(ctx, userID, keyID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/apikeys/:keyID
+func (s *Service) APIKeyRevoke(ctx context.Context, keyID string) error {
+	userID, err := claimsUserID()
+	if err != nil {
+		return err
+	}
+
+	return s.apiKeyApp.Revoke(ctx, userID, keyID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/lockouts
+func (s *Service) LockoutQuery(ctx context.Context, app lockoutapp.Subject) (lockoutapp.Lockout, error) {
+	claims, err := s.adminClaims(ctx)
+	if err != nil {
+		return lockoutapp.Lockout{}, err
+	}
+
+	return s.lockoutApp.QueryBySubject(ctx, claims, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/lockouts/unlock
+func (s *Service) LockoutUnlock(ctx context.Context, app lockoutapp.Subject) error {
+	claims, err := s.adminClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.lockoutApp.Unlock(ctx, claims, app)
+}
+
+// Authorize evaluates the authorization policy for callers that don't
+// evaluate it in process.
+//
 //lint:ignore U1000 "called by encore"
 //encore:api private method=POST path=/v1/authorize
 func (s *Service) Authorize(ctx context.Context, authInfo mid.AuthInfo) error {
-	if err := s.auth.Authorize(ctx, authInfo.Claims, authInfo.UserID, authInfo.Rule); err != nil {
-		return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", authInfo.Claims.Roles, authInfo.Rule, err)
+	return mid.CheckAuthorization(ctx, s.auth, authInfo)
+}
+
+// =============================================================================
+
+// claimsUserID returns the id of the user the auth handler authenticated.
+func claimsUserID() (uuid.UUID, error) {
+	claims := eauth.Data().(*auth.Claims)
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.UUID{}, errs.Newf(errs.Unauthenticated, "parsing subject: %s", err)
+	}
+
+	return userID, nil
+}
+
+// adminClaims returns the claims the auth handler authenticated when they
+// belong to an admin.
+func (s *Service) adminClaims(ctx context.Context) (auth.Claims, error) {
+	claims := eauth.Data().(*auth.Claims)
+
+	authInfo := mid.AuthInfo{
+		Claims: *claims,
+		Rule:   auth.RuleAdminOnly,
+	}
+
+	if err := mid.CheckAuthorization(ctx, s.auth, authInfo); err != nil {
+		return auth.Claims{}, err
 	}
 
-	return nil
+	return *claims, nil
 }
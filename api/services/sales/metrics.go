@@ -14,6 +14,10 @@ var (
 	requests   = emetrics.NewCounter[uint64]("requests", emetrics.CounterConfig{})
 	failures   = emetrics.NewCounter[uint64]("errors", emetrics.CounterConfig{})
 	panics     = emetrics.NewCounter[uint64]("panics", emetrics.CounterConfig{})
+
+	delegateSuccesses = emetrics.NewCounterGroup[metrics.DelegateLabels, uint64]("delegate_successes", emetrics.CounterConfig{})
+	delegateFailures  = emetrics.NewCounterGroup[metrics.DelegateLabels, uint64]("delegate_failures", emetrics.CounterConfig{})
+	delegateLatency   = emetrics.NewCounterGroup[metrics.DelegateLabels, uint64]("delegate_latency_ms", emetrics.CounterConfig{})
 )
 
 // newMetrics will construct a business layer metrics value that will allow
@@ -25,5 +29,9 @@ func newMetrics() *metrics.Values {
 		Requests:   requests,
 		Failures:   failures,
 		Panics:     panics,
+
+		DelegateSuccesses: delegateSuccesses,
+		DelegateFailures:  delegateFailures,
+		DelegateLatency:   delegateLatency,
 	})
 }
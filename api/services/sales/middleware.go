@@ -1,12 +1,7 @@
 package sales
 
 import (
-	"context"
-	"fmt"
-	"time"
-
 	"encore.dev/middleware"
-	authsrv "github.com/ardanlabs/encore/api/services/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -36,12 +31,8 @@ func (s *Service) authorize(req middleware.Request, next middleware.Next) middle
 		return errs.NewResponse(errs.Unauthenticated, err)
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
-	defer cancel()
-
-	if err := authsrv.Authorize(ctx, p); err != nil {
-		err = fmt.Errorf("%s", err.Error()[17:]) // Remove "unauthenticated:" from the error string.
-		return errs.NewResponse(errs.Unauthenticated, err)
+	if err := mid.CheckAuthorization(req.Context(), s.authz, p); err != nil {
+		return middleware.Response{Err: err}
 	}
 
 	return next(req)
@@ -55,12 +46,8 @@ func (s *Service) authorizeUser(req middleware.Request, next middleware.Next) mi
 		return errs.NewResponse(errs.Unauthenticated, err)
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
-	defer cancel()
-
-	if err := authsrv.Authorize(ctx, p); err != nil {
-		err = fmt.Errorf("%s", err.Error()[17:]) // Remove "unauthenticated:" from the error string.
-		return errs.NewResponse(errs.Unauthenticated, err)
+	if err := mid.CheckAuthorization(req.Context(), s.authz, p); err != nil {
+		return middleware.Response{Err: err}
 	}
 
 	return next(req)
@@ -74,12 +61,8 @@ func (s *Service) authorizeProduct(req middleware.Request, next middleware.Next)
 		return errs.NewResponse(errs.Unauthenticated, err)
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
-	defer cancel()
-
-	if err := authsrv.Authorize(ctx, p); err != nil {
-		err = fmt.Errorf("%s", err.Error()[17:]) // Remove "unauthenticated:" from the error string.
-		return errs.NewResponse(errs.Unauthenticated, err)
+	if err := mid.CheckAuthorization(req.Context(), s.authz, p); err != nil {
+		return middleware.Response{Err: err}
 	}
 
 	return next(req)
@@ -93,14 +76,25 @@ func (s *Service) authorizeHome(req middleware.Request, next middleware.Next) mi
 		return errs.NewResponse(errs.Unauthenticated, err)
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
-	defer cancel()
+	if err := mid.CheckAuthorization(req.Context(), s.authz, p); err != nil {
+		return middleware.Response{Err: err}
+	}
 
-	if err := authsrv.Authorize(ctx, p); err != nil {
-		err = fmt.Errorf("%s", err.Error()[17:]) // Remove "unauthenticated:" from the error string.
+	return next(req)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:middleware target=tag:authorize_order
+func (s *Service) authorizeOrder(req middleware.Request, next middleware.Next) middleware.Response {
+	p, req, err := mid.AuthorizeOrder(s.orderBus, req)
+	if err != nil {
 		return errs.NewResponse(errs.Unauthenticated, err)
 	}
 
+	if err := mid.CheckAuthorization(req.Context(), s.authz, p); err != nil {
+		return middleware.Response{Err: err}
+	}
+
 	return next(req)
 }
 
@@ -1,28 +1,50 @@
 package sales
 
 import (
+	auditapp "github.com/ardanlabs/encore/app/domain/auditapp"
+	delegateapp "github.com/ardanlabs/encore/app/domain/delegateapp"
 	homeapp "github.com/ardanlabs/encore/app/domain/homeapp"
+	orderapp "github.com/ardanlabs/encore/app/domain/orderapp"
+	orgapp "github.com/ardanlabs/encore/app/domain/orgapp"
 	productapp "github.com/ardanlabs/encore/app/domain/productapp"
+	roleapp "github.com/ardanlabs/encore/app/domain/roleapp"
 	tranapp "github.com/ardanlabs/encore/app/domain/tranapp"
 	userapp "github.com/ardanlabs/encore/app/domain/userapp"
 	vproductapp "github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/outbox"
 )
 
 type appDomain struct {
+	auditApp    *auditapp.App
+	delegateApp *delegateapp.App
 	homeApp     *homeapp.App
+	orderApp    *orderapp.App
+	orgApp      *orgapp.App
 	productApp  *productapp.App
+	roleApp     *roleapp.App
 	tranApp     *tranapp.App
 	userApp     *userapp.App
 	vproductApp *vproductapp.App
 }
 
 type busDomain struct {
+	auditBus   *auditbus.Business
 	delegate   *delegate.Delegate
 	homeBus    *homebus.Business
+	orderBus   *orderbus.Business
+	orgBus     *orgbus.Business
+	outbox     *outbox.Outbox
 	productBus *productbus.Business
+	roleBus    *rolebus.Business
+	tokenBus   *tokenbus.Business
 	userBus    *userbus.Business
 }
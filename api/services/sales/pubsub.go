@@ -6,6 +6,7 @@ import (
 	"encore.dev/pubsub"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
 	bpubsub "github.com/ardanlabs/encore/business/sdk/pubsub"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 // We need a single subscription which will route a message to the
@@ -17,8 +18,14 @@ var _ = pubsub.NewSubscription(bpubsub.Delegate, "handle-delegate-call",
 )
 
 // DelegateHandler receives a message from the pubsub system and passes it
-// into the delegate system.
+// into the delegate system. A handler that fails is kept as a dead letter
+// and the message is acknowledged. The error is only returned when the dead
+// letter can't be kept, so the message is delivered again. The handlers that
+// already succeeded are not run again. The handlers act for the system since
+// the message isn't part of a request.
 func (s *Service) DelegateHandler(ctx context.Context, data delegate.Data) error {
+	ctx = sqldb.WithSystem(ctx)
+
 	s.log.Info(ctx, "DelegateHandler", "data", data)
-	return s.delegate.Call(ctx, data)
+	return s.delegate.Handle(ctx, data)
 }
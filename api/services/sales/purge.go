@@ -0,0 +1,60 @@
+package sales
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/cron"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// retention is how long deleted users, products and homes are kept around
+// so they can be restored before they are permanently removed.
+const retention = 30 * 24 * time.Hour
+
+// handledRetention is how long the delegate remembers the handlers that ran
+// for an event, so a redelivered event isn't handled twice.
+const handledRetention = 7 * 24 * time.Hour
+
+// We need a single job which will permanently remove the deleted rows that
+// are older than the retention window.
+var _ = cron.NewJob("purge-deleted", cron.JobConfig{
+	Title:    "Purge deleted users, products and homes",
+	Every:    24 * cron.Hour,
+	Endpoint: PurgeDeleted,
+})
+
+// PurgeDeleted permanently removes the users, products and homes that were
+// deleted longer ago than the retention window. Products and homes are purged
+// before users since they reference them. The records of the delegate
+// handlers that ran longer ago than their retention window are removed too.
+// The job acts for the system so the rows of every tenant are purged.
+//
+//encore:api private method=POST path=/v1/purge
+func (s *Service) PurgeDeleted(ctx context.Context) error {
+	ctx = sqldb.WithSystem(ctx)
+
+	prds, err := s.productApp.Purge(ctx, retention)
+	if err != nil {
+		return err
+	}
+
+	hmes, err := s.homeApp.Purge(ctx, retention)
+	if err != nil {
+		return err
+	}
+
+	usrs, err := s.userApp.Purge(ctx, retention)
+	if err != nil {
+		return err
+	}
+
+	handled, err := s.delegate.Purge(ctx, handledRetention)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info(ctx, "purge-deleted", "products", prds, "homes", hmes, "users", usrs, "handled", handled)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package sales
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/cron"
+	bpubsub "github.com/ardanlabs/encore/business/sdk/pubsub"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+// relayBatch is how many outbox events are published per transaction.
+const relayBatch = 100
+
+// outboxRetention is how long sent events are kept in the outbox.
+const outboxRetention = 7 * 24 * time.Hour
+
+// We need a single job which will publish the events written to the outbox.
+var _ = cron.NewJob("relay-outbox", cron.JobConfig{
+	Title:    "Relay outbox events to their topics",
+	Every:    1 * cron.Minute,
+	Endpoint: RelayOutbox,
+})
+
+// RelayOutbox publishes the pending outbox events to their topics in batches
+// until none are left, then removes the events that were sent longer ago than
+// the retention window. A batch holds one event per ordering key, so a batch
+// can be short while events are left. The job acts for the system so the
+// events of every tenant are relayed.
+//
+//encore:api private method=POST path=/v1/outbox/relay
+func (s *Service) RelayOutbox(ctx context.Context) error {
+	ctx = sqldb.WithSystem(ctx)
+
+	var sent int
+	for {
+		n, err := s.outbox.Relay(ctx, bpubsub.RelayPublisher{}, relayBatch)
+		if err != nil {
+			return err
+		}
+
+		sent += n
+
+		if n == 0 {
+			break
+		}
+	}
+
+	purged, err := s.outbox.Purge(ctx, outboxRetention)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info(ctx, "relay-outbox", "sent", sent, "purged", purged)
+
+	return nil
+}
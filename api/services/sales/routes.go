@@ -5,11 +5,17 @@ import (
 	"net/http"
 
 	"encore.dev"
+	"github.com/ardanlabs/encore/app/domain/auditapp"
+	"github.com/ardanlabs/encore/app/domain/delegateapp"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
+	"github.com/ardanlabs/encore/app/domain/orderapp"
+	"github.com/ardanlabs/encore/app/domain/orgapp"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/domain/roleapp"
 	"github.com/ardanlabs/encore/app/domain/tranapp"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/query"
 )
 
@@ -32,21 +38,61 @@ func (s *Service) Fallback(w http.ResponseWriter, r *http.Request) {
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/homes tag:metrics tag:authorize tag:as_user_role
+//encore:api auth method=GET path=/v1/audit tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) AuditQuery(ctx context.Context, qp auditapp.QueryParams) (query.Result[auditapp.Audit], error) {
+	return s.auditApp.Query(ctx, qp)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/deadletters tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterQuery(ctx context.Context, qp delegateapp.QueryParams) (query.Result[delegateapp.DeadLetter], error) {
+	return s.delegateApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/deadletters/:deadLetterID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterQueryByID(ctx context.Context, deadLetterID string) (delegateapp.DeadLetter, error) {
+	return s.delegateApp.QueryByID(ctx, deadLetterID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/deadletters/:deadLetterID/replay tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterReplay(ctx context.Context, deadLetterID string) error {
+	return s.delegateApp.Replay(ctx, deadLetterID)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=DELETE path=/v1/deadletters/:deadLetterID tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) DeadLetterDiscard(ctx context.Context, deadLetterID string) error {
+	return s.delegateApp.Discard(ctx, deadLetterID)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes tag:transaction tag:metrics tag:authorize tag:as_user_role
 func (s *Service) HomeCreate(ctx context.Context, app homeapp.NewHome) (homeapp.Home, error) {
 	return s.homeApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/homes/:homeID tag:metrics tag:authorize_home
+//encore:api auth method=PUT path=/v1/homes/:homeID tag:transaction tag:metrics tag:authorize_home
 func (s *Service) HomeUpdate(ctx context.Context, homeID string, app homeapp.UpdateHome) (homeapp.Home, error) {
 	return s.homeApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/homes/:homeID tag:metrics tag:authorize_home
-func (s *Service) HomeDelete(ctx context.Context, homeID string) error {
-	return s.homeApp.Delete(ctx)
+//encore:api auth method=DELETE path=/v1/homes/:homeID tag:transaction tag:metrics tag:authorize_home
+func (s *Service) HomeDelete(ctx context.Context, homeID string, pc etag.Precondition) error {
+	return s.homeApp.Delete(ctx, pc)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/homes/:homeID/restore tag:transaction tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) HomeRestore(ctx context.Context, homeID string) (homeapp.Home, error) {
+	return s.homeApp.Restore(ctx, homeID)
 }
 
 //lint:ignore U1000 "called by encore"
@@ -64,21 +110,61 @@ func (s *Service) HomeQueryByID(ctx context.Context, productID string) (homeapp.
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/products tag:metrics tag:authorize tag:as_user_role
+//encore:api auth method=POST path=/v1/orders tag:transaction tag:metrics tag:authorize tag:as_any_role
+func (s *Service) OrderCreate(ctx context.Context, app orderapp.NewOrder) (orderapp.Order, error) {
+	return s.orderApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/orders tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) OrderQuery(ctx context.Context, qp orderapp.QueryParams) (query.Result[orderapp.Order], error) {
+	return s.orderApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/orders/:orderID tag:metrics tag:authorize_order
+func (s *Service) OrderQueryByID(ctx context.Context, orderID string) (orderapp.Order, error) {
+	return s.orderApp.QueryByID(ctx)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/orgs tag:transaction tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) OrgCreate(ctx context.Context, app orgapp.NewOrganization) (orgapp.Organization, error) {
+	return s.orgApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/orgs/:orgID tag:metrics tag:authorize tag:as_any_role
+func (s *Service) OrgQueryByID(ctx context.Context, orgID string) (orgapp.Organization, error) {
+	return s.orgApp.QueryByID(ctx, orgID)
+}
+
+// =============================================================================
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products tag:transaction tag:metrics tag:authorize tag:as_user_role
 func (s *Service) ProductCreate(ctx context.Context, app productapp.NewProduct) (productapp.Product, error) {
 	return s.productApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/products/:productID tag:metrics tag:authorize_product
+//encore:api auth method=PUT path=/v1/products/:productID tag:transaction tag:metrics tag:authorize_product
 func (s *Service) ProductUpdate(ctx context.Context, productID string, app productapp.UpdateProduct) (productapp.Product, error) {
 	return s.productApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/products/:productID tag:metrics tag:authorize_product
-func (s *Service) ProductDelete(ctx context.Context, productID string) error {
-	return s.productApp.Delete(ctx)
+//encore:api auth method=DELETE path=/v1/products/:productID tag:transaction tag:metrics tag:authorize_product
+func (s *Service) ProductDelete(ctx context.Context, productID string, pc etag.Precondition) error {
+	return s.productApp.Delete(ctx, pc)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/products/:productID/restore tag:transaction tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) ProductRestore(ctx context.Context, productID string) (productapp.Product, error) {
+	return s.productApp.Restore(ctx, productID)
 }
 
 //lint:ignore U1000 "called by encore"
@@ -87,6 +173,12 @@ func (s *Service) ProductQuery(ctx context.Context, qp productapp.QueryParams) (
 	return s.productApp.Query(ctx, qp)
 }
 
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/products/search tag:metrics tag:authorize tag:as_any_role
+func (s *Service) ProductSearch(ctx context.Context, sp productapp.SearchParams) (query.Result[productapp.SearchResult], error) {
+	return s.productApp.Search(ctx, sp)
+}
+
 //lint:ignore U1000 "called by encore"
 //encore:api auth method=GET path=/v1/products/:productID tag:metrics tag:authorize_product
 func (s *Service) ProductQueryByID(ctx context.Context, productID string) (productapp.Product, error) {
@@ -95,6 +187,38 @@ func (s *Service) ProductQueryByID(ctx context.Context, productID string) (produ
 
 // =============================================================================
 
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/roles tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) RoleCreate(ctx context.Context, app roleapp.NewRole) (roleapp.Role, error) {
+	return s.roleApp.Create(ctx, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/roles/:name/permissions tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) RoleGrant(ctx context.Context, name string, app roleapp.GrantPermission) (roleapp.Role, error) {
+	return s.roleApp.Grant(ctx, name, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/roles tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) RoleQuery(ctx context.Context, qp roleapp.QueryParams) (query.Result[roleapp.Role], error) {
+	return s.roleApp.Query(ctx, qp)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/rules/:rule/permissions tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) RuleGrant(ctx context.Context, rule string, app roleapp.GrantRule) (roleapp.RulePermissions, error) {
+	return s.roleApp.GrantRule(ctx, rule, app)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=GET path=/v1/rules/:rule/permissions tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) RuleQuery(ctx context.Context, rule string) (roleapp.RulePermissions, error) {
+	return s.roleApp.QueryRule(ctx, rule)
+}
+
+// =============================================================================
+
 //lint:ignore U1000 "called by encore"
 //encore:api auth method=POST path=/v1/tran tag:transaction tag:metrics tag:authorize tag:as_admin_role
 func (s *Service) TranCreate(ctx context.Context, app tranapp.NewTran) (tranapp.Product, error) {
@@ -104,27 +228,33 @@ func (s *Service) TranCreate(ctx context.Context, app tranapp.NewTran) (tranapp.
 // =============================================================================
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=POST path=/v1/users tag:metrics tag:authorize tag:as_admin_role
+//encore:api auth method=POST path=/v1/users tag:transaction tag:metrics tag:authorize tag:as_admin_role
 func (s *Service) UserCreate(ctx context.Context, app userapp.NewUser) (userapp.User, error) {
 	return s.userApp.Create(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/users/:userID tag:metrics tag:authorize_user
+//encore:api auth method=PUT path=/v1/users/:userID tag:transaction tag:metrics tag:authorize_user
 func (s *Service) UserUpdate(ctx context.Context, userID string, app userapp.UpdateUser) (userapp.User, error) {
 	return s.userApp.Update(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=PUT path=/v1/role/:userID tag:metrics tag:authorize_user tag:as_admin_role
+//encore:api auth method=PUT path=/v1/role/:userID tag:transaction tag:metrics tag:authorize_user tag:as_admin_role
 func (s *Service) UserUpdateRole(ctx context.Context, userID string, app userapp.UpdateUserRole) (userapp.User, error) {
 	return s.userApp.UpdateRole(ctx, app)
 }
 
 //lint:ignore U1000 "called by encore"
-//encore:api auth method=DELETE path=/v1/users/:userID tag:metrics tag:authorize_user
-func (s *Service) UserDelete(ctx context.Context, userID string) error {
-	return s.userApp.Delete(ctx)
+//encore:api auth method=DELETE path=/v1/users/:userID tag:transaction tag:metrics tag:authorize_user
+func (s *Service) UserDelete(ctx context.Context, userID string, pc etag.Precondition) error {
+	return s.userApp.Delete(ctx, pc)
+}
+
+//lint:ignore U1000 "called by encore"
+//encore:api auth method=POST path=/v1/users/:userID/restore tag:transaction tag:metrics tag:authorize tag:as_admin_role
+func (s *Service) UserRestore(ctx context.Context, userID string) (userapp.User, error) {
+	return s.userApp.Restore(ctx, userID)
 }
 
 //lint:ignore U1000 "called by encore"
@@ -7,32 +7,56 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"time"
 
 	"encore.dev"
 	esqldb "encore.dev/storage/sqldb"
 	"github.com/ardanlabs/conf/v3"
+	"github.com/ardanlabs/encore/app/domain/auditapp"
+	"github.com/ardanlabs/encore/app/domain/delegateapp"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
+	"github.com/ardanlabs/encore/app/domain/orderapp"
+	"github.com/ardanlabs/encore/app/domain/orgapp"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/domain/roleapp"
 	"github.com/ardanlabs/encore/app/domain/tranapp"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/domain/vproductapp"
+	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/debug"
 	"github.com/ardanlabs/encore/app/sdk/metrics"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/domain/auditbus/stores/auditdb"
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/homebus/stores/homedb"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/orderbus/stores/orderdb"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/orgbus/stores/orgdb"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/productbus/stores/productdb"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/rolebus/stores/roledb"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus/stores/tokendb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus/stores/vproductdb"
 	"github.com/ardanlabs/encore/business/sdk/appdb/migrate"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/delegate/stores/delegatedb"
+	"github.com/ardanlabs/encore/business/sdk/outbox"
+	"github.com/ardanlabs/encore/business/sdk/outbox/stores/outboxdb"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/jmoiron/sqlx"
 )
 
+// authorizeCacheTTL is how long an authorization decision is reused. The
+// permissions of a role take this long to apply to the requests.
+const authorizeCacheTTL = 5 * time.Second
+
 // Represents the database this service will use. The name has to be a literal
 // string.
 var appDB = esqldb.Named("app")
@@ -47,35 +71,69 @@ type Service struct {
 	mtrcs *metrics.Values
 	db    *sqlx.DB
 	debug http.Handler
+	authz *auth.Authorizer
 	appDomain
 	busDomain
 }
 
 // NewService is called to create a new encore Service.
 func NewService(log *logger.Logger, db *sqlx.DB) (*Service, error) {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
-	productBus := productbus.NewBusiness(log, userBus, delegate, productdb.NewStore(log, db))
-	homeBus := homebus.NewBusiness(log, userBus, delegate, homedb.NewStore(log, db))
+	// Events are written to the outbox with the change that raised them and
+	// the relay job publishes them to the delegate topic, or to their public
+	// topic for the public events.
+	mtrcs := newMetrics()
+	outbox := outbox.New(log, sqldb.NewBeginner(db), outboxdb.NewStore(log, db))
+	delegate := delegate.New(log, sqldb.NewBeginner(db), outbox, delegatedb.NewStore(log, db), mtrcs)
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, db))
+	userBus := userbus.NewBusiness(log, auditBus, delegate, userdb.NewStore(log, db))
+	productBus := productbus.NewBusiness(log, userBus, auditBus, delegate, productdb.NewStore(log, db))
+	homeBus := homebus.NewBusiness(log, userBus, auditBus, delegate, homedb.NewStore(log, db))
+	orderBus := orderbus.NewBusiness(log, userBus, productBus, homeBus, orderdb.NewStore(log, db))
 	vproductBus := vproductbus.NewBusiness(vproductdb.NewStore(log, db))
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, db))
+	orgBus := orgbus.NewBusiness(log, orgdb.NewStore(log, db))
+	tokenBus := tokenbus.NewBusiness(log, delegate, tokendb.NewStore(log, db))
+
+	// Requests are authorized in process with the same policy the auth
+	// service uses, rather than calling the auth service every time.
+	authz, err := auth.NewAuthorizer(auth.AuthorizerConfig{
+		Log:      log,
+		DB:       db,
+		CacheTTL: authorizeCacheTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("authorizer: %w", err)
+	}
 
 	s := Service{
 		log:   log,
-		mtrcs: newMetrics(),
+		mtrcs: mtrcs,
 		db:    db,
 		debug: debug.Mux(),
+		authz: authz,
 		appDomain: appDomain{
+			auditApp:    auditapp.NewApp(auditBus),
+			delegateApp: delegateapp.NewApp(delegate),
 			userApp:     userapp.NewApp(userBus),
 			productApp:  productapp.NewApp(productBus),
+			roleApp:     roleapp.NewApp(roleBus),
 			homeApp:     homeapp.NewApp(homeBus),
+			orderApp:    orderapp.NewApp(orderBus),
+			orgApp:      orgapp.NewApp(orgBus, userBus),
 			tranApp:     tranapp.NewApp(userBus, productBus),
 			vproductApp: vproductapp.NewApp(vproductBus),
 		},
 		busDomain: busDomain{
+			auditBus:   auditBus,
 			delegate:   delegate,
+			outbox:     outbox,
 			userBus:    userBus,
 			productBus: productBus,
+			roleBus:    roleBus,
+			tokenBus:   tokenBus,
 			homeBus:    homeBus,
+			orderBus:   orderBus,
+			orgBus:     orgBus,
 		},
 	}
 
@@ -124,7 +124,9 @@ func Token(db *dbtest.Database, ath *auth.Auth, email string) string {
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: userbus.ParseRolesToString(dbUsr.Roles),
+		Roles:         userbus.ParseRolesToString(dbUsr.Roles),
+		Tenant:        dbUsr.TenantID.String(),
+		EmailVerified: dbUsr.EmailVerified,
 	}
 
 	token, err := ath.GenerateToken(kid, claims)
@@ -3,6 +3,11 @@ package apitest
 // KeyStore represents a mock keystore with a hardcoded key.
 type KeyStore struct{}
 
+// ActiveKID implements the auth interface.
+func (ks *KeyStore) ActiveKID() string {
+	return kid
+}
+
 // PrivateKey implements the auth interface.
 func (ks *KeyStore) PrivateKey(kid string) (string, error) {
 	return privateKeyPEM, nil
@@ -13,6 +18,11 @@ func (ks *KeyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+// PublicKeys implements the auth interface.
+func (ks *KeyStore) PublicKeys() map[string]string {
+	return map[string]string{kid: publicKeyPEM}
+}
+
 const (
 	kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ"
 
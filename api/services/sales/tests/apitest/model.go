@@ -6,6 +6,7 @@ import (
 	eauth "encore.dev/beta/auth"
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
@@ -15,6 +16,7 @@ type User struct {
 	userbus.User
 	Products []productbus.Product
 	Homes    []homebus.Home
+	Orders   []orderbus.Order
 	Token    string
 }
 
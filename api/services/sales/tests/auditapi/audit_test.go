@@ -0,0 +1,23 @@
+package audit_test
+
+import (
+	"testing"
+)
+
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+
+	test := startTest(t)
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, queryOk(sd), "query-ok")
+	test.Run(t, queryAuth(sd), "query-auth")
+}
@@ -0,0 +1,21 @@
+package audit_test
+
+import (
+	"github.com/ardanlabs/encore/app/domain/auditapp"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/uuid"
+)
+
+func toAppAudits(actorID uuid.UUID, prds []productbus.Product) []auditapp.Audit {
+	items := make([]auditapp.Audit, len(prds))
+	for i, prd := range prds {
+		items[i] = auditapp.Audit{
+			ActorID:  actorID.String(),
+			Entity:   "PRODUCT",
+			EntityID: prd.ID.String(),
+			Action:   "CREATE",
+		}
+	}
+
+	return items
+}
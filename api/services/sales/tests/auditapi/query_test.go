@@ -0,0 +1,122 @@
+package audit_test
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/auditapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/go-cmp/cmp"
+)
+
+func queryOk(sd apitest.SeedData) []apitest.Table {
+	prds := make([]productbus.Product, len(sd.Users[0].Products))
+	copy(prds, sd.Users[0].Products)
+
+	sort.Slice(prds, func(i, j int) bool {
+		return prds[i].ID.String() <= prds[j].ID.String()
+	})
+
+	table := []apitest.Table{
+		{
+			Name:  "entity",
+			Token: sd.Admins[0].Token,
+			ExpResp: query.Result[auditapp.Audit]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       len(prds),
+				Items:       toAppAudits(sd.Users[0].ID, prds),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				qp := auditapp.QueryParams{
+					Page:    "1",
+					Rows:    "10",
+					OrderBy: "entity_id,ASC",
+					ActorID: sd.Users[0].ID.String(),
+					Entity:  "PRODUCT",
+					Action:  "CREATE",
+				}
+
+				resp, err := sales.AuditQuery(ctx, qp)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(query.Result[auditapp.Audit])
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(query.Result[auditapp.Audit])
+
+				if len(gotResp.Items) == len(expResp.Items) {
+					for i := range gotResp.Items {
+						expResp.Items[i].ID = gotResp.Items[i].ID
+						expResp.Items[i].Diff = gotResp.Items[i].Diff
+						expResp.Items[i].DateCreated = gotResp.Items[i].DateCreated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "emptytoken",
+			Token:   "&nbsp;",
+			ExpResp: errs.Newf(errs.Unauthenticated, "error parsing token: token contains an invalid number of segments"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.AuditQuery(ctx, auditapp.QueryParams{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "sig",
+			Token:   sd.Admins[0].Token + "A",
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.AuditQuery(ctx, auditapp.QueryParams{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "wronguser",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_only]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.AuditQuery(ctx, auditapp.QueryParams{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
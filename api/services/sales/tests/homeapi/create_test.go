@@ -7,6 +7,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -25,6 +26,8 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Status: "ACTIVE",
+				ETag:   etag.Format(1),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := homeapp.NewHome{
@@ -30,5 +30,10 @@ func Test_Home(t *testing.T) {
 	test.Run(t, updateAuth(sd), "update-auth")
 
 	test.Run(t, deleteOk(sd), "delete-ok")
+	test.Run(t, deleteBad(sd), "delete-bad")
 	test.Run(t, deleteAuth(sd), "delete-auth")
+
+	test.Run(t, restoreOk(sd), "restore-ok")
+	test.Run(t, restoreBad(sd), "restore-bad")
+	test.Run(t, restoreAuth(sd), "restore-auth")
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/domain/homeapp"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/domain/homebus"
 )
 
@@ -20,8 +21,10 @@ func toAppHome(hme homebus.Home) homeapp.Home {
 			State:    hme.Address.State,
 			Country:  hme.Address.Country,
 		},
+		Status:      hme.Status.String(),
 		DateCreated: hme.DateCreated.Format(time.RFC3339),
 		DateUpdated: hme.DateUpdated.Format(time.RFC3339),
+		ETag:        etag.Format(hme.Version),
 	}
 }
 
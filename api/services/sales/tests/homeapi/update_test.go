@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/homeapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -29,8 +30,10 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Status:      "ACTIVE",
 				DateCreated: sd.Users[0].Homes[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].Homes[0].DateCreated.Format(time.RFC3339),
+				ETag:        etag.Format(2),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := homeapp.UpdateHome{
@@ -43,6 +46,7 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 						State:    dbtest.StringPointer("AL"),
 						Country:  dbtest.StringPointer("US"),
 					},
+					IfMatch: etag.Format(sd.Users[0].Homes[0].Version),
 				}
 
 				resp, err := sales.HomeUpdate(ctx, sd.Users[0].Homes[0].ID.String(), app)
@@ -108,6 +112,25 @@ func updateBad(sd apitest.SeedData) []apitest.Table {
 			},
 			CmpFunc: apitest.CmpAppErrors,
 		},
+		{
+			Name:    "ifmatch",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "if-match[\"1\"] etag[\"2\"]: resource was modified since it was read"),
+			ExcFunc: func(ctx context.Context) any {
+				app := homeapp.UpdateHome{
+					Type:    dbtest.StringPointer("CONDO"),
+					IfMatch: etag.Format(sd.Users[0].Homes[0].Version),
+				}
+
+				resp, err := sales.HomeUpdate(ctx, sd.Users[0].Homes[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
 	}
 
 	return table
@@ -0,0 +1,176 @@
+package order_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/orderapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func createOk(sd apitest.SeedData) []apitest.Table {
+	prd := sd.Users[0].Products[0]
+
+	table := []apitest.Table{
+		{
+			Name:  "basic",
+			Token: sd.Users[0].Token,
+			ExpResp: orderapp.Order{
+				UserID: sd.Users[0].ID.String(),
+				HomeID: sd.Users[0].Homes[0].ID.String(),
+				Items: []orderapp.Item{
+					{
+						ProductID: prd.ID.String(),
+						Quantity:  prd.Quantity,
+						Cost:      prd.Cost.Float64(),
+						Currency:  prd.Cost.Currency(),
+					},
+				},
+				Total:    prd.Cost.Mul(prd.Quantity).Float64(),
+				Currency: prd.Cost.Currency(),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := orderapp.NewOrder{
+					HomeID: sd.Users[0].Homes[0].ID.String(),
+					Items: []orderapp.NewItem{
+						{
+							ProductID: prd.ID.String(),
+							Quantity:  prd.Quantity,
+						},
+					},
+				}
+
+				resp, err := sales.OrderCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orderapp.Order)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(orderapp.Order)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func createBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "missing",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"homeID\",\"error\":\"homeID is a required field\"},{\"field\":\"items\",\"error\":\"items is a required field\"}]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderCreate(ctx, orderapp.NewOrder{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "stock",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "insufficient stock"),
+			ExcFunc: func(ctx context.Context) any {
+				prd := sd.Users[0].Products[1]
+
+				app := orderapp.NewOrder{
+					HomeID: sd.Users[0].Homes[0].ID.String(),
+					Items: []orderapp.NewItem{
+						{
+							ProductID: prd.ID.String(),
+							Quantity:  prd.Quantity + 1,
+						},
+					},
+				}
+
+				resp, err := sales.OrderCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "home",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "home does not belong to the buyer"),
+			ExcFunc: func(ctx context.Context) any {
+				app := orderapp.NewOrder{
+					HomeID: sd.Users[1].Homes[0].ID.String(),
+					Items: []orderapp.NewItem{
+						{
+							ProductID: sd.Users[0].Products[1].ID.String(),
+							Quantity:  1,
+						},
+					},
+				}
+
+				resp, err := sales.OrderCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func createAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "emptytoken",
+			Token:   "&nbsp;",
+			ExpResp: errs.Newf(errs.Unauthenticated, "error parsing token: token contains an invalid number of segments"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderCreate(ctx, orderapp.NewOrder{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "sig",
+			Token:   sd.Admins[0].Token + "A",
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderCreate(ctx, orderapp.NewOrder{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,42 @@
+package order_test
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/app/domain/orderapp"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+)
+
+func toAppOrder(ord orderbus.Order) orderapp.Order {
+	total := ord.Total()
+
+	items := make([]orderapp.Item, len(ord.Items))
+	for i, itm := range ord.Items {
+		items[i] = orderapp.Item{
+			ProductID: itm.ProductID.String(),
+			Quantity:  itm.Quantity,
+			Cost:      itm.Cost.Float64(),
+			Currency:  itm.Cost.Currency(),
+		}
+	}
+
+	return orderapp.Order{
+		ID:          ord.ID.String(),
+		UserID:      ord.UserID.String(),
+		HomeID:      ord.HomeID.String(),
+		Items:       items,
+		Total:       total.Float64(),
+		Currency:    total.Currency(),
+		DateCreated: ord.DateCreated.Format(time.RFC3339),
+		DateUpdated: ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []orderbus.Order) []orderapp.Order {
+	items := make([]orderapp.Order, len(ords))
+	for i, ord := range ords {
+		items[i] = toAppOrder(ord)
+	}
+
+	return items
+}
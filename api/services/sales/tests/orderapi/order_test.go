@@ -0,0 +1,28 @@
+package order_test
+
+import (
+	"testing"
+)
+
+func Test_Order(t *testing.T) {
+	t.Parallel()
+
+	test := startTest(t)
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, queryOk(sd), "query-ok")
+	test.Run(t, queryByIDOk(sd), "querybyid-ok")
+	test.Run(t, queryByIDAuth(sd), "querybyid-auth")
+
+	test.Run(t, createOk(sd), "create-ok")
+	test.Run(t, createBad(sd), "create-bad")
+	test.Run(t, createAuth(sd), "create-auth")
+}
@@ -0,0 +1,116 @@
+package order_test
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/orderapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/google/go-cmp/cmp"
+)
+
+func queryOk(sd apitest.SeedData) []apitest.Table {
+	ords := make([]orderbus.Order, 0, len(sd.Admins[0].Orders)+len(sd.Users[0].Orders))
+	ords = append(ords, sd.Admins[0].Orders...)
+	ords = append(ords, sd.Users[0].Orders...)
+
+	sort.Slice(ords, func(i, j int) bool {
+		return ords[i].ID.String() <= ords[j].ID.String()
+	})
+
+	table := []apitest.Table{
+		{
+			Name:  "all",
+			Token: sd.Admins[0].Token,
+			ExpResp: query.Result[orderapp.Order]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       len(ords),
+				Items:       toAppOrders(ords),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				qp := orderapp.QueryParams{
+					Page:    "1",
+					Rows:    "10",
+					OrderBy: "order_id,ASC",
+				}
+
+				resp, err := sales.OrderQuery(ctx, qp)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryByIDOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "byid",
+			Token:   sd.Users[0].Token,
+			ExpResp: toAppOrder(sd.Users[0].Orders[0]),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderQueryByID(ctx, sd.Users[0].Orders[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "admin",
+			Token:   sd.Admins[0].Token,
+			ExpResp: toAppOrder(sd.Users[0].Orders[0]),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderQueryByID(ctx, sd.Users[0].Orders[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryByIDAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "wronguser",
+			Token:   sd.Users[1].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_or_subject]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrderQueryByID(ctx, sd.Users[0].Orders[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
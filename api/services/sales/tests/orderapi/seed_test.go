@@ -0,0 +1,117 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+	busDomain := db.BusDomain
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestGenerateSeedProducts(ctx, 3, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	hmes, err := homebus.TestGenerateSeedHomes(ctx, 1, busDomain.Home, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding homes : %w", err)
+	}
+
+	// The first product is left untouched so the create tests can buy
+	// its entire stock.
+	ords, err := orderbus.TestGenerateSeedOrders(ctx, 1, busDomain.Order, usrs[0].ID, hmes[0].ID, prds[1:])
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding orders : %w", err)
+	}
+
+	for i := 1; i < len(prds); i++ {
+		prds[i].Quantity--
+		prds[i].Version++
+	}
+
+	tu1 := apitest.User{
+		User:     usrs[0],
+		Products: prds,
+		Homes:    hmes,
+		Orders:   ords,
+		Token:    apitest.Token(db, ath, usrs[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	hmes, err = homebus.TestGenerateSeedHomes(ctx, 1, busDomain.Home, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding homes : %w", err)
+	}
+
+	tu2 := apitest.User{
+		User:  usrs[0],
+		Homes: hmes,
+		Token: apitest.Token(db, ath, usrs[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err = productbus.TestGenerateSeedProducts(ctx, 2, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	hmes, err = homebus.TestGenerateSeedHomes(ctx, 1, busDomain.Home, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding homes : %w", err)
+	}
+
+	ords, err = orderbus.TestGenerateSeedOrders(ctx, 1, busDomain.Order, usrs[0].ID, hmes[0].ID, prds)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding orders : %w", err)
+	}
+
+	for i := range prds {
+		prds[i].Quantity--
+		prds[i].Version++
+	}
+
+	tu3 := apitest.User{
+		User:     usrs[0],
+		Products: prds,
+		Homes:    hmes,
+		Orders:   ords,
+		Token:    apitest.Token(db, ath, usrs[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := apitest.SeedData{
+		Users:  []apitest.User{tu1, tu2},
+		Admins: []apitest.User{tu3},
+	}
+
+	return sd, nil
+}
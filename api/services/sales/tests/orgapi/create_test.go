@@ -0,0 +1,137 @@
+package org_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/orgapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func createOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "basic",
+			Token: sd.Admins[0].Token,
+			ExpResp: orgapp.Organization{
+				Name: "Acme",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := orgapp.NewOrganization{
+					Name: "Acme",
+					Admin: orgapp.NewAdmin{
+						Name:            "Bill Kennedy",
+						Email:           "bill@acme.com",
+						Password:        "123",
+						PasswordConfirm: "123",
+					},
+				}
+
+				resp, err := sales.OrgCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orgapp.Organization)
+				if !exists {
+					return "error occurred"
+				}
+
+				if gotResp.AdminID == "" {
+					return "admin not created"
+				}
+
+				expResp := exp.(orgapp.Organization)
+				expResp.ID = gotResp.ID
+				expResp.AdminID = gotResp.AdminID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func createBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "missing-input",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"name\",\"error\":\"name is a required field\"},{\"field\":\"name\",\"error\":\"name is a required field\"},{\"field\":\"email\",\"error\":\"email is a required field\"},{\"field\":\"password\",\"error\":\"password is a required field\"}]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrgCreate(ctx, orgapp.NewOrganization{})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "duplicate",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.Aborted, "organization name is not unique"),
+			ExcFunc: func(ctx context.Context) any {
+				app := orgapp.NewOrganization{
+					Name: "Tenant",
+					Admin: orgapp.NewAdmin{
+						Name:            "Jill Kennedy",
+						Email:           "jill@tenant.com",
+						Password:        "123",
+						PasswordConfirm: "123",
+					},
+				}
+
+				resp, err := sales.OrgCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func createAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "tenant",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.PermissionDenied, "only the default organization can create organizations"),
+			ExcFunc: func(ctx context.Context) any {
+				app := orgapp.NewOrganization{
+					Name: "Other",
+					Admin: orgapp.NewAdmin{
+						Name:            "Jack Kennedy",
+						Email:           "jack@other.com",
+						Password:        "123",
+						PasswordConfirm: "123",
+					},
+				}
+
+				resp, err := sales.OrgCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
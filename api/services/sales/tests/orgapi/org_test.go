@@ -0,0 +1,29 @@
+package org_test
+
+import (
+	"testing"
+)
+
+func Test_Org(t *testing.T) {
+	t.Parallel()
+
+	test := startTest(t)
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, queryByIDOk(sd), "querybyid-ok")
+	test.Run(t, queryByIDBad(sd), "querybyid-bad")
+
+	test.Run(t, createOk(sd), "create-ok")
+	test.Run(t, createAuth(sd), "create-auth")
+	test.Run(t, createBad(sd), "create-bad")
+
+	test.Run(t, isolation(sd), "isolation")
+}
@@ -0,0 +1,138 @@
+package org_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/orgapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/google/go-cmp/cmp"
+)
+
+func queryByIDOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "own",
+			Token: sd.Admins[1].Token,
+			ExpResp: orgapp.Organization{
+				ID:   sd.Admins[1].TenantID.String(),
+				Name: "Tenant",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrgQueryByID(ctx, sd.Admins[1].TenantID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orgapp.Organization)
+				if !exists {
+					return "error occurred"
+				}
+
+				if _, err := time.Parse(time.RFC3339, gotResp.DateCreated); err != nil {
+					return err.Error()
+				}
+
+				expResp := exp.(orgapp.Organization)
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:  "default",
+			Token: sd.Admins[0].Token,
+			ExpResp: orgapp.Organization{
+				ID:   sd.Admins[1].TenantID.String(),
+				Name: "Tenant",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrgQueryByID(ctx, sd.Admins[1].TenantID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orgapp.Organization)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(orgapp.Organization)
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryByIDBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "other",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.NotFound, "organization not found"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.OrgQueryByID(ctx, orgbus.DefaultID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+// isolation checks the rows of one organization can't be reached by the
+// members of another.
+func isolation(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "product",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "query: productID[%s]: db: product not found", sd.Users[0].Products[0].ID),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductQueryByID(ctx, sd.Users[0].Products[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "user",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "query: userID[%s]: db: user not found", sd.Users[0].ID),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.UserQueryByID(ctx, sd.Users[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
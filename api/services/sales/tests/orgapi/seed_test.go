@@ -0,0 +1,76 @@
+package org_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+	busDomain := db.BusDomain
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestGenerateSeedProducts(ctx, 1, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	tu1 := apitest.User{
+		User:     usrs[0],
+		Products: prds,
+		Token:    apitest.Token(db, ath, usrs[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu2 := apitest.User{
+		User:  usrs[0],
+		Token: apitest.Token(db, ath, usrs[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	org, err := busDomain.Org.Create(ctx, orgbus.NewOrganization{Name: "Tenant"})
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding organization : %w", err)
+	}
+
+	nu := userbus.TestNewUsers(1, userbus.Roles.Admin)[0]
+	nu.TenantID = org.ID
+
+	usr, err := busDomain.User.Create(sqldb.WithTenant(ctx, org.ID), usrs[0].ID, nu)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu3 := apitest.User{
+		User:  usr,
+		Token: apitest.Token(db, ath, usr.Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := apitest.SeedData{
+		Users:  []apitest.User{tu1},
+		Admins: []apitest.User{tu2, tu3},
+	}
+
+	return sd, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/productapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -19,7 +20,10 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 				UserID:   sd.Users[0].ID.String(),
 				Name:     "Guitar",
 				Cost:     10.34,
+				Currency: "USD",
 				Quantity: 10,
+				Status:   "ACTIVE",
+				ETag:     etag.Format(1),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := productapp.NewProduct{
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 )
 
@@ -12,10 +13,13 @@ func toAppProduct(prd productbus.Product) productapp.Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
+		Cost:        prd.Cost.Float64(),
+		Currency:    prd.Cost.Currency(),
 		Quantity:    prd.Quantity,
+		Status:      prd.Status.String(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		ETag:        etag.Format(prd.Version),
 	}
 }
 
@@ -20,6 +20,8 @@ func Test_Product(t *testing.T) {
 
 	test.Run(t, queryOk(sd), "query-ok")
 	test.Run(t, queryByIDOk(sd), "querybyid-ok")
+	test.Run(t, searchOk(sd), "search-ok")
+	test.Run(t, searchBad(sd), "search-bad")
 
 	test.Run(t, createOk(sd), "create-ok")
 	test.Run(t, createBad(sd), "create-bad")
@@ -30,5 +32,10 @@ func Test_Product(t *testing.T) {
 	test.Run(t, updateAuth(sd), "update-auth")
 
 	test.Run(t, deleteOk(sd), "delete-ok")
+	test.Run(t, deleteBad(sd), "delete-bad")
 	test.Run(t, deleteAuth(sd), "delete-auth")
+
+	test.Run(t, restoreOk(sd), "restore-ok")
+	test.Run(t, restoreBad(sd), "restore-bad")
+	test.Run(t, restoreAuth(sd), "restore-auth")
 }
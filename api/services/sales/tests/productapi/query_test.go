@@ -2,13 +2,16 @@ package product_test
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"github.com/ardanlabs/encore/api/services/sales"
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -50,6 +53,44 @@ func queryOk(sd apitest.SeedData) []apitest.Table {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:  "cursor",
+			Token: sd.Admins[0].Token,
+			ExpResp: query.Result[productapp.Product]{
+				RowsPerPage: 2,
+				Items:       toAppProducts(prds),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				qp := productapp.QueryParams{
+					Rows:    "2",
+					Cursor:  page.CursorStart,
+					OrderBy: "product_id,ASC",
+					Name:    "Name",
+				}
+
+				var all query.Result[productapp.Product]
+				for {
+					resp, err := sales.ProductQuery(ctx, qp)
+					if err != nil {
+						return err
+					}
+
+					all.RowsPerPage = resp.RowsPerPage
+					all.Items = append(all.Items, resp.Items...)
+
+					if resp.NextCursor == "" {
+						break
+					}
+
+					qp.Cursor = resp.NextCursor
+				}
+
+				return all
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
@@ -77,3 +118,82 @@ func queryByIDOk(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func searchOk(sd apitest.SeedData) []apitest.Table {
+	prd := sd.Users[0].Products[0]
+
+	table := []apitest.Table{
+		{
+			Name:  "basic",
+			Token: sd.Users[0].Token,
+			ExpResp: query.Result[productapp.SearchResult]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       1,
+				Items: []productapp.SearchResult{
+					{
+						Product: toAppProduct(prd),
+						Snippet: fmt.Sprintf("<b>%s</b>", prd.Name),
+					},
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				sp := productapp.SearchParams{
+					Page: "1",
+					Rows: "10",
+					Q:    prd.Name.String(),
+				}
+
+				resp, err := sales.ProductSearch(ctx, sp)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(query.Result[productapp.SearchResult])
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(query.Result[productapp.SearchResult])
+
+				for i := range gotResp.Items {
+					if gotResp.Items[i].Rank <= 0 {
+						return fmt.Sprintf("expected a positive rank, got %v", gotResp.Items[i].Rank)
+					}
+
+					if i < len(expResp.Items) {
+						expResp.Items[i].Rank = gotResp.Items[i].Rank
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func searchBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "missing",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "search text is required"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductSearch(ctx, productapp.SearchParams{Q: "  "})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,128 @@
+package product_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/productapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func restoreOk(sd apitest.SeedData) []apitest.Table {
+	// The product was deleted and restored since it was seeded.
+	prd := sd.Users[0].Products[1]
+	prd.Version += 2
+
+	table := []apitest.Table{
+		{
+			Name:    "basic",
+			Token:   sd.Admins[0].Token,
+			ExpResp: toAppProduct(prd),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, sd.Users[0].Products[1].ID.String())
+				if err != nil {
+					return err
+				}
+
+				resp.DateUpdated = resp.DateCreated
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				if _, exists := got.(productapp.Product); !exists {
+					return "error occurred"
+				}
+
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restoreBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "notdeleted",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.NotFound, "query: productID[%s]: db: product not found", sd.Admins[0].Products[0].ID),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, sd.Admins[0].Products[0].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "badid",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "invalid UUID length: 3"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, "abc")
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func restoreAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "emptytoken",
+			Token:   "&nbsp;",
+			ExpResp: errs.Newf(errs.Unauthenticated, "error parsing token: token contains an invalid number of segments"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, "")
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "sig",
+			Token:   sd.Admins[0].Token + "A",
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, "")
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "wronguser",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_only]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.ProductRestore(ctx, sd.Users[0].Products[1].ID.String())
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
@@ -9,10 +9,11 @@ import (
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := sqldb.WithSystem(context.Background())
 	busDomain := db.BusDomain
 
 	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/productapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -22,15 +23,19 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 				UserID:      sd.Users[0].ID.String(),
 				Name:        "Guitar",
 				Cost:        10.34,
+				Currency:    "USD",
 				Quantity:    10,
+				Status:      "ACTIVE",
 				DateCreated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
+				ETag:        etag.Format(2),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := productapp.UpdateProduct{
 					Name:     dbtest.StringPointer("Guitar"),
 					Cost:     dbtest.FloatPointer(10.34),
 					Quantity: dbtest.IntPointer(10),
+					IfMatch:  etag.Format(sd.Users[0].Products[0].Version),
 				}
 
 				resp, err := sales.ProductUpdate(ctx, sd.Users[0].Products[0].ID.String(), app)
@@ -76,6 +81,43 @@ func updateBad(sd apitest.SeedData) []apitest.Table {
 			},
 			CmpFunc: apitest.CmpAppErrors,
 		},
+		{
+			Name:    "ifmatch",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "if-match[\"1\"] etag[\"2\"]: resource was modified since it was read"),
+			ExcFunc: func(ctx context.Context) any {
+				app := productapp.UpdateProduct{
+					Name:    dbtest.StringPointer("Stale"),
+					IfMatch: etag.Format(sd.Users[0].Products[0].Version),
+				}
+
+				resp, err := sales.ProductUpdate(ctx, sd.Users[0].Products[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "required",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "if-match header is required"),
+			ExcFunc: func(ctx context.Context) any {
+				app := productapp.UpdateProduct{
+					Name: dbtest.StringPointer("Unconditional"),
+				}
+
+				resp, err := sales.ProductUpdate(ctx, sd.Users[0].Products[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
 	}
 
 	return table
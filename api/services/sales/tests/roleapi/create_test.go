@@ -0,0 +1,150 @@
+package role_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/roleapp"
+	"github.com/ardanlabs/encore/app/domain/userapp"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func createOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "basic",
+			Token: sd.Admins[0].Token,
+			ExpResp: roleapp.Role{
+				Name:        "MANAGER",
+				Permissions: []string{"user"},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				app := roleapp.NewRole{
+					Name:        "MANAGER",
+					Permissions: []string{"user"},
+				}
+
+				resp, err := sales.RoleCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(roleapp.Role)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(roleapp.Role)
+				expResp.DateCreated = gotResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "assign",
+			Token:   sd.Admins[0].Token,
+			ExpResp: []string{"MANAGER"},
+			ExcFunc: func(ctx context.Context) any {
+				app := userapp.NewUser{
+					Name:            "Bill Kennedy",
+					Email:           "bill@ardanlabs.com",
+					Roles:           []string{"MANAGER"},
+					Password:        "123",
+					PasswordConfirm: "123",
+				}
+
+				resp, err := sales.UserCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp.Roles
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func createBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "duplicate",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.Aborted, "role name is not unique"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.RoleCreate(ctx, roleapp.NewRole{Name: "ADMIN"})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "permission",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.InvalidArgument, "parse permissions: invalid permission \"owner\""),
+			ExcFunc: func(ctx context.Context) any {
+				app := roleapp.NewRole{
+					Name:        "AUDITOR",
+					Permissions: []string{"owner"},
+				}
+
+				resp, err := sales.RoleCreate(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func createAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "wronguser",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_only]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.RoleCreate(ctx, roleapp.NewRole{Name: "AUDITOR"})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "tenant",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.PermissionDenied, "only the default organization can change roles and permissions"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.RoleCreate(ctx, roleapp.NewRole{Name: "AUDITOR"})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
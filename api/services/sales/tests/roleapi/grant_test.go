@@ -0,0 +1,100 @@
+package role_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/roleapp"
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func grantOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "basic",
+			Token:   sd.Admins[0].Token,
+			ExpResp: []string{"admin", "user"},
+			ExcFunc: func(ctx context.Context) any {
+				app := roleapp.GrantPermission{
+					Permission: "admin",
+				}
+
+				resp, err := sales.RoleGrant(ctx, "MANAGER", app)
+				if err != nil {
+					return err
+				}
+
+				return resp.Permissions
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func grantBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "notfound",
+			Token:   sd.Admins[0].Token,
+			ExpResp: errs.Newf(errs.NotFound, "query: name[AUDITOR]: db: role not found"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.RoleGrant(ctx, "AUDITOR", roleapp.GrantPermission{Permission: "user"})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func grantAuth(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "tenant",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.PermissionDenied, "only the default organization can change roles and permissions"),
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := sales.RoleGrant(ctx, "USER", roleapp.GrantPermission{Permission: "admin"})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+		{
+			Name:    "tenantrule",
+			Token:   sd.Admins[1].Token,
+			ExpResp: errs.Newf(errs.PermissionDenied, "only the default organization can change roles and permissions"),
+			ExcFunc: func(ctx context.Context) any {
+				app := roleapp.GrantRule{
+					Permission: "user",
+					Scope:      "all",
+				}
+
+				resp, err := sales.RuleGrant(ctx, auth.RuleAdminOnly, app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
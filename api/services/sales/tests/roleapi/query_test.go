@@ -0,0 +1,60 @@
+package role_test
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/api/services/sales"
+	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
+	"github.com/ardanlabs/encore/app/domain/roleapp"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/google/go-cmp/cmp"
+)
+
+func queryOk(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:  "basic",
+			Token: sd.Admins[0].Token,
+			ExpResp: query.Result[roleapp.Role]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       2,
+				Items: []roleapp.Role{
+					{Name: "ADMIN", Permissions: []string{"admin"}},
+					{Name: "USER", Permissions: []string{"user"}},
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				qp := roleapp.QueryParams{
+					Page: "1",
+					Rows: "10",
+				}
+
+				resp, err := sales.RoleQuery(ctx, qp)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(query.Result[roleapp.Role])
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(query.Result[roleapp.Role])
+
+				for i := range gotResp.Items {
+					if i < len(expResp.Items) {
+						expResp.Items[i].DateCreated = gotResp.Items[i].DateCreated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
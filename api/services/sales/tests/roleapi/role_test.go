@@ -0,0 +1,30 @@
+package role_test
+
+import (
+	"testing"
+)
+
+func Test_Role(t *testing.T) {
+	t.Parallel()
+
+	test := startTest(t)
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, queryOk(sd), "query-ok")
+
+	test.Run(t, createOk(sd), "create-ok")
+	test.Run(t, createAuth(sd), "create-auth")
+	test.Run(t, createBad(sd), "create-bad")
+
+	test.Run(t, grantOk(sd), "grant-ok")
+	test.Run(t, grantBad(sd), "grant-bad")
+	test.Run(t, grantAuth(sd), "grant-auth")
+}
@@ -17,6 +17,7 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 			ExpResp: tranapp.Product{
 				Name:     "Guitar",
 				Cost:     10.34,
+				Currency: "USD",
 				Quantity: 10,
 			},
 			ExcFunc: func(ctx context.Context) any {
@@ -7,6 +7,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -21,6 +22,7 @@ func createOk(sd apitest.SeedData) []apitest.Table {
 				Roles:      []string{"ADMIN"},
 				Department: "IT",
 				Enabled:    true,
+				ETag:       etag.Format(1),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := userapp.NewUser{
@@ -6,6 +6,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales"
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -16,7 +17,7 @@ func deleteOk(sd apitest.SeedData) []apitest.Table {
 			Token:   sd.Users[1].Token,
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := sales.UserDelete(ctx, sd.Users[1].ID.String()); err != nil {
+				if err := sales.UserDelete(ctx, sd.Users[1].ID.String(), etag.Precondition{IfMatch: etag.Format(sd.Users[1].Version)}); err != nil {
 					return err
 				}
 
@@ -31,7 +32,7 @@ func deleteOk(sd apitest.SeedData) []apitest.Table {
 			Token:   sd.Admins[1].Token,
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := sales.UserDelete(ctx, sd.Admins[1].ID.String()); err != nil {
+				if err := sales.UserDelete(ctx, sd.Admins[1].ID.String(), etag.Precondition{IfMatch: etag.Format(sd.Admins[1].Version)}); err != nil {
 					return err
 				}
 
@@ -53,7 +54,7 @@ func deleteAuth(sd apitest.SeedData) []apitest.Table {
 			Token:   "&nbsp;",
 			ExpResp: errs.Newf(errs.Unauthenticated, "error parsing token: token contains an invalid number of segments"),
 			ExcFunc: func(ctx context.Context) any {
-				err := sales.UserDelete(ctx, "")
+				err := sales.UserDelete(ctx, "", etag.Precondition{})
 				if err != nil {
 					return err
 				}
@@ -67,7 +68,7 @@ func deleteAuth(sd apitest.SeedData) []apitest.Table {
 			Token:   sd.Users[0].Token + "A",
 			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
 			ExcFunc: func(ctx context.Context) any {
-				err := sales.UserDelete(ctx, "")
+				err := sales.UserDelete(ctx, "", etag.Precondition{})
 				if err != nil {
 					return err
 				}
@@ -81,7 +82,32 @@ func deleteAuth(sd apitest.SeedData) []apitest.Table {
 			Token:   sd.Users[2].Token,
 			ExpResp: errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_or_subject]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
 			ExcFunc: func(ctx context.Context) any {
-				err := sales.UserDelete(ctx, sd.Users[0].ID.String())
+				err := sales.UserDelete(ctx, sd.Users[0].ID.String(), etag.Precondition{})
+				if err != nil {
+					return err
+				}
+
+				return nil
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
+	}
+
+	return table
+}
+
+func deleteBad(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:    "ifmatch",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "if-match[\"1\"] etag[\"2\"]: resource was modified since it was read"),
+			ExcFunc: func(ctx context.Context) any {
+				pc := etag.Precondition{
+					IfMatch: etag.Format(sd.Users[0].Version),
+				}
+
+				err := sales.UserDelete(ctx, sd.Users[0].ID.String(), pc)
 				if err != nil {
 					return err
 				}
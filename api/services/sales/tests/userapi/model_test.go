@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/domain/userapp"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
 
@@ -14,15 +15,17 @@ func toAppUser(usr userbus.User) userapp.User {
 	}
 
 	return userapp.User{
-		ID:           usr.ID.String(),
-		Name:         usr.Name.String(),
-		Email:        usr.Email.Address,
-		Roles:        roles,
-		PasswordHash: nil,
-		Department:   usr.Department,
-		Enabled:      usr.Enabled,
-		DateCreated:  usr.DateCreated.Format(time.RFC3339),
-		DateUpdated:  usr.DateUpdated.Format(time.RFC3339),
+		ID:            usr.ID.String(),
+		Name:          usr.Name.String(),
+		Email:         usr.Email.Address,
+		Roles:         roles,
+		PasswordHash:  nil,
+		Department:    usr.Department,
+		Enabled:       usr.Enabled,
+		EmailVerified: usr.EmailVerified,
+		DateCreated:   usr.DateCreated.Format(time.RFC3339),
+		DateUpdated:   usr.DateUpdated.Format(time.RFC3339),
+		ETag:          etag.Format(usr.Version),
 	}
 }
 
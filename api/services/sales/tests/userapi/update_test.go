@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/encore/api/services/sales/tests/apitest"
 	"github.com/ardanlabs/encore/app/domain/userapp"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -26,6 +27,7 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 				Enabled:     true,
 				DateCreated: sd.Users[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].DateCreated.Format(time.RFC3339),
+				ETag:        etag.Format(2),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				app := userapp.UpdateUser{
@@ -34,6 +36,7 @@ func updateOk(sd apitest.SeedData) []apitest.Table {
 					Department:      dbtest.StringPointer("IT"),
 					Password:        dbtest.StringPointer("123"),
 					PasswordConfirm: dbtest.StringPointer("123"),
+					IfMatch:         etag.Format(sd.Users[0].Version),
 				}
 
 				resp, err := sales.UserUpdate(ctx, sd.Users[0].ID.String(), app)
@@ -93,6 +96,25 @@ func updateBad(sd apitest.SeedData) []apitest.Table {
 			},
 			CmpFunc: apitest.CmpAppErrors,
 		},
+		{
+			Name:    "ifmatch",
+			Token:   sd.Users[0].Token,
+			ExpResp: errs.Newf(errs.FailedPrecondition, "if-match[\"1\"] etag[\"2\"]: resource was modified since it was read"),
+			ExcFunc: func(ctx context.Context) any {
+				app := userapp.UpdateUser{
+					Name:    dbtest.StringPointer("Stale Kennedy"),
+					IfMatch: etag.Format(sd.Users[0].Version),
+				}
+
+				resp, err := sales.UserUpdate(ctx, sd.Users[0].ID.String(), app)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: apitest.CmpAppErrors,
+		},
 	}
 
 	return table
@@ -30,5 +30,10 @@ func Test_User(t *testing.T) {
 	test.Run(t, updateBad(sd), "update-bad")
 
 	test.Run(t, deleteOk(sd), "delete-ok")
+	test.Run(t, deleteBad(sd), "delete-bad")
 	test.Run(t, deleteAuth(sd), "delete-auth")
+
+	test.Run(t, restoreOk(sd), "restore-ok")
+	test.Run(t, restoreBad(sd), "restore-bad")
+	test.Run(t, restoreAuth(sd), "restore-auth")
 }
@@ -13,8 +13,10 @@ func toAppVProduct(usr userbus.User, prd productbus.Product) vproductapp.Product
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
+		Cost:        prd.Cost.Float64(),
+		Currency:    prd.Cost.Currency(),
 		Quantity:    prd.Quantity,
+		Status:      prd.Status.String(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
 		UserName:    usr.Name.String(),
@@ -0,0 +1,113 @@
+// Package accountapp maintains the app layer api for resetting passwords and
+// verifying email addresses.
+package accountapp
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the account domain.
+type App struct {
+	accountBus *accountbus.Business
+	userBus    *userbus.Business
+	tokenBus   *tokenbus.Business
+}
+
+// NewApp constructs an account app API for use.
+func NewApp(accountBus *accountbus.Business, userBus *userbus.Business, tokenBus *tokenbus.Business) *App {
+	return &App{
+		accountBus: accountBus,
+		userBus:    userBus,
+		tokenBus:   tokenBus,
+	}
+}
+
+// RequestPasswordReset mails a password reset token to the email address.
+// The call succeeds whether or not a user has the address.
+func (a *App) RequestPasswordReset(ctx context.Context, app PasswordReset) error {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.accountBus.RequestPasswordReset(ctx, *addr); err != nil {
+		return errs.Newf(errs.Internal, "requestpasswordreset: %s", err)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordReset replaces the password of the user the token was
+// mailed to. Every refresh token of the user is revoked so other sessions
+// have to sign in with the new password.
+func (a *App) ConfirmPasswordReset(ctx context.Context, app ConfirmPasswordReset) error {
+	usr, err := a.accountBus.ResetPassword(ctx, app.Token, app.Password)
+	if err != nil {
+		return tokenError("resetpassword", err)
+	}
+
+	if err := a.tokenBus.RevokeByUserID(ctx, usr.ID); err != nil {
+		return errs.Newf(errs.Internal, "revokebyuserid: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+// RequestVerification mails an email verification token to the user.
+func (a *App) RequestVerification(ctx context.Context, userID uuid.UUID) error {
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if err := a.accountBus.RequestVerification(ctx, usr); err != nil {
+		if errors.Is(err, accountbus.ErrAlreadyVerified) {
+			return errs.New(errs.FailedPrecondition, accountbus.ErrAlreadyVerified)
+		}
+		return errs.Newf(errs.Internal, "requestverification: userID[%s]: %s", userID, err)
+	}
+
+	return nil
+}
+
+// ConfirmVerification marks the email address of the user the token was
+// mailed to as verified.
+func (a *App) ConfirmVerification(ctx context.Context, app ConfirmVerification) error {
+	if _, err := a.accountBus.VerifyEmail(ctx, app.Token); err != nil {
+		return tokenError("verifyemail", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// tokenErrors are the errors of using a token the caller is told about.
+var tokenErrors = []error{
+	accountbus.ErrNotFound,
+	accountbus.ErrExpired,
+	accountbus.ErrUsed,
+	accountbus.ErrEmailChanged,
+}
+
+// tokenError maps the errors of using a token to the errors the caller sees.
+func tokenError(op string, err error) error {
+	for _, tknErr := range tokenErrors {
+		if errors.Is(err, tknErr) {
+			return errs.New(errs.InvalidArgument, tknErr)
+		}
+	}
+
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
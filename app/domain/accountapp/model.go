@@ -0,0 +1,51 @@
+package accountapp
+
+import (
+	"github.com/ardanlabs/encore/app/sdk/errs"
+)
+
+// PasswordReset defines the email address to mail a password reset token to.
+type PasswordReset struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app PasswordReset) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordReset defines the password reset token that was mailed to
+// the user and the new password.
+type ConfirmPasswordReset struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app ConfirmPasswordReset) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// ConfirmVerification defines the email verification token that was mailed
+// to the user.
+type ConfirmVerification struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app ConfirmVerification) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
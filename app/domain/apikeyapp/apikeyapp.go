@@ -0,0 +1,104 @@
+// Package apikeyapp maintains the app layer api for the api keys of a user.
+package apikeyapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the api key domain.
+type App struct {
+	apiKeyBus *apikeybus.Business
+}
+
+// NewApp constructs an api key app API for use.
+func NewApp(apiKeyBus *apikeybus.Business) *App {
+	return &App{
+		apiKeyBus: apiKeyBus,
+	}
+}
+
+// Create mints a new api key for the user.
+func (a *App) Create(ctx context.Context, userID uuid.UUID, app NewAPIKey) (MintedAPIKey, error) {
+	nak, err := toBusNewAPIKey(userID, app)
+	if err != nil {
+		return MintedAPIKey{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	m, err := a.apiKeyBus.Create(ctx, nak)
+	if err != nil {
+		switch {
+		case errors.Is(err, apikeybus.ErrInvalidScope):
+			return MintedAPIKey{}, errs.New(errs.InvalidArgument, err)
+
+		case errors.Is(err, apikeybus.ErrDuplicateName):
+			return MintedAPIKey{}, errs.New(errs.AlreadyExists, err)
+		}
+		return MintedAPIKey{}, errs.Newf(errs.Internal, "create: ak[%+v]: %s", nak, err)
+	}
+
+	return toAppMintedAPIKey(m), nil
+}
+
+// Revoke revokes an api key of the user.
+func (a *App) Revoke(ctx context.Context, userID uuid.UUID, keyID string) error {
+	ak, err := a.queryByID(ctx, userID, keyID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.apiKeyBus.Revoke(ctx, ak); err != nil {
+		return errs.Newf(errs.Internal, "revoke: keyID[%s]: %s", ak.ID, err)
+	}
+
+	return nil
+}
+
+// Query returns the api keys of the user.
+func (a *App) Query(ctx context.Context, userID uuid.UUID) (APIKeys, error) {
+	aks, err := a.apiKeyBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return APIKeys{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	return toAppAPIKeys(aks), nil
+}
+
+// QueryByID returns an api key of the user.
+func (a *App) QueryByID(ctx context.Context, userID uuid.UUID, keyID string) (APIKey, error) {
+	ak, err := a.queryByID(ctx, userID, keyID)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return toAppAPIKey(ak), nil
+}
+
+// =============================================================================
+
+// queryByID finds the api key and checks it belongs to the user. The api key
+// of another user isn't found so the caller can't learn it exists.
+func (a *App) queryByID(ctx context.Context, userID uuid.UUID, keyID string) (apikeybus.APIKey, error) {
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return apikeybus.APIKey{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	ak, err := a.apiKeyBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apikeybus.ErrNotFound) {
+			return apikeybus.APIKey{}, errs.New(errs.NotFound, err)
+		}
+		return apikeybus.APIKey{}, errs.Newf(errs.Internal, "querybyid: keyID[%s]: %s", id, err)
+	}
+
+	if ak.UserID != userID {
+		return apikeybus.APIKey{}, errs.New(errs.NotFound, apikeybus.ErrNotFound)
+	}
+
+	return ak, nil
+}
@@ -0,0 +1,141 @@
+package apikeyapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/google/uuid"
+)
+
+// APIKey represents information about an api key. The key itself is only
+// returned when the api key is minted.
+type APIKey struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Prefix       string   `json:"prefix"`
+	Scopes       []string `json:"scopes"`
+	RequestCount int      `json:"requestCount"`
+	DateExpires  string   `json:"dateExpires"`
+	DateCreated  string   `json:"dateCreated"`
+	DateLastUsed string   `json:"dateLastUsed,omitempty"`
+	DateRevoked  string   `json:"dateRevoked,omitempty"`
+}
+
+// Encode implments the encoder interface.
+func (app APIKey) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppAPIKey(ak apikeybus.APIKey) APIKey {
+	app := APIKey{
+		ID:           ak.ID.String(),
+		Name:         ak.Name,
+		Prefix:       ak.Prefix,
+		Scopes:       userbus.ParseRolesToString(ak.Scopes),
+		RequestCount: ak.RequestCount,
+		DateExpires:  ak.DateExpires.Format(time.RFC3339),
+		DateCreated:  ak.DateCreated.Format(time.RFC3339),
+	}
+
+	if !ak.DateLastUsed.IsZero() {
+		app.DateLastUsed = ak.DateLastUsed.Format(time.RFC3339)
+	}
+
+	if !ak.DateRevoked.IsZero() {
+		app.DateRevoked = ak.DateRevoked.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+// APIKeys represents the api keys of a user.
+type APIKeys struct {
+	Items []APIKey `json:"items"`
+}
+
+// Encode implments the encoder interface.
+func (app APIKeys) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppAPIKeys(aks []apikeybus.APIKey) APIKeys {
+	app := APIKeys{
+		Items: make([]APIKey, len(aks)),
+	}
+
+	for i, ak := range aks {
+		app.Items[i] = toAppAPIKey(ak)
+	}
+
+	return app
+}
+
+// MintedAPIKey represents an api key that was minted along with the key the
+// client has to keep. The key can't be recovered later.
+type MintedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Encode implments the encoder interface.
+func (app MintedAPIKey) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMintedAPIKey(m apikeybus.Minted) MintedAPIKey {
+	return MintedAPIKey{
+		APIKey: toAppAPIKey(m.APIKey),
+		Key:    m.Key,
+	}
+}
+
+// =============================================================================
+
+// NewAPIKey defines the data needed to mint a new api key. The scopes are
+// the roles the key acts with.
+type NewAPIKey struct {
+	Name        string   `json:"name" validate:"required"`
+	Scopes      []string `json:"scopes" validate:"required,min=1"`
+	DateExpires string   `json:"dateExpires" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewAPIKey) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewAPIKey(userID uuid.UUID, app NewAPIKey) (apikeybus.NewAPIKey, error) {
+	scopes, err := userbus.ParseRoles(app.Scopes)
+	if err != nil {
+		return apikeybus.NewAPIKey{}, fmt.Errorf("parse scopes: %w", err)
+	}
+
+	expires, err := time.Parse(time.RFC3339, app.DateExpires)
+	if err != nil {
+		return apikeybus.NewAPIKey{}, fmt.Errorf("parse dateExpires: %w", err)
+	}
+
+	if !expires.After(time.Now()) {
+		return apikeybus.NewAPIKey{}, fmt.Errorf("dateExpires must be in the future")
+	}
+
+	bus := apikeybus.NewAPIKey{
+		UserID:      userID,
+		Name:        app.Name,
+		Scopes:      scopes,
+		DateExpires: expires,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,58 @@
+// Package auditapp maintains the app layer api for the audit domain.
+package auditapp
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+// App manages the set of app layer api functions for the audit domain.
+type App struct {
+	auditBus *auditbus.Business
+}
+
+// NewApp constructs an audit app API for use.
+func NewApp(auditBus *auditbus.Business) *App {
+	return &App{
+		auditBus: auditBus,
+	}
+}
+
+// Query returns a list of audit records with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Audit], error) {
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	if err != nil {
+		return query.Result[Audit]{}, err
+	}
+
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
+	if err != nil {
+		return query.Result[Audit]{}, err
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return query.Result[Audit]{}, err
+	}
+
+	adts, err := a.auditBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return query.Result[Audit]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	if page.IsCursor() {
+		next := query.NextCursor(adts, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppAudits(adts), page, next), nil
+	}
+
+	total, err := a.auditBus.Count(ctx, filter)
+	if err != nil {
+		return query.Result[Audit]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppAudits(adts), total, page), nil
+}
@@ -0,0 +1,63 @@
+package auditapp
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+func parseFilter(qp QueryParams) (auditbus.QueryFilter, error) {
+	var filter auditbus.QueryFilter
+
+	if qp.ActorID != "" {
+		id, err := uuid.Parse(qp.ActorID)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("actor_id", err)
+		}
+		filter.ActorID = &id
+	}
+
+	if qp.Entity != "" {
+		entity, err := auditbus.ParseEntity(qp.Entity)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("entity", err)
+		}
+		filter.Entity = &entity
+	}
+
+	if qp.EntityID != "" {
+		id, err := uuid.Parse(qp.EntityID)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("entity_id", err)
+		}
+		filter.EntityID = &id
+	}
+
+	if qp.Action != "" {
+		action, err := auditbus.ParseAction(qp.Action)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("action", err)
+		}
+		filter.Action = &action
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("start_created_date", err)
+		}
+		filter.StartCreatedDate = &t
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		if err != nil {
+			return auditbus.QueryFilter{}, errs.NewFieldsError("end_created_date", err)
+		}
+		filter.EndCreatedDate = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,62 @@
+package auditapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page             string
+	Rows             string
+	Cursor           string
+	OrderBy          string
+	ActorID          string
+	Entity           string
+	EntityID         string
+	Action           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+// =============================================================================
+
+// Audit represents information about an individual change to an entity.
+type Audit struct {
+	ID          string          `json:"id"`
+	ActorID     string          `json:"actorID"`
+	Entity      string          `json:"entity"`
+	EntityID    string          `json:"entityID"`
+	Action      string          `json:"action"`
+	Diff        json.RawMessage `json:"diff"`
+	DateCreated string          `json:"dateCreated"`
+}
+
+// Encode implments the encoder interface.
+func (app Audit) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppAudit(adt auditbus.Audit) Audit {
+	return Audit{
+		ID:          adt.ID.String(),
+		ActorID:     adt.ActorID.String(),
+		Entity:      adt.Entity.String(),
+		EntityID:    adt.EntityID.String(),
+		Action:      adt.Action.String(),
+		Diff:        adt.Diff,
+		DateCreated: adt.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppAudits(adts []auditbus.Audit) []Audit {
+	app := make([]Audit, len(adts))
+	for i, adt := range adts {
+		app[i] = toAppAudit(adt)
+	}
+
+	return app
+}
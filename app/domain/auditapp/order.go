@@ -0,0 +1,38 @@
+package auditapp
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+var defaultOrderBy = order.NewBy("date_created", order.DESC)
+
+var orderByFields = map[string]string{
+	"audit_id":     auditbus.OrderByID,
+	"actor_id":     auditbus.OrderByActorID,
+	"entity":       auditbus.OrderByEntity,
+	"entity_id":    auditbus.OrderByEntityID,
+	"action":       auditbus.OrderByAction,
+	"date_created": auditbus.OrderByDateCreated,
+}
+
+// cursorKey returns the value of the order by field and the id of the
+// audit for constructing a page cursor.
+func cursorKey(adt auditbus.Audit, field string) (string, string) {
+	switch field {
+	case auditbus.OrderByActorID:
+		return adt.ActorID.String(), adt.ID.String()
+	case auditbus.OrderByEntity:
+		return adt.Entity.String(), adt.ID.String()
+	case auditbus.OrderByEntityID:
+		return adt.EntityID.String(), adt.ID.String()
+	case auditbus.OrderByAction:
+		return adt.Action.String(), adt.ID.String()
+	case auditbus.OrderByDateCreated:
+		return adt.DateCreated.UTC().Format(time.RFC3339Nano), adt.ID.String()
+	}
+
+	return adt.ID.String(), adt.ID.String()
+}
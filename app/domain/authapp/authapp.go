@@ -0,0 +1,174 @@
+// Package authapp maintains the app layer api for signing in with access and
+// refresh tokens.
+package authapp
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// Access tokens are short lived since they are only checked against the
+// denylist. Refresh tokens are rotated on every use.
+const (
+	accessTTL  = 15 * time.Minute
+	refreshTTL = 30 * 24 * time.Hour
+)
+
+// errAuthentication is returned for any failure to sign in so the caller
+// can't learn which accounts exist.
+var errAuthentication = errors.New("authentication failed")
+
+// App manages the set of app layer api functions for signing in.
+type App struct {
+	auth       *auth.Auth
+	userBus    *userbus.Business
+	tokenBus   *tokenbus.Business
+	lockoutBus *lockoutbus.Business
+}
+
+// NewApp constructs an auth app API for use.
+func NewApp(ath *auth.Auth, userBus *userbus.Business, tokenBus *tokenbus.Business, lockoutBus *lockoutbus.Business) *App {
+	return &App{
+		auth:       ath,
+		userBus:    userBus,
+		tokenBus:   tokenBus,
+		lockoutBus: lockoutBus,
+	}
+}
+
+// Login authenticates the user with their credentials and returns a new
+// access token along with a refresh token that starts a new family. The
+// email and the client ip are locked out after too many failed attempts.
+func (a *App) Login(ctx context.Context, app Login) (Token, error) {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return Token{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.lockoutBus.Authenticate(ctx, *addr, mid.ClientIP(app.ForwardedFor), app.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, lockoutbus.ErrLocked):
+			return Token{}, errs.New(errs.ResourceExhausted, lockoutbus.ErrLocked)
+
+		case errors.Is(err, userbus.ErrNotFound), errors.Is(err, userbus.ErrAuthenticationFailure):
+			return Token{}, errs.New(errs.Unauthenticated, errAuthentication)
+		}
+		return Token{}, errs.Newf(errs.Internal, "authenticate: %s", err)
+	}
+
+	if !usr.Enabled {
+		return Token{}, errs.New(errs.Unauthenticated, errAuthentication)
+	}
+
+	iss, err := a.tokenBus.Issue(ctx, usr.ID, refreshTTL)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "issue: %s", err)
+	}
+
+	return a.token(usr, iss)
+}
+
+// Refresh exchanges the refresh token for a new access token and a new
+// refresh token. Presenting a refresh token that was already exchanged
+// revokes every token in its family.
+func (a *App) Refresh(ctx context.Context, app Refresh) (Token, error) {
+	iss, err := a.tokenBus.Rotate(ctx, app.RefreshToken, refreshTTL)
+	if err != nil {
+		switch {
+		case errors.Is(err, tokenbus.ErrNotFound),
+			errors.Is(err, tokenbus.ErrExpired),
+			errors.Is(err, tokenbus.ErrRevoked),
+			errors.Is(err, tokenbus.ErrReused):
+			return Token{}, errs.New(errs.Unauthenticated, err)
+		}
+		return Token{}, errs.Newf(errs.Internal, "rotate: %s", err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, iss.UserID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return Token{}, errs.New(errs.Unauthenticated, errAuthentication)
+		}
+		return Token{}, errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", iss.UserID, err)
+	}
+
+	if !usr.Enabled {
+		if err := a.tokenBus.RevokeByUserID(ctx, usr.ID); err != nil {
+			return Token{}, errs.Newf(errs.Internal, "revokebyuserid: userID[%s]: %s", usr.ID, err)
+		}
+		return Token{}, errs.New(errs.Unauthenticated, errAuthentication)
+	}
+
+	return a.token(usr, iss)
+}
+
+// Logout revokes the access token used to make the call and the family of
+// the refresh token when one is provided.
+func (a *App) Logout(ctx context.Context, claims auth.Claims, app Logout) error {
+	if err := a.auth.Revoke(ctx, claims); err != nil {
+		return errs.Newf(errs.Internal, "revoke: %s", err)
+	}
+
+	if app.RefreshToken == "" {
+		return nil
+	}
+
+	if err := a.tokenBus.Revoke(ctx, app.RefreshToken); err != nil {
+		if errors.Is(err, tokenbus.ErrNotFound) {
+			return nil
+		}
+		return errs.Newf(errs.Internal, "revoke: %s", err)
+	}
+
+	return nil
+}
+
+// Purge permanently removes the tokens that expired longer ago than the
+// specified retention window.
+func (a *App) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := a.tokenBus.Purge(ctx, retention)
+	if err != nil {
+		return 0, errs.Newf(errs.Internal, "purge: %s", err)
+	}
+
+	return n, nil
+}
+
+// =============================================================================
+
+func (a *App) token(usr userbus.User, iss tokenbus.Issued) (Token, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(accessTTL)
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			Issuer:    a.auth.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Roles:         userbus.ParseRolesToString(usr.Roles),
+		Tenant:        usr.TenantID.String(),
+		EmailVerified: usr.EmailVerified,
+	}
+
+	tkn, err := a.auth.GenerateToken(a.auth.ActiveKID(), claims)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "generatetoken: %s", err)
+	}
+
+	return toAppToken(tkn, iss.Token, expiresAt), nil
+}
@@ -0,0 +1,69 @@
+package authapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+)
+
+// Login defines the credentials needed to sign in. The forwarded for header
+// identifies the client so failed attempts can be tracked per client.
+type Login struct {
+	Email        string `json:"email" validate:"required,email"`
+	Password     string `json:"password" validate:"required"`
+	ForwardedFor string `header:"X-Forwarded-For"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Login) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// Refresh defines the refresh token to exchange for new tokens.
+type Refresh struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Refresh) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// Logout defines the refresh token to revoke along with the access token
+// used to make the call. The refresh token is optional.
+type Logout struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// =============================================================================
+
+// Token represents an access token and the refresh token used to get a new
+// access token once it expires.
+type Token struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresAt    string `json:"expiresAt"`
+}
+
+// Encode implments the encoder interface.
+func (app Token) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppToken(token string, refreshToken string, expiresAt time.Time) Token {
+	return Token{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,112 @@
+// Package delegateapp maintains the app layer api for the dead letters of the
+// delegate system.
+package delegateapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the dead letters.
+type App struct {
+	delegate *delegate.Delegate
+}
+
+// NewApp constructs a delegate app API for use.
+func NewApp(delegate *delegate.Delegate) *App {
+	return &App{
+		delegate: delegate,
+	}
+}
+
+// Query returns a list of dead letters with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[DeadLetter], error) {
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[DeadLetter]{}, err
+	}
+
+	filter := parseFilter(qp)
+
+	dls, err := a.delegate.QueryDeadLetters(ctx, filter, pg)
+	if err != nil {
+		return query.Result[DeadLetter]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.delegate.CountDeadLetters(ctx, filter)
+	if err != nil {
+		return query.Result[DeadLetter]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppDeadLetters(dls), total, pg), nil
+}
+
+// QueryByID returns a dead letter by its ID.
+func (a *App) QueryByID(ctx context.Context, deadLetterID string) (DeadLetter, error) {
+	dl, err := a.queryByID(ctx, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	return toAppDeadLetter(dl), nil
+}
+
+// Replay executes the delegate function of the dead letter again. The dead
+// letter is removed when the function succeeds.
+func (a *App) Replay(ctx context.Context, deadLetterID string) error {
+	dl, err := a.queryByID(ctx, deadLetterID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.delegate.Replay(ctx, dl); err != nil {
+		switch {
+		case errors.Is(err, delegate.ErrHandlerNotFound):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, delegate.ErrReplayFailed):
+			return errs.New(errs.Aborted, err)
+		}
+		return errs.Newf(errs.Internal, "replay: deadLetterID[%s]: %s", dl.ID, err)
+	}
+
+	return nil
+}
+
+// Discard removes a dead letter without executing its delegate function.
+func (a *App) Discard(ctx context.Context, deadLetterID string) error {
+	dl, err := a.queryByID(ctx, deadLetterID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.delegate.Discard(ctx, dl); err != nil {
+		return errs.Newf(errs.Internal, "discard: deadLetterID[%s]: %s", dl.ID, err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+func (a *App) queryByID(ctx context.Context, deadLetterID string) (delegate.DeadLetter, error) {
+	id, err := uuid.Parse(deadLetterID)
+	if err != nil {
+		return delegate.DeadLetter{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	dl, err := a.delegate.QueryDeadLetterByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, delegate.ErrDeadLetterNotFound) {
+			return delegate.DeadLetter{}, errs.New(errs.NotFound, delegate.ErrDeadLetterNotFound)
+		}
+		return delegate.DeadLetter{}, errs.Newf(errs.Internal, "querybyid: deadLetterID[%s]: %s", id, err)
+	}
+
+	return dl, nil
+}
@@ -0,0 +1,19 @@
+package delegateapp
+
+import (
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+func parseFilter(qp QueryParams) delegate.DeadLetterFilter {
+	var filter delegate.DeadLetterFilter
+
+	if qp.Domain != "" {
+		filter.Domain = &qp.Domain
+	}
+
+	if qp.Action != "" {
+		filter.Action = &qp.Action
+	}
+
+	return filter
+}
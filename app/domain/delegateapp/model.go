@@ -0,0 +1,71 @@
+package delegateapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page   string
+	Rows   string
+	Domain string
+	Action string
+}
+
+// =============================================================================
+
+// DeadLetter represents an event a delegate function failed to handle.
+type DeadLetter struct {
+	ID          string          `json:"id"`
+	EventID     string          `json:"eventID"`
+	Domain      string          `json:"domain"`
+	Action      string          `json:"action"`
+	Version     int             `json:"version"`
+	Params      json.RawMessage `json:"params"`
+	Handler     string          `json:"handler"`
+	Attempts    int             `json:"attempts"`
+	Error       string          `json:"error"`
+	DateCreated string          `json:"dateCreated"`
+	DateFailed  string          `json:"dateFailed"`
+}
+
+// Encode implments the encoder interface.
+func (app DeadLetter) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDeadLetter(dl delegate.DeadLetter) DeadLetter {
+	app := DeadLetter{
+		ID:          dl.ID.String(),
+		EventID:     dl.Data.ID,
+		Domain:      dl.Data.Domain,
+		Action:      dl.Data.Action,
+		Version:     dl.Data.Version,
+		Params:      dl.Data.RawParams,
+		Handler:     dl.Handler,
+		Attempts:    dl.Attempts,
+		Error:       dl.Error,
+		DateCreated: dl.DateCreated.Format(time.RFC3339),
+		DateFailed:  dl.DateFailed.Format(time.RFC3339),
+	}
+
+	// The parameters aren't required to be json.
+	if !json.Valid(app.Params) {
+		app.Params, _ = json.Marshal(string(dl.Data.RawParams))
+	}
+
+	return app
+}
+
+func toAppDeadLetters(dls []delegate.DeadLetter) []DeadLetter {
+	app := make([]DeadLetter, len(dls))
+	for i, dl := range dls {
+		app[i] = toAppDeadLetter(dl)
+	}
+
+	return app
+}
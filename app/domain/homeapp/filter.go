@@ -1,6 +1,8 @@
 package homeapp
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
@@ -19,6 +21,16 @@ func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 		filter.ID = &id
 	}
 
+	if qp.IDs != "" {
+		for _, v := range strings.Split(qp.IDs, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return homebus.QueryFilter{}, errs.NewFieldsError("home_ids", err)
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
 	if qp.UserID != "" {
 		id, err := uuid.Parse(qp.UserID)
 		if err != nil {
@@ -27,6 +39,16 @@ func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 		filter.UserID = &id
 	}
 
+	if qp.UserIDs != "" {
+		for _, v := range strings.Split(qp.UserIDs, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return homebus.QueryFilter{}, errs.NewFieldsError("user_ids", err)
+			}
+			filter.UserIDs = append(filter.UserIDs, id)
+		}
+	}
+
 	if qp.Type != "" {
 		typ, err := homebus.ParseType(qp.Type)
 		if err != nil {
@@ -35,6 +57,44 @@ func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 		filter.Type = &typ
 	}
 
+	if qp.Types != "" {
+		for _, v := range strings.Split(qp.Types, ",") {
+			typ, err := homebus.ParseType(strings.TrimSpace(v))
+			if err != nil {
+				return homebus.QueryFilter{}, errs.NewFieldsError("types", err)
+			}
+			filter.Types = append(filter.Types, typ)
+		}
+	}
+
+	if qp.City != "" {
+		city := qp.City
+		filter.City = &city
+	}
+
+	if qp.State != "" {
+		state := qp.State
+		filter.State = &state
+	}
+
+	if qp.ZipCode != "" {
+		zipCode := qp.ZipCode
+		filter.ZipCode = &zipCode
+	}
+
+	if qp.Country != "" {
+		country := qp.Country
+		filter.Country = &country
+	}
+
+	if qp.Status != "" {
+		status, err := homebus.ParseStatus(qp.Status)
+		if err != nil {
+			return homebus.QueryFilter{}, errs.NewFieldsError("status", err)
+		}
+		filter.Status = &status
+	}
+
 	if qp.StartCreatedDate != "" {
 		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
 		if err != nil {
@@ -51,5 +111,13 @@ func parseFilter(qp QueryParams) (homebus.QueryFilter, error) {
 		filter.EndCreatedDate = &t
 	}
 
+	if qp.Deleted != "" {
+		deleted, err := strconv.ParseBool(qp.Deleted)
+		if err != nil {
+			return homebus.QueryFilter{}, errs.NewFieldsError("deleted", err)
+		}
+		filter.Deleted = &deleted
+	}
+
 	return filter, nil
 }
@@ -3,13 +3,17 @@ package homeapp
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
-	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the home domain.
@@ -24,14 +28,44 @@ func NewApp(homeBus *homebus.Business) *App {
 	}
 }
 
+// newWithTx constructs a new App value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	homeBus, err := a.homeBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		homeBus: homeBus,
+	}
+
+	return &app, nil
+}
+
 // Create adds a new home to the system.
 func (a *App) Create(ctx context.Context, app NewHome) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
 	nh, err := toBusNewHome(ctx, app)
 	if err != nil {
 		return Home{}, errs.New(errs.InvalidArgument, err)
 	}
 
-	hme, err := a.homeBus.Create(ctx, nh)
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Home{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	hme, err := a.homeBus.Create(ctx, actorID, nh)
 	if err != nil {
 		return Home{}, errs.Newf(errs.Internal, "create: hme[%+v]: %s", app, err)
 	}
@@ -41,6 +75,11 @@ func (a *App) Create(ctx context.Context, app NewHome) (Home, error) {
 
 // Update updates an existing home.
 func (a *App) Update(ctx context.Context, app UpdateHome) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
 	uh, err := toBusUpdateHome(app)
 	if err != nil {
 		return Home{}, errs.New(errs.InvalidArgument, err)
@@ -51,8 +90,20 @@ func (a *App) Update(ctx context.Context, app UpdateHome) (Home, error) {
 		return Home{}, errs.Newf(errs.Internal, "home missing in context: %s", err)
 	}
 
-	updUsr, err := a.homeBus.Update(ctx, hme, uh)
+	if err := etag.Check(app.IfMatch, hme.Version); err != nil {
+		return Home{}, errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
 	if err != nil {
+		return Home{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	updUsr, err := a.homeBus.Update(ctx, actorID, hme, uh)
+	if err != nil {
+		if errors.Is(err, homebus.ErrVersionConflict) {
+			return Home{}, errs.New(errs.FailedPrecondition, err)
+		}
 		return Home{}, errs.Newf(errs.Internal, "update: homeID[%s] uh[%+v]: %s", hme.ID, uh, err)
 	}
 
@@ -60,32 +111,97 @@ func (a *App) Update(ctx context.Context, app UpdateHome) (Home, error) {
 }
 
 // Delete removes a home from the system.
-func (a *App) Delete(ctx context.Context) error {
+func (a *App) Delete(ctx context.Context, pc etag.Precondition) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	hme, err := mid.GetHome(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "homeID missing in context: %s", err)
 	}
 
-	if err := a.homeBus.Delete(ctx, hme); err != nil {
+	if err := etag.Check(pc.IfMatch, hme.Version); err != nil {
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	if err := a.homeBus.Delete(ctx, actorID, hme); err != nil {
+		if errors.Is(err, homebus.ErrVersionConflict) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
 		return errs.Newf(errs.Internal, "delete: homeID[%s]: %s", hme.ID, err)
 	}
 
 	return nil
 }
 
+// Restore brings back a deleted home. The home can't be restored while the
+// user that owns it is deleted.
+func (a *App) Restore(ctx context.Context, homeID string) (Home, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Home{}, errs.New(errs.Internal, err)
+	}
+
+	id, err := uuid.Parse(homeID)
+	if err != nil {
+		return Home{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	hme, err := a.homeBus.QueryDeletedByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, homebus.ErrNotFound) {
+			return Home{}, errs.New(errs.NotFound, err)
+		}
+		return Home{}, errs.Newf(errs.Internal, "querydeletedbyid: homeID[%s]: %s", id, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Home{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	rstHme, err := a.homeBus.Restore(ctx, actorID, hme)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return Home{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return Home{}, errs.Newf(errs.Internal, "restore: homeID[%s]: %s", hme.ID, err)
+	}
+
+	return toAppHome(rstHme), nil
+}
+
+// Purge permanently removes the homes that were deleted longer ago than the
+// specified retention window.
+func (a *App) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := a.homeBus.Purge(ctx, retention)
+	if err != nil {
+		return 0, errs.Newf(errs.Internal, "purge: %s", err)
+	}
+
+	return n, nil
+}
+
 // Query returns a list of homes with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Home], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
 
-	filter, err := parseFilter(qp)
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Home]{}, err
 	}
@@ -95,6 +211,11 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Home], er
 		return query.Result[Home]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
+	if page.IsCursor() {
+		next := query.NextCursor(hmes, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppHomes(hmes), page, next), nil
+	}
+
 	total, err := a.homeBus.Count(ctx, filter)
 	if err != nil {
 		return query.Result[Home]{}, errs.Newf(errs.Internal, "count: %s", err)
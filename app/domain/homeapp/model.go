@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/business/domain/homebus"
 )
@@ -15,12 +16,22 @@ import (
 type QueryParams struct {
 	Page             string
 	Rows             string
+	Cursor           string
 	OrderBy          string
 	ID               string
+	IDs              string
 	UserID           string
+	UserIDs          string
 	Type             string
+	Types            string
+	City             string
+	State            string
+	ZipCode          string
+	Country          string
+	Status           string
 	StartCreatedDate string
 	EndCreatedDate   string
+	Deleted          string
 }
 
 // =============================================================================
@@ -41,8 +52,10 @@ type Home struct {
 	UserID      string  `json:"userID"`
 	Type        string  `json:"type"`
 	Address     Address `json:"address"`
+	Status      string  `json:"status"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
+	ETag        string  `json:"etag" header:"ETag"`
 }
 
 // Encode implments the encoder interface.
@@ -64,8 +77,10 @@ func toAppHome(hme homebus.Home) Home {
 			State:    hme.Address.State,
 			Country:  hme.Address.Country,
 		},
+		Status:      hme.Status.String(),
 		DateCreated: hme.DateCreated.Format(time.RFC3339),
 		DateUpdated: hme.DateUpdated.Format(time.RFC3339),
+		ETag:        etag.Format(hme.Version),
 	}
 }
 
@@ -153,6 +168,7 @@ type UpdateAddress struct {
 type UpdateHome struct {
 	Type    *string        `json:"type"`
 	Address *UpdateAddress `json:"address"`
+	IfMatch string         `header:"If-Match"`
 }
 
 // Decode implments the decoder interface.
@@ -12,3 +12,16 @@ var orderByFields = map[string]string{
 	"type":    homebus.OrderByType,
 	"user_id": homebus.OrderByUserID,
 }
+
+// cursorKey returns the value of the order by field and the id of the
+// home for constructing a page cursor.
+func cursorKey(hme homebus.Home, field string) (string, string) {
+	switch field {
+	case homebus.OrderByType:
+		return hme.Type.String(), hme.ID.String()
+	case homebus.OrderByUserID:
+		return hme.UserID.String(), hme.ID.String()
+	}
+
+	return hme.ID.String(), hme.ID.String()
+}
@@ -0,0 +1,101 @@
+// Package lockoutapp maintains the app layer api for the lockout domain.
+package lockoutapp
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+)
+
+// App manages the set of app layer api functions for the lockout domain.
+type App struct {
+	lockoutBus *lockoutbus.Business
+	userBus    *userbus.Business
+}
+
+// NewApp constructs a lockout app API for use.
+func NewApp(lockoutBus *lockoutbus.Business, userBus *userbus.Business) *App {
+	return &App{
+		lockoutBus: lockoutBus,
+		userBus:    userBus,
+	}
+}
+
+// QueryBySubject returns the failed attempts of the email or client ip.
+func (a *App) QueryBySubject(ctx context.Context, claims auth.Claims, app Subject) (Lockout, error) {
+	sub, err := a.subject(ctx, claims, app)
+	if err != nil {
+		return Lockout{}, err
+	}
+
+	l, err := a.lockoutBus.QueryBySubject(ctx, sub)
+	if err != nil {
+		if errors.Is(err, lockoutbus.ErrNotFound) {
+			return Lockout{}, errs.New(errs.NotFound, err)
+		}
+		return Lockout{}, errs.Newf(errs.Internal, "querybysubject: %s", err)
+	}
+
+	return toAppLockout(l), nil
+}
+
+// Unlock clears the failed attempts and the lockout of the email or client
+// ip.
+func (a *App) Unlock(ctx context.Context, claims auth.Claims, app Subject) error {
+	sub, err := a.subject(ctx, claims, app)
+	if err != nil {
+		return err
+	}
+
+	if err := a.lockoutBus.Unlock(ctx, sub); err != nil {
+		return errs.Newf(errs.Internal, "unlock: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// subject checks the admin can act on the subject. The admins of the
+// default organization can act on any subject. The admins of the other
+// organizations can only act on the emails of their own users since a
+// client ip can be shared between organizations.
+func (a *App) subject(ctx context.Context, claims auth.Claims, app Subject) (lockoutbus.Subject, error) {
+	sub, err := toBusSubject(app)
+	if err != nil {
+		return lockoutbus.Subject{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	if claims.Tenant == orgbus.DefaultID.String() {
+		return sub, nil
+	}
+
+	if sub.Kind != lockoutbus.Kinds.Email {
+		return lockoutbus.Subject{}, errs.Newf(errs.PermissionDenied, "only the default organization can act on a client ip")
+	}
+
+	addr, err := mail.ParseAddress(sub.Value)
+	if err != nil {
+		return lockoutbus.Subject{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userBus.QueryByEmail(ctx, *addr)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return lockoutbus.Subject{}, errs.New(errs.NotFound, lockoutbus.ErrNotFound)
+		}
+		return lockoutbus.Subject{}, errs.Newf(errs.Internal, "querybyemail: %s", err)
+	}
+
+	if usr.TenantID.String() != claims.Tenant {
+		return lockoutbus.Subject{}, errs.New(errs.NotFound, lockoutbus.ErrNotFound)
+	}
+
+	return sub, nil
+}
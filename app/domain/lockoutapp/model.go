@@ -0,0 +1,72 @@
+package lockoutapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+)
+
+// Lockout represents the failed attempts of an email or client ip.
+type Lockout struct {
+	Kind            string `json:"kind"`
+	Subject         string `json:"subject"`
+	Failures        int    `json:"failures"`
+	Lockouts        int    `json:"lockouts"`
+	DateLastFailure string `json:"dateLastFailure"`
+	DateLockedUntil string `json:"dateLockedUntil,omitempty"`
+}
+
+// Encode implments the encoder interface.
+func (app Lockout) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppLockout(l lockoutbus.Lockout) Lockout {
+	app := Lockout{
+		Kind:            l.Subject.Kind.String(),
+		Subject:         l.Subject.Value,
+		Failures:        l.Failures,
+		Lockouts:        l.Lockouts,
+		DateLastFailure: l.DateLastFailure.Format(time.RFC3339),
+	}
+
+	if !l.DateLockedUntil.IsZero() {
+		app.DateLockedUntil = l.DateLockedUntil.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// Subject defines the email or client ip to look up or unlock.
+type Subject struct {
+	Kind    string `json:"kind" validate:"required"`
+	Subject string `json:"subject" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Subject) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusSubject(app Subject) (lockoutbus.Subject, error) {
+	kind, err := lockoutbus.ParseKind(app.Kind)
+	if err != nil {
+		return lockoutbus.Subject{}, err
+	}
+
+	sub := lockoutbus.Subject{
+		Kind:  kind,
+		Value: app.Subject,
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,55 @@
+package orderapp
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/google/uuid"
+)
+
+func parseFilter(qp QueryParams) (orderbus.QueryFilter, error) {
+	var filter orderbus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		if err != nil {
+			return orderbus.QueryFilter{}, errs.NewFieldsError("order_id", err)
+		}
+		filter.ID = &id
+	}
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return orderbus.QueryFilter{}, errs.NewFieldsError("user_id", err)
+		}
+		filter.UserID = &id
+	}
+
+	if qp.HomeID != "" {
+		id, err := uuid.Parse(qp.HomeID)
+		if err != nil {
+			return orderbus.QueryFilter{}, errs.NewFieldsError("home_id", err)
+		}
+		filter.HomeID = &id
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		if err != nil {
+			return orderbus.QueryFilter{}, errs.NewFieldsError("start_created_date", err)
+		}
+		filter.StartCreatedDate = &t
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		if err != nil {
+			return orderbus.QueryFilter{}, errs.NewFieldsError("end_created_date", err)
+		}
+		filter.EndCreatedDate = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,149 @@
+package orderapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/google/uuid"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page             string
+	Rows             string
+	Cursor           string
+	OrderBy          string
+	ID               string
+	UserID           string
+	HomeID           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+// =============================================================================
+
+// Item represents an individual product purchased in an order.
+type Item struct {
+	ProductID string  `json:"productID"`
+	Quantity  int     `json:"quantity"`
+	Cost      float64 `json:"cost"`
+	Currency  string  `json:"currency"`
+}
+
+// Order represents information about an individual order.
+type Order struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"userID"`
+	HomeID      string  `json:"homeID"`
+	Items       []Item  `json:"items"`
+	Total       float64 `json:"total"`
+	Currency    string  `json:"currency"`
+	DateCreated string  `json:"dateCreated"`
+	DateUpdated string  `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Order) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrder(ord orderbus.Order) Order {
+	total := ord.Total()
+
+	items := make([]Item, len(ord.Items))
+	for i, itm := range ord.Items {
+		items[i] = Item{
+			ProductID: itm.ProductID.String(),
+			Quantity:  itm.Quantity,
+			Cost:      itm.Cost.Float64(),
+			Currency:  itm.Cost.Currency(),
+		}
+	}
+
+	return Order{
+		ID:          ord.ID.String(),
+		UserID:      ord.UserID.String(),
+		HomeID:      ord.HomeID.String(),
+		Items:       items,
+		Total:       total.Float64(),
+		Currency:    total.Currency(),
+		DateCreated: ord.DateCreated.Format(time.RFC3339),
+		DateUpdated: ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []orderbus.Order) []Order {
+	app := make([]Order, len(ords))
+	for i, ord := range ords {
+		app[i] = toAppOrder(ord)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewItem defines the data needed for each product being purchased.
+type NewItem struct {
+	ProductID string `json:"productID" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,gte=1"`
+}
+
+// NewOrder defines the data needed to place a new order.
+type NewOrder struct {
+	HomeID string    `json:"homeID" validate:"required"`
+	Items  []NewItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// Decode implments the decoder interface.
+func (app *NewOrder) Decode(data []byte) error {
+	return json.Unmarshal(data, &app)
+}
+
+// Validate checks if the data in the model is considered clean.
+func (app NewOrder) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrder(ctx context.Context, app NewOrder) (orderbus.NewOrder, error) {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return orderbus.NewOrder{}, fmt.Errorf("getuserid: %w", err)
+	}
+
+	homeID, err := uuid.Parse(app.HomeID)
+	if err != nil {
+		return orderbus.NewOrder{}, fmt.Errorf("parse: %w", err)
+	}
+
+	items := make([]orderbus.NewItem, len(app.Items))
+	for i, itm := range app.Items {
+		productID, err := uuid.Parse(itm.ProductID)
+		if err != nil {
+			return orderbus.NewOrder{}, fmt.Errorf("parse: %w", err)
+		}
+
+		items[i] = orderbus.NewItem{
+			ProductID: productID,
+			Quantity:  itm.Quantity,
+		}
+	}
+
+	bus := orderbus.NewOrder{
+		UserID: userID,
+		HomeID: homeID,
+		Items:  items,
+	}
+
+	return bus, nil
+}
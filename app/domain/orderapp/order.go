@@ -0,0 +1,32 @@
+package orderapp
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+var defaultOrderBy = order.NewBy("order_id", order.ASC)
+
+var orderByFields = map[string]string{
+	"order_id":     orderbus.OrderByID,
+	"user_id":      orderbus.OrderByUserID,
+	"home_id":      orderbus.OrderByHomeID,
+	"date_created": orderbus.OrderByDateCreated,
+}
+
+// cursorKey returns the value of the order by field and the id of the
+// order for constructing a page cursor.
+func cursorKey(ord orderbus.Order, field string) (string, string) {
+	switch field {
+	case orderbus.OrderByUserID:
+		return ord.UserID.String(), ord.ID.String()
+	case orderbus.OrderByHomeID:
+		return ord.HomeID.String(), ord.ID.String()
+	case orderbus.OrderByDateCreated:
+		return ord.DateCreated.UTC().Format(time.RFC3339Nano), ord.ID.String()
+	}
+
+	return ord.ID.String(), ord.ID.String()
+}
@@ -0,0 +1,145 @@
+// Package orderapp maintains the app layer api for the order domain.
+package orderapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+// App manages the set of app layer api functions for the order domain.
+type App struct {
+	orderBus *orderbus.Business
+}
+
+// NewApp constructs an order app API for use.
+func NewApp(orderBus *orderbus.Business) *App {
+	return &App{
+		orderBus: orderBus,
+	}
+}
+
+// newWithTx constructs a new App value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBus, err := a.orderBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		orderBus: orderBus,
+	}
+
+	return &app, nil
+}
+
+// Create places a new order and removes the purchased stock under a
+// single transaction.
+func (a *App) Create(ctx context.Context, app NewOrder) (Order, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Order{}, errs.New(errs.Internal, err)
+	}
+
+	no, err := toBusNewOrder(ctx, app)
+	if err != nil {
+		return Order{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	ord, err := a.orderBus.Create(ctx, no)
+	if err != nil {
+		switch {
+		case errors.Is(err, productbus.ErrInsufficientStock):
+			return Order{}, errs.New(errs.FailedPrecondition, productbus.ErrInsufficientStock)
+
+		case errors.Is(err, productbus.ErrInactive):
+			return Order{}, errs.New(errs.FailedPrecondition, productbus.ErrInactive)
+
+		case errors.Is(err, orderbus.ErrProductInactive):
+			return Order{}, errs.New(errs.FailedPrecondition, orderbus.ErrProductInactive)
+
+		case errors.Is(err, orderbus.ErrUserDisabled):
+			return Order{}, errs.New(errs.FailedPrecondition, orderbus.ErrUserDisabled)
+
+		case errors.Is(err, orderbus.ErrCurrencyMismatch):
+			return Order{}, errs.New(errs.FailedPrecondition, orderbus.ErrCurrencyMismatch)
+
+		case errors.Is(err, orderbus.ErrHomeNotOwned):
+			return Order{}, errs.New(errs.InvalidArgument, orderbus.ErrHomeNotOwned)
+
+		case errors.Is(err, orderbus.ErrNoItems):
+			return Order{}, errs.New(errs.InvalidArgument, orderbus.ErrNoItems)
+
+		case errors.Is(err, orderbus.ErrDuplicateProduct):
+			return Order{}, errs.New(errs.InvalidArgument, orderbus.ErrDuplicateProduct)
+
+		case errors.Is(err, productbus.ErrNotFound):
+			return Order{}, errs.New(errs.NotFound, productbus.ErrNotFound)
+
+		case errors.Is(err, homebus.ErrNotFound):
+			return Order{}, errs.New(errs.NotFound, homebus.ErrNotFound)
+		}
+
+		return Order{}, errs.Newf(errs.Internal, "create: no[%+v]: %s", no, err)
+	}
+
+	return toAppOrder(ord), nil
+}
+
+// Query returns a list of orders with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Order], error) {
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	if err != nil {
+		return query.Result[Order]{}, err
+	}
+
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
+	if err != nil {
+		return query.Result[Order]{}, err
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return query.Result[Order]{}, err
+	}
+
+	ords, err := a.orderBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return query.Result[Order]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	if page.IsCursor() {
+		next := query.NextCursor(ords, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppOrders(ords), page, next), nil
+	}
+
+	total, err := a.orderBus.Count(ctx, filter)
+	if err != nil {
+		return query.Result[Order]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppOrders(ords), total, page), nil
+}
+
+// QueryByID returns an order by its ID.
+func (a *App) QueryByID(ctx context.Context) (Order, error) {
+	ord, err := mid.GetOrder(ctx)
+	if err != nil {
+		return Order{}, errs.Newf(errs.Internal, "querybyid: %s", err)
+	}
+
+	return toAppOrder(ord), nil
+}
@@ -0,0 +1,90 @@
+package orgapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+)
+
+// Organization represents information about an individual organization.
+type Organization struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	AdminID     string `json:"adminID,omitempty"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implments the encoder interface.
+func (app Organization) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrganization(org orgbus.Organization) Organization {
+	return Organization{
+		ID:          org.ID.String(),
+		Name:        org.Name,
+		DateCreated: org.DateCreated.Format(time.RFC3339),
+		DateUpdated: org.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// NewOrganization defines the data needed to add a new organization along
+// with the first admin of the organization.
+type NewOrganization struct {
+	Name  string   `json:"name" validate:"required"`
+	Admin NewAdmin `json:"admin"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewOrganization) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrganization(app NewOrganization) orgbus.NewOrganization {
+	return orgbus.NewOrganization{
+		Name: app.Name,
+	}
+}
+
+// NewAdmin contains information needed to create the first admin of an
+// organization.
+type NewAdmin struct {
+	Name            string `json:"name" validate:"required"`
+	Email           string `json:"email" validate:"required,email"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+func toBusNewUser(app NewAdmin) (userbus.NewUser, error) {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
+	}
+
+	name, err := userbus.ParseName(app.Name)
+	if err != nil {
+		return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := userbus.NewUser{
+		Name:     name,
+		Email:    *addr,
+		Roles:    []userbus.Role{userbus.Roles.Admin},
+		Password: app.Password,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,136 @@
+// Package orgapp maintains the app layer api for the organization domain.
+package orgapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/google/uuid"
+)
+
+// App manages the set of app layer api functions for the organization domain.
+type App struct {
+	orgBus  *orgbus.Business
+	userBus *userbus.Business
+}
+
+// NewApp constructs an organization app API for use.
+func NewApp(orgBus *orgbus.Business, userBus *userbus.Business) *App {
+	return &App{
+		orgBus:  orgBus,
+		userBus: userBus,
+	}
+}
+
+// newWithTx constructs a new Handlers value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgBus, err := a.orgBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		orgBus:  orgBus,
+		userBus: userBus,
+	}
+
+	return &app, nil
+}
+
+// Create adds a new organization and its first admin under a single
+// transaction. Only the admins of the default organization, which operates
+// the deployment, can create organizations.
+func (a *App) Create(ctx context.Context, app NewOrganization) (Organization, error) {
+	tenantID, err := mid.GetTenantID(ctx)
+	if err != nil {
+		return Organization{}, errs.Newf(errs.Internal, "tenant missing in context: %s", err)
+	}
+
+	if tenantID != orgbus.DefaultID {
+		return Organization{}, errs.Newf(errs.PermissionDenied, "only the default organization can create organizations")
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return Organization{}, errs.New(errs.Internal, err)
+	}
+
+	nu, err := toBusNewUser(app.Admin)
+	if err != nil {
+		return Organization{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Organization{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	org, err := a.orgBus.Create(ctx, toBusNewOrganization(app))
+	if err != nil {
+		if errors.Is(err, orgbus.ErrUniqueName) {
+			return Organization{}, errs.New(errs.Aborted, orgbus.ErrUniqueName)
+		}
+		return Organization{}, errs.Newf(errs.Internal, "create: org[%+v]: %s", app.Name, err)
+	}
+
+	// The admin is created as part of the new organization so the database
+	// access has to be scoped to it.
+	nu.TenantID = org.ID
+
+	usr, err := a.userBus.Create(sqldb.WithTenant(ctx, org.ID), actorID, nu)
+	if err != nil {
+		if errors.Is(err, userbus.ErrUniqueEmail) {
+			return Organization{}, errs.New(errs.Aborted, userbus.ErrUniqueEmail)
+		}
+		return Organization{}, errs.Newf(errs.Internal, "create: usr[%+v]: %s", usr, err)
+	}
+
+	resp := toAppOrganization(org)
+	resp.AdminID = usr.ID.String()
+
+	return resp, nil
+}
+
+// QueryByID returns the organization by its ID. Only the caller's own
+// organization can be read unless they belong to the default organization.
+func (a *App) QueryByID(ctx context.Context, orgID string) (Organization, error) {
+	id, err := uuid.Parse(orgID)
+	if err != nil {
+		return Organization{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	tenantID, err := mid.GetTenantID(ctx)
+	if err != nil {
+		return Organization{}, errs.Newf(errs.Internal, "tenant missing in context: %s", err)
+	}
+
+	if tenantID != orgbus.DefaultID && tenantID != id {
+		return Organization{}, errs.New(errs.NotFound, orgbus.ErrNotFound)
+	}
+
+	org, err := a.orgBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, orgbus.ErrNotFound) {
+			return Organization{}, errs.New(errs.NotFound, err)
+		}
+		return Organization{}, errs.Newf(errs.Internal, "querybyid: orgID[%s]: %s", id, err)
+	}
+
+	return toAppOrganization(org), nil
+}
@@ -2,6 +2,8 @@ package productapp
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/productbus"
@@ -19,6 +21,16 @@ func parseFilter(qp QueryParams) (productbus.QueryFilter, error) {
 		filter.ID = &id
 	}
 
+	if qp.IDs != "" {
+		for _, v := range strings.Split(qp.IDs, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return productbus.QueryFilter{}, errs.NewFieldsError("product_ids", err)
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
 	if qp.Name != "" {
 		name, err := productbus.ParseName(qp.Name)
 		if err != nil {
@@ -27,14 +39,38 @@ func parseFilter(qp QueryParams) (productbus.QueryFilter, error) {
 		filter.Name = &name
 	}
 
+	if qp.NamePrefix != "" {
+		name, err := productbus.ParseName(qp.NamePrefix)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("name_prefix", err)
+		}
+		filter.NamePrefix = &name
+	}
+
 	if qp.Cost != "" {
-		cst, err := strconv.ParseFloat(qp.Cost, 64)
+		cst, err := productbus.ParseMoney(qp.Cost, productbus.DefaultCurrency)
 		if err != nil {
 			return productbus.QueryFilter{}, errs.NewFieldsError("cost", err)
 		}
 		filter.Cost = &cst
 	}
 
+	if qp.MinCost != "" {
+		cst, err := productbus.ParseMoney(qp.MinCost, productbus.DefaultCurrency)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("min_cost", err)
+		}
+		filter.MinCost = &cst
+	}
+
+	if qp.MaxCost != "" {
+		cst, err := productbus.ParseMoney(qp.MaxCost, productbus.DefaultCurrency)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("max_cost", err)
+		}
+		filter.MaxCost = &cst
+	}
+
 	if qp.Quantity != "" {
 		qua, err := strconv.ParseInt(qp.Quantity, 10, 64)
 		if err != nil {
@@ -44,5 +80,55 @@ func parseFilter(qp QueryParams) (productbus.QueryFilter, error) {
 		filter.Quantity = &i
 	}
 
+	if qp.MinQuantity != "" {
+		qua, err := strconv.ParseInt(qp.MinQuantity, 10, 64)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("min_quantity", err)
+		}
+		i := int(qua)
+		filter.MinQuantity = &i
+	}
+
+	if qp.MaxQuantity != "" {
+		qua, err := strconv.ParseInt(qp.MaxQuantity, 10, 64)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("max_quantity", err)
+		}
+		i := int(qua)
+		filter.MaxQuantity = &i
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("start_created_date", err)
+		}
+		filter.StartCreatedDate = &t
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("end_created_date", err)
+		}
+		filter.EndCreatedDate = &t
+	}
+
+	if qp.Status != "" {
+		status, err := productbus.ParseStatus(qp.Status)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("status", err)
+		}
+		filter.Status = &status
+	}
+
+	if qp.Deleted != "" {
+		deleted, err := strconv.ParseBool(qp.Deleted)
+		if err != nil {
+			return productbus.QueryFilter{}, errs.NewFieldsError("deleted", err)
+		}
+		filter.Deleted = &deleted
+	}
+
 	return filter, nil
 }
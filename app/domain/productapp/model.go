@@ -7,19 +7,39 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 )
 
 // QueryParams represents the set of possible query strings.
 type QueryParams struct {
-	Page     string
-	Rows     string
-	OrderBy  string
-	ID       string
-	Name     string
-	Cost     string
-	Quantity string
+	Page             string
+	Rows             string
+	Cursor           string
+	OrderBy          string
+	ID               string
+	IDs              string
+	Name             string
+	NamePrefix       string
+	Cost             string
+	MinCost          string
+	MaxCost          string
+	Quantity         string
+	MinQuantity      string
+	MaxQuantity      string
+	StartCreatedDate string
+	EndCreatedDate   string
+	Status           string
+	Deleted          string
+}
+
+// SearchParams represents the set of possible query strings for a full text
+// search.
+type SearchParams struct {
+	Page string
+	Rows string
+	Q    string
 }
 
 // =============================================================================
@@ -30,9 +50,12 @@ type Product struct {
 	UserID      string  `json:"userID"`
 	Name        string  `json:"name"`
 	Cost        float64 `json:"cost"`
+	Currency    string  `json:"currency"`
 	Quantity    int     `json:"quantity"`
+	Status      string  `json:"status"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
+	ETag        string  `json:"etag" header:"ETag"`
 }
 
 // Encode implments the encoder interface.
@@ -46,10 +69,13 @@ func toAppProduct(prd productbus.Product) Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
+		Cost:        prd.Cost.Float64(),
+		Currency:    prd.Cost.Currency(),
 		Quantity:    prd.Quantity,
+		Status:      prd.Status.String(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		ETag:        etag.Format(prd.Version),
 	}
 }
 
@@ -64,10 +90,34 @@ func toAppProducts(prds []productbus.Product) []Product {
 
 // =============================================================================
 
-// NewProduct defines the data needed to add a new product.
+// SearchResult represents a product matching a full text search.
+type SearchResult struct {
+	Product Product `json:"product"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func toAppSearchResults(results []productbus.SearchResult) []SearchResult {
+	app := make([]SearchResult, len(results))
+	for i, result := range results {
+		app[i] = SearchResult{
+			Product: toAppProduct(result.Product),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewProduct defines the data needed to add a new product. The currency
+// defaults to US dollars when it isn't provided.
 type NewProduct struct {
 	Name     string  `json:"name" validate:"required"`
 	Cost     float64 `json:"cost" validate:"required,gte=0"`
+	Currency string  `json:"currency" validate:"omitempty,iso4217"`
 	Quantity int     `json:"quantity" validate:"required,gte=1"`
 }
 
@@ -96,10 +146,20 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (productbus.NewProduct
 		return productbus.NewProduct{}, fmt.Errorf("parse name: %w", err)
 	}
 
+	currency := app.Currency
+	if currency == "" {
+		currency = productbus.DefaultCurrency
+	}
+
+	cost, err := productbus.ParseMoneyFloat(app.Cost, currency)
+	if err != nil {
+		return productbus.NewProduct{}, fmt.Errorf("parse cost: %w", err)
+	}
+
 	bus := productbus.NewProduct{
 		UserID:   userID,
 		Name:     name,
-		Cost:     app.Cost,
+		Cost:     cost,
 		Quantity: app.Quantity,
 	}
 
@@ -108,11 +168,14 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (productbus.NewProduct
 
 // =============================================================================
 
-// UpdateProduct defines the data needed to update a product.
+// UpdateProduct defines the data needed to update a product. A cost or
+// currency that isn't provided keeps the current value.
 type UpdateProduct struct {
 	Name     *string  `json:"name"`
 	Cost     *float64 `json:"cost" validate:"omitempty,gte=0"`
+	Currency *string  `json:"currency" validate:"omitempty,iso4217"`
 	Quantity *int     `json:"quantity" validate:"omitempty,gte=1"`
+	IfMatch  string   `header:"If-Match"`
 }
 
 // Decode implments the decoder interface.
@@ -129,7 +192,7 @@ func (app UpdateProduct) Validate() error {
 	return nil
 }
 
-func toBusUpdateProduct(app UpdateProduct) (productbus.UpdateProduct, error) {
+func toBusUpdateProduct(prd productbus.Product, app UpdateProduct) (productbus.UpdateProduct, error) {
 	var name *productbus.Name
 	if app.Name != nil {
 		nm, err := productbus.ParseName(*app.Name)
@@ -139,9 +202,28 @@ func toBusUpdateProduct(app UpdateProduct) (productbus.UpdateProduct, error) {
 		name = &nm
 	}
 
+	var cost *productbus.Money
+	if app.Cost != nil || app.Currency != nil {
+		amount := prd.Cost.Float64()
+		if app.Cost != nil {
+			amount = *app.Cost
+		}
+
+		currency := prd.Cost.Currency()
+		if app.Currency != nil {
+			currency = *app.Currency
+		}
+
+		cst, err := productbus.ParseMoneyFloat(amount, currency)
+		if err != nil {
+			return productbus.UpdateProduct{}, fmt.Errorf("parse cost: %w", err)
+		}
+		cost = &cst
+	}
+
 	bus := productbus.UpdateProduct{
 		Name:     name,
-		Cost:     app.Cost,
+		Cost:     cost,
 		Quantity: app.Quantity,
 	}
 
@@ -1,6 +1,8 @@
 package productapp
 
 import (
+	"strconv"
+
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 )
@@ -14,3 +16,20 @@ var orderByFields = map[string]string{
 	"quantity":   productbus.OrderByQuantity,
 	"user_id":    productbus.OrderByUserID,
 }
+
+// cursorKey returns the value of the order by field and the id of the
+// product for constructing a page cursor.
+func cursorKey(prd productbus.Product, field string) (string, string) {
+	switch field {
+	case productbus.OrderByUserID:
+		return prd.UserID.String(), prd.ID.String()
+	case productbus.OrderByName:
+		return prd.Name.String(), prd.ID.String()
+	case productbus.OrderByCost:
+		return prd.Cost.String(), prd.ID.String()
+	case productbus.OrderByQuantity:
+		return strconv.Itoa(prd.Quantity), prd.ID.String()
+	}
+
+	return prd.ID.String(), prd.ID.String()
+}
@@ -3,13 +3,19 @@ package productapp
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the product domain.
@@ -24,14 +30,44 @@ func NewApp(productBus *productbus.Business) *App {
 	}
 }
 
+// newWithTx constructs a new App value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	productBus, err := a.productBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		productBus: productBus,
+	}
+
+	return &app, nil
+}
+
 // Create adds a new product to the system.
 func (a *App) Create(ctx context.Context, app NewProduct) (Product, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Product{}, errs.New(errs.Internal, err)
+	}
+
 	np, err := toBusNewProduct(ctx, app)
 	if err != nil {
 		return Product{}, errs.New(errs.InvalidArgument, err)
 	}
 
-	prd, err := a.productBus.Create(ctx, np)
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Product{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	prd, err := a.productBus.Create(ctx, actorID, np)
 	if err != nil {
 		return Product{}, errs.Newf(errs.Internal, "create: prd[%+v]: %s", prd, err)
 	}
@@ -41,9 +77,9 @@ func (a *App) Create(ctx context.Context, app NewProduct) (Product, error) {
 
 // Update updates an existing product.
 func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
-	up, err := toBusUpdateProduct(app)
+	a, err := a.newWithTx(ctx)
 	if err != nil {
-		return Product{}, errs.New(errs.InvalidArgument, err)
+		return Product{}, errs.New(errs.Internal, err)
 	}
 
 	prd, err := mid.GetProduct(ctx)
@@ -51,8 +87,25 @@ func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
 		return Product{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
 	}
 
-	updPrd, err := a.productBus.Update(ctx, prd, up)
+	up, err := toBusUpdateProduct(prd, app)
 	if err != nil {
+		return Product{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := etag.Check(app.IfMatch, prd.Version); err != nil {
+		return Product{}, errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Product{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	updPrd, err := a.productBus.Update(ctx, actorID, prd, up)
+	if err != nil {
+		if errors.Is(err, productbus.ErrVersionConflict) {
+			return Product{}, errs.New(errs.FailedPrecondition, err)
+		}
 		return Product{}, errs.Newf(errs.Internal, "update: productID[%s] up[%+v]: %s", prd.ID, app, err)
 	}
 
@@ -60,32 +113,97 @@ func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
 }
 
 // Delete removes a product from the system.
-func (a *App) Delete(ctx context.Context) error {
+func (a *App) Delete(ctx context.Context, pc etag.Precondition) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	prd, err := mid.GetProduct(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "productID missing in context: %s", err)
 	}
 
-	if err := a.productBus.Delete(ctx, prd); err != nil {
+	if err := etag.Check(pc.IfMatch, prd.Version); err != nil {
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	if err := a.productBus.Delete(ctx, actorID, prd); err != nil {
+		if errors.Is(err, productbus.ErrVersionConflict) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
 		return errs.Newf(errs.Internal, "delete: productID[%s]: %s", prd.ID, err)
 	}
 
 	return nil
 }
 
+// Restore brings back a deleted product. The product can't be restored while
+// the user that owns it is deleted.
+func (a *App) Restore(ctx context.Context, productID string) (Product, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Product{}, errs.New(errs.Internal, err)
+	}
+
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return Product{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := a.productBus.QueryDeletedByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, productbus.ErrNotFound) {
+			return Product{}, errs.New(errs.NotFound, err)
+		}
+		return Product{}, errs.Newf(errs.Internal, "querydeletedbyid: productID[%s]: %s", id, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Product{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	rstPrd, err := a.productBus.Restore(ctx, actorID, prd)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return Product{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return Product{}, errs.Newf(errs.Internal, "restore: productID[%s]: %s", prd.ID, err)
+	}
+
+	return toAppProduct(rstPrd), nil
+}
+
+// Purge permanently removes the products that were deleted longer ago than
+// the specified retention window.
+func (a *App) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := a.productBus.Purge(ctx, retention)
+	if err != nil {
+		return 0, errs.Newf(errs.Internal, "purge: %s", err)
+	}
+
+	return n, nil
+}
+
 // Query returns a list of products with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	filter, err := parseFilter(qp)
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
@@ -95,6 +213,11 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product],
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
+	if page.IsCursor() {
+		next := query.NextCursor(prds, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppProducts(prds), page, next), nil
+	}
+
 	total, err := a.productBus.Count(ctx, filter)
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "count: %s", err)
@@ -103,6 +226,34 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product],
 	return query.NewResult(toAppProducts(prds), total, page), nil
 }
 
+// Search returns a list of products matching the full text search, best
+// matches first.
+func (a *App) Search(ctx context.Context, sp SearchParams) (query.Result[SearchResult], error) {
+	page, err := page.Parse(sp.Page, sp.Rows)
+	if err != nil {
+		return query.Result[SearchResult]{}, err
+	}
+
+	text := strings.TrimSpace(sp.Q)
+	if text == "" {
+		return query.Result[SearchResult]{}, errs.Newf(errs.InvalidArgument, "search text is required")
+	}
+
+	var filter productbus.QueryFilter
+
+	results, err := a.productBus.Search(ctx, text, filter, page)
+	if err != nil {
+		return query.Result[SearchResult]{}, errs.Newf(errs.Internal, "search: %s", err)
+	}
+
+	total, err := a.productBus.SearchCount(ctx, text, filter)
+	if err != nil {
+		return query.Result[SearchResult]{}, errs.Newf(errs.Internal, "searchcount: %s", err)
+	}
+
+	return query.NewResult(toAppSearchResults(results), total, page), nil
+}
+
 // QueryByID returns a product by its Ia.
 func (a *App) QueryByID(ctx context.Context) (Product, error) {
 	prd, err := mid.GetProduct(ctx)
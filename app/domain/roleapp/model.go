@@ -0,0 +1,140 @@
+package roleapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+)
+
+// QueryParams represents the set of possible query strings.
+type QueryParams struct {
+	Page string
+	Rows string
+}
+
+// =============================================================================
+
+// Role represents information about an individual role.
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	DateCreated string   `json:"dateCreated"`
+}
+
+// Encode implments the encoder interface.
+func (app Role) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRole(role rolebus.Role) Role {
+	return Role{
+		Name:        role.Name.String(),
+		Permissions: rolebus.ParsePermissionsToString(role.Permissions),
+		DateCreated: role.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppRoles(roles []rolebus.Role) []Role {
+	app := make([]Role, len(roles))
+	for i, role := range roles {
+		app[i] = toAppRole(role)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewRole defines the data needed to add a new role.
+type NewRole struct {
+	Name        string   `json:"name" validate:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewRole) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewRole(app NewRole) (rolebus.NewRole, error) {
+	name, err := userbus.ParseRole(app.Name)
+	if err != nil {
+		return rolebus.NewRole{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	perms, err := rolebus.ParsePermissions(app.Permissions)
+	if err != nil {
+		return rolebus.NewRole{}, fmt.Errorf("parse permissions: %w", err)
+	}
+
+	bus := rolebus.NewRole{
+		Name:        name,
+		Permissions: perms,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// GrantPermission defines the data needed to grant a permission to a role.
+type GrantPermission struct {
+	Permission string `json:"permission" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app GrantPermission) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// RulePermissions represents the permissions that satisfy a rule of the
+// authorization policy.
+type RulePermissions struct {
+	Rule        string   `json:"rule"`
+	Permissions []string `json:"permissions"`
+	Subject     []string `json:"subject"`
+}
+
+// Encode implments the encoder interface.
+func (app RulePermissions) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRulePermissions(rp rolebus.RulePermissions) RulePermissions {
+	return RulePermissions{
+		Rule:        rp.Rule,
+		Permissions: rolebus.ParsePermissionsToString(rp.Permissions),
+		Subject:     rolebus.ParsePermissionsToString(rp.Subject),
+	}
+}
+
+// GrantRule defines the data needed to make a permission satisfy a rule.
+type GrantRule struct {
+	Permission string `json:"permission" validate:"required"`
+	Scope      string `json:"scope" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app GrantRule) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package roleapp maintains the app layer api for the role domain.
+package roleapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/mid"
+	"github.com/ardanlabs/encore/app/sdk/query"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+// App manages the set of app layer api functions for the role domain.
+type App struct {
+	roleBus *rolebus.Business
+}
+
+// NewApp constructs a role app API for use.
+func NewApp(roleBus *rolebus.Business) *App {
+	return &App{
+		roleBus: roleBus,
+	}
+}
+
+// Create adds a new role to the system. Roles are shared by every tenant, so
+// only the admins of the default organization can create them.
+func (a *App) Create(ctx context.Context, app NewRole) (Role, error) {
+	if err := checkDefaultTenant(ctx); err != nil {
+		return Role{}, err
+	}
+
+	nr, err := toBusNewRole(app)
+	if err != nil {
+		return Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	role, err := a.roleBus.Create(ctx, nr)
+	if err != nil {
+		if errors.Is(err, rolebus.ErrUniqueName) {
+			return Role{}, errs.New(errs.Aborted, rolebus.ErrUniqueName)
+		}
+		return Role{}, errs.Newf(errs.Internal, "create: role[%+v]: %s", nr, err)
+	}
+
+	return toAppRole(role), nil
+}
+
+// Grant gives a permission to an existing role. Only the admins of the
+// default organization can grant permissions.
+func (a *App) Grant(ctx context.Context, name string, app GrantPermission) (Role, error) {
+	if err := checkDefaultTenant(ctx); err != nil {
+		return Role{}, err
+	}
+
+	roleName, err := userbus.ParseRole(name)
+	if err != nil {
+		return Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	perm, err := rolebus.ParsePermission(app.Permission)
+	if err != nil {
+		return Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	role, err := a.roleBus.QueryByName(ctx, roleName)
+	if err != nil {
+		if errors.Is(err, rolebus.ErrNotFound) {
+			return Role{}, errs.New(errs.NotFound, err)
+		}
+		return Role{}, errs.Newf(errs.Internal, "querybyname: name[%s]: %s", roleName, err)
+	}
+
+	role, err = a.roleBus.Grant(ctx, role, perm)
+	if err != nil {
+		return Role{}, errs.Newf(errs.Internal, "grant: name[%s] permission[%s]: %s", roleName, perm, err)
+	}
+
+	return toAppRole(role), nil
+}
+
+// GrantRule makes a permission satisfy a rule of the authorization policy.
+// Only the admins of the default organization can change the policy.
+func (a *App) GrantRule(ctx context.Context, rule string, app GrantRule) (RulePermissions, error) {
+	if err := checkDefaultTenant(ctx); err != nil {
+		return RulePermissions{}, err
+	}
+
+	if !auth.IsRule(rule) {
+		return RulePermissions{}, errs.Newf(errs.NotFound, "rule %q not found", rule)
+	}
+
+	perm, err := rolebus.ParsePermission(app.Permission)
+	if err != nil {
+		return RulePermissions{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	scope, err := rolebus.ParseScope(app.Scope)
+	if err != nil {
+		return RulePermissions{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	rp, err := a.roleBus.GrantRule(ctx, rule, perm, scope)
+	if err != nil {
+		return RulePermissions{}, errs.Newf(errs.Internal, "grantrule: rule[%s] permission[%s]: %s", rule, perm, err)
+	}
+
+	return toAppRulePermissions(rp), nil
+}
+
+// QueryRule returns the permissions that satisfy a rule of the authorization
+// policy.
+func (a *App) QueryRule(ctx context.Context, rule string) (RulePermissions, error) {
+	if !auth.IsRule(rule) {
+		return RulePermissions{}, errs.Newf(errs.NotFound, "rule %q not found", rule)
+	}
+
+	rp, err := a.roleBus.QueryRulePermissions(ctx, rule)
+	if err != nil {
+		return RulePermissions{}, errs.Newf(errs.Internal, "queryrulepermissions: rule[%s]: %s", rule, err)
+	}
+
+	return toAppRulePermissions(rp), nil
+}
+
+// Query returns a list of roles with paging.
+func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Role], error) {
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return query.Result[Role]{}, err
+	}
+
+	roles, err := a.roleBus.Query(ctx, pg)
+	if err != nil {
+		return query.Result[Role]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.roleBus.Count(ctx)
+	if err != nil {
+		return query.Result[Role]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppRoles(roles), total, pg), nil
+}
+
+// =============================================================================
+
+// checkDefaultTenant denies the request unless it's made by the default
+// organization, which operates the deployment. The roles and the permissions
+// of the rules apply to every tenant.
+func checkDefaultTenant(ctx context.Context) error {
+	tenantID, err := mid.GetTenantID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "tenant missing in context: %s", err)
+	}
+
+	if tenantID != orgbus.DefaultID {
+		return errs.Newf(errs.PermissionDenied, "only the default organization can change roles and permissions")
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ type Product struct {
 	UserID      string  `json:"userID"`
 	Name        string  `json:"name"`
 	Cost        float64 `json:"cost"`
+	Currency    string  `json:"currency"`
 	Quantity    int     `json:"quantity"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
@@ -33,7 +34,8 @@ func toAppProduct(prd productbus.Product) Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
+		Cost:        prd.Cost.Float64(),
+		Currency:    prd.Cost.Currency(),
 		Quantity:    prd.Quantity,
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
@@ -108,10 +110,12 @@ func toBusNewUser(app NewUser) (userbus.NewUser, error) {
 
 // =============================================================================
 
-// NewProduct is what we require from clients when adding a Product.
+// NewProduct is what we require from clients when adding a Product. The
+// currency defaults to US dollars when it isn't provided.
 type NewProduct struct {
 	Name     string  `json:"name" validate:"required"`
 	Cost     float64 `json:"cost" validate:"required,gte=0"`
+	Currency string  `json:"currency" validate:"omitempty,iso4217"`
 	Quantity int     `json:"quantity" validate:"required,gte=1"`
 }
 
@@ -130,9 +134,19 @@ func toBusNewProduct(app NewProduct) (productbus.NewProduct, error) {
 		return productbus.NewProduct{}, fmt.Errorf("parse: %w", err)
 	}
 
+	currency := app.Currency
+	if currency == "" {
+		currency = productbus.DefaultCurrency
+	}
+
+	cost, err := productbus.ParseMoneyFloat(app.Cost, currency)
+	if err != nil {
+		return productbus.NewProduct{}, fmt.Errorf("parse cost: %w", err)
+	}
+
 	bus := productbus.NewProduct{
 		Name:     name,
-		Cost:     app.Cost,
+		Cost:     cost,
 		Quantity: app.Quantity,
 	}
 
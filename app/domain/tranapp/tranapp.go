@@ -68,17 +68,30 @@ func (a *App) Create(ctx context.Context, nt NewTran) (Product, error) {
 		return Product{}, errs.New(errs.InvalidArgument, err)
 	}
 
-	usr, err := a.userBus.Create(ctx, nu)
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return Product{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	nu.TenantID, err = mid.GetTenantID(ctx)
+	if err != nil {
+		return Product{}, errs.Newf(errs.Internal, "tenant missing in context: %s", err)
+	}
+
+	usr, err := a.userBus.Create(ctx, actorID, nu)
 	if err != nil {
 		if errors.Is(err, userbus.ErrUniqueEmail) {
 			return Product{}, errs.New(errs.Aborted, userbus.ErrUniqueEmail)
 		}
+		if errors.Is(err, userbus.ErrUnknownRole) {
+			return Product{}, errs.New(errs.InvalidArgument, err)
+		}
 		return Product{}, errs.Newf(errs.Internal, "create: usr[%+v]: %s", usr, err)
 	}
 
 	np.UserID = usr.ID
 
-	prd, err := a.productBus.Create(ctx, np)
+	prd, err := a.productBus.Create(ctx, actorID, np)
 	if err != nil {
 		return Product{}, errs.Newf(errs.Internal, "create: prd[%+v]: %s", prd, err)
 	}
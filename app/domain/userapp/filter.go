@@ -2,6 +2,8 @@ package userapp
 
 import (
 	"net/mail"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
@@ -20,6 +22,16 @@ func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 		filter.ID = &id
 	}
 
+	if qp.IDs != "" {
+		for _, v := range strings.Split(qp.IDs, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return userbus.QueryFilter{}, errs.NewFieldsError("user_ids", err)
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
 	if qp.Name != "" {
 		name, err := userbus.ParseName(qp.Name)
 		if err != nil {
@@ -28,6 +40,14 @@ func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 		filter.Name = &name
 	}
 
+	if qp.NamePrefix != "" {
+		name, err := userbus.ParseName(qp.NamePrefix)
+		if err != nil {
+			return userbus.QueryFilter{}, errs.NewFieldsError("name_prefix", err)
+		}
+		filter.NamePrefix = &name
+	}
+
 	if qp.Email != "" {
 		addr, err := mail.ParseAddress(qp.Email)
 		if err != nil {
@@ -36,6 +56,29 @@ func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 		filter.Email = addr
 	}
 
+	if qp.Roles != "" {
+		for _, v := range strings.Split(qp.Roles, ",") {
+			role, err := userbus.ParseRole(strings.TrimSpace(v))
+			if err != nil {
+				return userbus.QueryFilter{}, errs.NewFieldsError("roles", err)
+			}
+			filter.Roles = append(filter.Roles, role)
+		}
+	}
+
+	if qp.Department != "" {
+		department := qp.Department
+		filter.Department = &department
+	}
+
+	if qp.Enabled != "" {
+		enabled, err := strconv.ParseBool(qp.Enabled)
+		if err != nil {
+			return userbus.QueryFilter{}, errs.NewFieldsError("enabled", err)
+		}
+		filter.Enabled = &enabled
+	}
+
 	if qp.StartCreatedDate != "" {
 		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
 		if err != nil {
@@ -52,5 +95,13 @@ func parseFilter(qp QueryParams) (userbus.QueryFilter, error) {
 		filter.EndCreatedDate = &t
 	}
 
+	if qp.Deleted != "" {
+		deleted, err := strconv.ParseBool(qp.Deleted)
+		if err != nil {
+			return userbus.QueryFilter{}, errs.NewFieldsError("deleted", err)
+		}
+		filter.Deleted = &deleted
+	}
+
 	return filter, nil
 }
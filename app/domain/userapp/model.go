@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
 
@@ -13,27 +14,36 @@ import (
 type QueryParams struct {
 	Page             string
 	Rows             string
+	Cursor           string
 	OrderBy          string
 	ID               string
+	IDs              string
 	Name             string
+	NamePrefix       string
 	Email            string
+	Roles            string
+	Department       string
+	Enabled          string
 	StartCreatedDate string
 	EndCreatedDate   string
+	Deleted          string
 }
 
 // =============================================================================
 
 // User represents information about an individual user.
 type User struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Email        string   `json:"email"`
-	Roles        []string `json:"roles"`
-	PasswordHash []byte   `json:"-"`
-	Department   string   `json:"department"`
-	Enabled      bool     `json:"enabled"`
-	DateCreated  string   `json:"dateCreated"`
-	DateUpdated  string   `json:"dateUpdated"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Email         string   `json:"email"`
+	Roles         []string `json:"roles"`
+	PasswordHash  []byte   `json:"-"`
+	Department    string   `json:"department"`
+	Enabled       bool     `json:"enabled"`
+	EmailVerified bool     `json:"emailVerified"`
+	DateCreated   string   `json:"dateCreated"`
+	DateUpdated   string   `json:"dateUpdated"`
+	ETag          string   `json:"etag" header:"ETag"`
 }
 
 func toAppUser(bus userbus.User) User {
@@ -43,15 +53,17 @@ func toAppUser(bus userbus.User) User {
 	}
 
 	return User{
-		ID:           bus.ID.String(),
-		Name:         bus.Name.String(),
-		Email:        bus.Email.Address,
-		Roles:        roles,
-		PasswordHash: bus.PasswordHash,
-		Department:   bus.Department,
-		Enabled:      bus.Enabled,
-		DateCreated:  bus.DateCreated.Format(time.RFC3339),
-		DateUpdated:  bus.DateUpdated.Format(time.RFC3339),
+		ID:            bus.ID.String(),
+		Name:          bus.Name.String(),
+		Email:         bus.Email.Address,
+		Roles:         roles,
+		PasswordHash:  bus.PasswordHash,
+		Department:    bus.Department,
+		Enabled:       bus.Enabled,
+		EmailVerified: bus.EmailVerified,
+		DateCreated:   bus.DateCreated.Format(time.RFC3339),
+		DateUpdated:   bus.DateUpdated.Format(time.RFC3339),
+		ETag:          etag.Format(bus.Version),
 	}
 }
 
@@ -120,7 +132,8 @@ func toBusNewUser(app NewUser) (userbus.NewUser, error) {
 
 // UpdateUserRole defines the data needed to update a user role.
 type UpdateUserRole struct {
-	Roles []string `json:"roles" validate:"required"`
+	Roles   []string `json:"roles" validate:"required"`
+	IfMatch string   `header:"If-Match"`
 }
 
 // Validate checks the data in the model is considered clean.
@@ -162,6 +175,7 @@ type UpdateUser struct {
 	Password        *string `json:"password"`
 	PasswordConfirm *string `json:"passwordConfirm" validate:"omitempty,eqfield=Password"`
 	Enabled         *bool   `json:"enabled"`
+	IfMatch         string  `header:"If-Match"`
 }
 
 // Validate checks the data in the model is considered clean.
@@ -1,6 +1,9 @@
 package userapp
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 )
@@ -14,3 +17,29 @@ var orderByFields = map[string]string{
 	"roles":   userbus.OrderByRoles,
 	"enabled": userbus.OrderByEnabled,
 }
+
+// cursorKey returns the value of the order by field and the id of the
+// user for constructing a page cursor.
+func cursorKey(usr userbus.User, field string) (string, string) {
+	switch field {
+	case userbus.OrderByName:
+		return usr.Name.String(), usr.ID.String()
+	case userbus.OrderByEmail:
+		return usr.Email.Address, usr.ID.String()
+	case userbus.OrderByRoles:
+		return formatRoles(usr.Roles), usr.ID.String()
+	case userbus.OrderByEnabled:
+		return strconv.FormatBool(usr.Enabled), usr.ID.String()
+	}
+
+	return usr.ID.String(), usr.ID.String()
+}
+
+func formatRoles(roles []userbus.Role) string {
+	strs := make([]string, len(roles))
+	for i, role := range roles {
+		strs[i] = role.String()
+	}
+
+	return "{" + strings.Join(strs, ",") + "}"
+}
@@ -4,14 +4,16 @@ package userapp
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/app/sdk/etag"
 	"github.com/ardanlabs/encore/app/sdk/mid"
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
-	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the user domain.
@@ -35,18 +37,57 @@ func NewAppWithAuth(userBus *userbus.Business, ath *auth.Auth) *App {
 	}
 }
 
+// newWithTx constructs a new App value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		userBus: userBus,
+		auth:    a.auth,
+	}
+
+	return &app, nil
+}
+
 // Create adds a new user to the system.
 func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	nc, err := toBusNewUser(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
 	}
 
-	usr, err := a.userBus.Create(ctx, nc)
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return User{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	nc.TenantID, err = mid.GetTenantID(ctx)
+	if err != nil {
+		return User{}, errs.Newf(errs.Internal, "tenant missing in context: %s", err)
+	}
+
+	usr, err := a.userBus.Create(ctx, actorID, nc)
 	if err != nil {
 		if errors.Is(err, userbus.ErrUniqueEmail) {
 			return User{}, errs.New(errs.Aborted, userbus.ErrUniqueEmail)
 		}
+		if errors.Is(err, userbus.ErrUnknownRole) {
+			return User{}, errs.New(errs.InvalidArgument, err)
+		}
 		return User{}, errs.Newf(errs.Internal, "create: usr[%+v]: %s", usr, err)
 	}
 
@@ -55,6 +96,11 @@ func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
 
 // Update updates an existing user.
 func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	uu, err := toBusUpdateUser(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
@@ -65,8 +111,23 @@ func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
 		return User{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
 	}
 
-	updUsr, err := a.userBus.Update(ctx, usr, uu)
+	if err := etag.Check(app.IfMatch, usr.Version); err != nil {
+		return User{}, errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
 	if err != nil {
+		return User{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	updUsr, err := a.userBus.Update(ctx, actorID, usr, uu)
+	if err != nil {
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			return User{}, errs.New(errs.FailedPrecondition, err)
+		}
+		if errors.Is(err, userbus.ErrUnknownRole) {
+			return User{}, errs.New(errs.InvalidArgument, err)
+		}
 		return User{}, errs.Newf(errs.Internal, "update: userID[%s] uu[%+v]: %s", usr.ID, uu, err)
 	}
 
@@ -75,6 +136,11 @@ func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
 
 // UpdateRole updates an existing user's role.
 func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
 	uu, err := toBusUpdateUserRole(app)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
@@ -85,8 +151,23 @@ func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error)
 		return User{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
 	}
 
-	updUsr, err := a.userBus.Update(ctx, usr, uu)
+	if err := etag.Check(app.IfMatch, usr.Version); err != nil {
+		return User{}, errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return User{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	updUsr, err := a.userBus.UpdateRole(ctx, actorID, usr, uu.Roles)
 	if err != nil {
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			return User{}, errs.New(errs.FailedPrecondition, err)
+		}
+		if errors.Is(err, userbus.ErrUnknownRole) {
+			return User{}, errs.New(errs.InvalidArgument, err)
+		}
 		return User{}, errs.Newf(errs.Internal, "updaterole: userID[%s] uu[%+v]: %s", usr.ID, uu, err)
 	}
 
@@ -94,32 +175,97 @@ func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error)
 }
 
 // Delete removes a user from the system.
-func (a *App) Delete(ctx context.Context) error {
+func (a *App) Delete(ctx context.Context, pc etag.Precondition) error {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	usr, err := mid.GetUser(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "userID missing in context: %s", err)
 	}
 
-	if err := a.userBus.Delete(ctx, usr); err != nil {
+	if err := etag.Check(pc.IfMatch, usr.Version); err != nil {
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	if err := a.userBus.Delete(ctx, actorID, usr); err != nil {
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
 		return errs.Newf(errs.Internal, "delete: userID[%s]: %s", usr.ID, err)
 	}
 
 	return nil
 }
 
+// Restore brings back a deleted user along with the products and homes that
+// were deleted with them.
+func (a *App) Restore(ctx context.Context, userID string) (User, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return User{}, errs.New(errs.Internal, err)
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return User{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userBus.QueryDeletedByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return User{}, errs.New(errs.NotFound, err)
+		}
+		return User{}, errs.Newf(errs.Internal, "querydeletedbyid: userID[%s]: %s", id, err)
+	}
+
+	actorID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return User{}, errs.Newf(errs.Internal, "actor missing in context: %s", err)
+	}
+
+	rstUsr, err := a.userBus.Restore(ctx, actorID, usr)
+	if err != nil {
+		if errors.Is(err, userbus.ErrUniqueEmail) {
+			return User{}, errs.New(errs.Aborted, userbus.ErrUniqueEmail)
+		}
+		return User{}, errs.Newf(errs.Internal, "restore: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppUser(rstUsr), nil
+}
+
+// Purge permanently removes the users that were deleted longer ago than the
+// specified retention window.
+func (a *App) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := a.userBus.Purge(ctx, retention)
+	if err != nil {
+		return 0, errs.Newf(errs.Internal, "purge: %s", err)
+	}
+
+	return n, nil
+}
+
 // Query returns a list of users with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[User], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
 	if err != nil {
 		return query.Result[User]{}, err
 	}
 
-	filter, err := parseFilter(qp)
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
 	if err != nil {
 		return query.Result[User]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[User]{}, err
 	}
@@ -129,6 +275,11 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[User], er
 		return query.Result[User]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
+	if page.IsCursor() {
+		next := query.NextCursor(usrs, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppUsers(usrs), page, next), nil
+	}
+
 	total, err := a.userBus.Count(ctx, filter)
 	if err != nil {
 		return query.Result[User]{}, errs.Newf(errs.Internal, "count: %s", err)
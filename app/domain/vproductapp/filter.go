@@ -2,6 +2,7 @@ package vproductapp
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/productbus"
@@ -21,6 +22,16 @@ func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 		filter.ID = &id
 	}
 
+	if qp.IDs != "" {
+		for _, v := range strings.Split(qp.IDs, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return vproductbus.QueryFilter{}, errs.NewFieldsError("product_ids", err)
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
 	if qp.Name != "" {
 		name, err := productbus.ParseName(qp.Name)
 		if err != nil {
@@ -29,14 +40,38 @@ func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 		filter.Name = &name
 	}
 
+	if qp.NamePrefix != "" {
+		name, err := productbus.ParseName(qp.NamePrefix)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("name_prefix", err)
+		}
+		filter.NamePrefix = &name
+	}
+
 	if qp.Cost != "" {
-		cst, err := strconv.ParseFloat(qp.Cost, 64)
+		cst, err := productbus.ParseMoney(qp.Cost, productbus.DefaultCurrency)
 		if err != nil {
 			return vproductbus.QueryFilter{}, errs.NewFieldsError("cost", err)
 		}
 		filter.Cost = &cst
 	}
 
+	if qp.MinCost != "" {
+		cst, err := productbus.ParseMoney(qp.MinCost, productbus.DefaultCurrency)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("min_cost", err)
+		}
+		filter.MinCost = &cst
+	}
+
+	if qp.MaxCost != "" {
+		cst, err := productbus.ParseMoney(qp.MaxCost, productbus.DefaultCurrency)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("max_cost", err)
+		}
+		filter.MaxCost = &cst
+	}
+
 	if qp.Quantity != "" {
 		qua, err := strconv.ParseInt(qp.Quantity, 10, 64)
 		if err != nil {
@@ -46,13 +81,44 @@ func parseFilter(qp QueryParams) (vproductbus.QueryFilter, error) {
 		filter.Quantity = &i
 	}
 
-	if qp.Name != "" {
-		name, err := userbus.ParseName(qp.Name)
+	if qp.MinQuantity != "" {
+		qua, err := strconv.ParseInt(qp.MinQuantity, 10, 64)
 		if err != nil {
-			return vproductbus.QueryFilter{}, errs.NewFieldsError("name", err)
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("min_quantity", err)
+		}
+		i := int(qua)
+		filter.MinQuantity = &i
+	}
+
+	if qp.MaxQuantity != "" {
+		qua, err := strconv.ParseInt(qp.MaxQuantity, 10, 64)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("max_quantity", err)
+		}
+		i := int(qua)
+		filter.MaxQuantity = &i
+	}
+
+	if qp.Status != "" {
+		status, err := productbus.ParseStatus(qp.Status)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("status", err)
+		}
+		filter.Status = &status
+	}
+
+	if qp.UserName != "" {
+		name, err := userbus.ParseName(qp.UserName)
+		if err != nil {
+			return vproductbus.QueryFilter{}, errs.NewFieldsError("user_name", err)
 		}
 		filter.UserName = &name
 	}
 
+	if qp.Search != "" {
+		search := qp.Search
+		filter.Search = &search
+	}
+
 	return filter, nil
 }
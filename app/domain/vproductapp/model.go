@@ -9,14 +9,23 @@ import (
 
 // QueryParams represents the set of possible query strings.
 type QueryParams struct {
-	Page     string
-	Rows     string
-	OrderBy  string
-	ID       string
-	Name     string
-	Cost     string
-	Quantity string
-	UserName string
+	Page        string
+	Rows        string
+	Cursor      string
+	OrderBy     string
+	ID          string
+	IDs         string
+	Name        string
+	NamePrefix  string
+	Cost        string
+	MinCost     string
+	MaxCost     string
+	Quantity    string
+	MinQuantity string
+	MaxQuantity string
+	Status      string
+	UserName    string
+	Search      string
 }
 
 // =============================================================================
@@ -28,7 +37,9 @@ type Product struct {
 	UserID      string  `json:"userID"`
 	Name        string  `json:"name"`
 	Cost        float64 `json:"cost"`
+	Currency    string  `json:"currency"`
 	Quantity    int     `json:"quantity"`
+	Status      string  `json:"status"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
 	UserName    string  `json:"userName"`
@@ -45,8 +56,10 @@ func toAppProduct(prd vproductbus.Product) Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost,
+		Cost:        prd.Cost.Float64(),
+		Currency:    prd.Cost.Currency(),
 		Quantity:    prd.Quantity,
+		Status:      prd.Status.String(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
 		UserName:    prd.UserName.String(),
@@ -1,6 +1,8 @@
 package vproductapp
 
 import (
+	"strconv"
+
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 )
@@ -15,3 +17,22 @@ var orderByFields = map[string]string{
 	"quantity":   vproductbus.OrderByQuantity,
 	"user_name":  vproductbus.OrderByUserName,
 }
+
+// cursorKey returns the value of the order by field and the id of the
+// product for constructing a page cursor.
+func cursorKey(prd vproductbus.Product, field string) (string, string) {
+	switch field {
+	case vproductbus.OrderByUserID:
+		return prd.UserID.String(), prd.ID.String()
+	case vproductbus.OrderByName:
+		return prd.Name.String(), prd.ID.String()
+	case vproductbus.OrderByCost:
+		return prd.Cost.String(), prd.ID.String()
+	case vproductbus.OrderByQuantity:
+		return strconv.Itoa(prd.Quantity), prd.ID.String()
+	case vproductbus.OrderByUserName:
+		return prd.UserName.String(), prd.ID.String()
+	}
+
+	return prd.ID.String(), prd.ID.String()
+}
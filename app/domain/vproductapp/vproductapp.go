@@ -8,7 +8,6 @@ import (
 	"github.com/ardanlabs/encore/app/sdk/query"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
-	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
 // App manages the set of app layer api functions for the view product domain.
@@ -25,17 +24,17 @@ func NewApp(vproductBus *vproductbus.Business) *App {
 
 // Query returns a list of products with paging.
 func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product], error) {
-	page, err := page.Parse(qp.Page, qp.Rows)
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	filter, err := parseFilter(qp)
+	page, err := query.ParsePage(qp.Page, qp.Rows, qp.Cursor, orderBy)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, defaultOrderBy)
+	filter, err := parseFilter(qp)
 	if err != nil {
 		return query.Result[Product]{}, err
 	}
@@ -45,6 +44,11 @@ func (a *App) Query(ctx context.Context, qp QueryParams) (query.Result[Product],
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "query: %s", err)
 	}
 
+	if page.IsCursor() {
+		next := query.NextCursor(prds, page, orderBy, cursorKey)
+		return query.NewCursorResult(toAppProducts(prds), page, next), nil
+	}
+
 	total, err := a.vproductBus.Count(ctx, filter)
 	if err != nil {
 		return query.Result[Product]{}, errs.Newf(errs.Internal, "count: %s", err)
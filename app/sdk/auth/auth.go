@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus/stores/tokendb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/usercache"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
@@ -20,21 +22,31 @@ import (
 	"github.com/open-policy-agent/opa/rego"
 )
 
-// ErrForbidden is returned when a auth issue is identified.
-var ErrForbidden = errors.New("attempted action is not allowed")
+// Set of error variables for authentication.
+var (
+	ErrForbidden = errors.New("attempted action is not allowed")
+	ErrRevoked   = errors.New("token has been revoked")
+)
 
-// Claims represents the authorization claims transmitted via a JWT.
+// Claims represents the authorization claims transmitted via a JWT. The
+// tenant is the organization the subject belongs to.
 type Claims struct {
 	jwt.RegisteredClaims
-	Roles []string `json:"roles"`
+	Roles         []string `json:"roles"`
+	Tenant        string   `json:"tenant"`
+	EmailVerified bool     `json:"email_verified"`
 }
 
 // KeyLookup declares a method set of behavior for looking up
 // private and public keys for JWT use. The return could be a
-// PEM encoded string or a JWS based key.
+// PEM encoded string or a JWS based key. The active kid identifies
+// the key used to sign new tokens and the public keys are the keys
+// tokens can be verified with, keyed by their kid.
 type KeyLookup interface {
+	ActiveKID() string
 	PrivateKey(kid string) (key string, err error)
 	PublicKey(kid string) (key string, err error)
+	PublicKeys() map[string]string
 }
 
 // Config represents information required to initialize auth.
@@ -48,29 +60,55 @@ type Config struct {
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
-	keyLookup KeyLookup
-	userBus   *userbus.Business
-	method    jwt.SigningMethod
-	parser    *jwt.Parser
-	issuer    string
+	keyLookup  KeyLookup
+	userBus    *userbus.Business
+	tokenBus   *tokenbus.Business
+	authorizer *Authorizer
+	parser     *jwt.Parser
+	issuer     string
+	authnQry   rego.PreparedEvalQuery
 }
 
 // New creates an Auth to support authentication/authorization.
 func New(cfg Config) (*Auth, error) {
 
 	// If a database connection is not provided, we won't perform the
-	// user enabled check.
+	// user enabled and revoked token checks.
 	var userBus *userbus.Business
+	var tokenBus *tokenbus.Business
 	if cfg.DB != nil {
-		userBus = userbus.NewBusiness(cfg.Log, nil, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), 10*time.Minute))
+		userBus = userbus.NewBusiness(cfg.Log, nil, nil, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), 10*time.Minute))
+		tokenBus = tokenbus.NewBusiness(cfg.Log, nil, tokendb.NewStore(cfg.Log, cfg.DB))
+	}
+
+	// The policies are compiled once here since compiling them is far more
+	// expensive than evaluating them. Prepared queries are safe to evaluate
+	// concurrently.
+	ctx := context.Background()
+
+	authnQry, err := prepareQuery(ctx, regoAuthentication, RuleAuthenticate)
+	if err != nil {
+		return nil, fmt.Errorf("prepare rule[%s]: %w", RuleAuthenticate, err)
+	}
+
+	// The auth service answers for other services so its decisions are
+	// not cached.
+	authorizer, err := NewAuthorizer(AuthorizerConfig{
+		Log: cfg.Log,
+		DB:  cfg.DB,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("authorizer: %w", err)
 	}
 
 	a := Auth{
-		keyLookup: cfg.KeyLookup,
-		userBus:   userBus,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
-		issuer:    cfg.Issuer,
+		keyLookup:  cfg.KeyLookup,
+		userBus:    userBus,
+		tokenBus:   tokenBus,
+		authorizer: authorizer,
+		parser:     jwt.NewParser(jwt.WithValidMethods(signingMethods)),
+		issuer:     cfg.Issuer,
+		authnQry:   authnQry,
 	}
 
 	return &a, nil
@@ -81,21 +119,37 @@ func (a *Auth) Issuer() string {
 	return a.issuer
 }
 
+// ActiveKID provides the kid of the key used to sign new tokens.
+func (a *Auth) ActiveKID() string {
+	return a.keyLookup.ActiveKID()
+}
+
 // GenerateToken generates a signed JWT token string representing the user Claims.
+// The claims must identify the tenant of the user. A token id is assigned
+// when the claims don't have one so the token can be revoked.
 func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
-	token := jwt.NewWithClaims(a.method, claims)
-	token.Header["kid"] = kid
+	if _, err := uuid.Parse(claims.Tenant); err != nil {
+		return "", fmt.Errorf("parsing tenant: %w", err)
+	}
+
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
 
 	privateKeyPEM, err := a.keyLookup.PrivateKey(kid)
 	if err != nil {
 		return "", fmt.Errorf("private key: %w", err)
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	// The signing method follows the type of the key.
+	privateKey, method, err := parsePrivateKey(privateKeyPEM)
 	if err != nil {
 		return "", fmt.Errorf("parsing private pem: %w", err)
 	}
 
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
 	str, err := token.SignedString(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
@@ -113,7 +167,7 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 	jwt := bearerToken[7:]
 
 	var claims Claims
-	token, _, err := a.parser.ParseUnverified(jwt, &claims)
+	token, parts, err := a.parser.ParseUnverified(jwt, &claims)
 	if err != nil {
 		return Claims{}, fmt.Errorf("error parsing token: %w", err)
 	}
@@ -133,16 +187,43 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, fmt.Errorf("failed to fetch public key: %w", err)
 	}
 
+	// The token has to be signed with the method of the key so a token
+	// can't choose the algorithm it's verified with.
+	publicKey, method, err := parsePublicKey(pem)
+	if err != nil {
+		return Claims{}, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	if token.Method.Alg() != method.Alg() {
+		return Claims{}, fmt.Errorf("token signed with %s, key requires %s", token.Method.Alg(), method.Alg())
+	}
+
 	input := map[string]any{
 		"Key":   pem,
 		"Token": jwt,
 		"ISS":   a.issuer,
+		"ALG":   method.Alg(),
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthentication, RuleAuthenticate, input); err != nil {
+	// OPA can't verify EdDSA signatures so they are verified here and the
+	// policy checks the rest of the token.
+	if method.Alg() == "EdDSA" {
+		if err := method.Verify(parts[0]+"."+parts[1], parts[2], publicKey); err != nil {
+			return Claims{}, fmt.Errorf("authentication failed : %w", err)
+		}
+		input["Verified"] = true
+	}
+
+	if err := opaPolicyEvaluation(ctx, a.authnQry, input); err != nil {
 		return Claims{}, fmt.Errorf("authentication failed : %w", err)
 	}
 
+	// Check the database for this token to verify it wasn't revoked.
+
+	if err := a.isTokenRevoked(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("token revoked : %w", err)
+	}
+
 	// Check the database for this user to verify they are still enabled.
 
 	if err := a.isUserEnabled(ctx, claims); err != nil {
@@ -152,26 +233,53 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 	return claims, nil
 }
 
-// Authorize attempts to authorize the user with the provided input roles, if
-// none of the input roles are within the user's claims, we return an error
-// otherwise the user is authorized.
-func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
-	input := map[string]any{
-		"Roles":   claims.Roles,
-		"Subject": claims.Subject,
-		"UserID":  userID,
+// Revoke adds the token the claims were taken from to the denylist so it
+// can't be used anymore, even though it hasn't expired.
+func (a *Auth) Revoke(ctx context.Context, claims Claims) error {
+	if a.tokenBus == nil {
+		return errors.New("revoking tokens requires a database")
+	}
+
+	// The entry is only needed until the token expires, so tokens that
+	// can't be identified or never expire can't be revoked.
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return errors.New("token has no id or expiry")
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthorization, rule, input); err != nil {
-		return fmt.Errorf("rego evaluation failed : %w", err)
+	if err := a.tokenBus.Deny(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return fmt.Errorf("deny: %w", err)
 	}
 
 	return nil
 }
 
-// opaPolicyEvaluation asks opa to evaluate the token against the specified token
-// policy and public key.
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule string, input any) error {
+// Authorize attempts to authorize the user with the permissions granted to
+// the roles in the user's claims, if the rule isn't satisfied by those
+// permissions, we return an error that wraps ErrForbidden otherwise the
+// user is authorized.
+func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
+	return a.authorizer.Authorize(ctx, claims, userID, rule)
+}
+
+// policyError is returned when the policy doesn't allow the input.
+type policyError struct {
+	results rego.ResultSet
+	ok      bool
+}
+
+// Error implements the error interface.
+func (pe *policyError) Error() string {
+	return fmt.Sprintf("bindings results[%v] ok[%v]", pe.results, pe.ok)
+}
+
+// Unwrap allows the error to be identified as ErrForbidden.
+func (pe *policyError) Unwrap() error {
+	return ErrForbidden
+}
+
+// prepareQuery compiles the rule of the policy into a query that can be
+// evaluated many times.
+func prepareQuery(ctx context.Context, regoScript string, rule string) (rego.PreparedEvalQuery, error) {
 	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
 
 	q, err := rego.New(
@@ -179,9 +287,15 @@ func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule
 		rego.Module("policy.rego", regoScript),
 	).PrepareForEval(ctx)
 	if err != nil {
-		return err
+		return rego.PreparedEvalQuery{}, err
 	}
 
+	return q, nil
+}
+
+// opaPolicyEvaluation asks opa to evaluate the input against the prepared
+// query of a policy rule.
+func opaPolicyEvaluation(ctx context.Context, q rego.PreparedEvalQuery, input any) error {
 	results, err := q.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
@@ -193,7 +307,7 @@ func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule
 
 	result, ok := results[0].Bindings["x"].(bool)
 	if !ok || !result {
-		return fmt.Errorf("bindings results[%v] ok[%v]", results, ok)
+		return &policyError{results: results, ok: ok}
 	}
 
 	return nil
@@ -220,5 +334,29 @@ func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
 		return fmt.Errorf("user disabled")
 	}
 
+	if usr.TenantID.String() != claims.Tenant {
+		return fmt.Errorf("user not in tenant")
+	}
+
+	return nil
+}
+
+// isTokenRevoked hits the database and checks the token is not on the
+// denylist. If no database connection was provided or the token has no id,
+// this check is skipped.
+func (a *Auth) isTokenRevoked(ctx context.Context, claims Claims) error {
+	if a.tokenBus == nil || claims.ID == "" {
+		return nil
+	}
+
+	denied, err := a.tokenBus.IsDenied(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("query denylist: %w", err)
+	}
+
+	if denied {
+		return ErrRevoked
+	}
+
 	return nil
 }
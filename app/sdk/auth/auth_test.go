@@ -3,12 +3,21 @@ package auth_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/foundation/keystore"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -33,6 +42,132 @@ func Test_Auth(t *testing.T) {
 	t.Run("test4", test4(ath))
 	t.Run("test5", test5(ath))
 	t.Run("test6", test6(ath))
+	t.Run("jwks", jwks(ath))
+}
+
+func Test_SigningMethods(t *testing.T) {
+	log := newUnit(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate an ECDSA key: %s", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate an Ed25519 key: %s", err)
+	}
+
+	table := []struct {
+		name string
+		key  any
+		alg  string
+	}{
+		{name: "es256", key: ecKey, alg: "ES256"},
+		{name: "eddsa", key: edKey, alg: "EdDSA"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(tt.key)
+			if err != nil {
+				t.Fatalf("Should be able to marshal the key: %s", err)
+			}
+
+			ks := keystore.New()
+			if err := ks.LoadKey(tt.name, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), keystore.StateActive); err != nil {
+				t.Fatalf("Should be able to load the key: %s", err)
+			}
+
+			ath, err := auth.New(auth.Config{
+				Log:       log,
+				KeyLookup: ks,
+				Issuer:    "service project",
+			})
+			if err != nil {
+				t.Fatalf("Should be able to create an authenticator: %s", err)
+			}
+
+			claims := auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    ath.Issuer(),
+					Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+					ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+				},
+				Roles:  []string{userbus.Roles.User.String()},
+				Tenant: orgbus.DefaultID.String(),
+			}
+
+			token, err := ath.GenerateToken(tt.name, claims)
+			if err != nil {
+				t.Fatalf("Should be able to generate a JWT : %s", err)
+			}
+
+			if _, err := ath.Authenticate(context.Background(), "Bearer "+token); err != nil {
+				t.Fatalf("Should be able to authenticate the claims : %s", err)
+			}
+
+			jwks, err := ath.JWKS()
+			if err != nil {
+				t.Fatalf("Should be able to get the key set : %s", err)
+			}
+
+			if jwks.Keys[0].Algorithm != tt.alg {
+				t.Errorf("Should get the %s algorithm, got %s", tt.alg, jwks.Keys[0].Algorithm)
+			}
+		})
+	}
+}
+
+func Test_Authorizer(t *testing.T) {
+	authz, err := auth.NewAuthorizer(auth.AuthorizerConfig{
+		Log:      newUnit(t),
+		CacheTTL: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create an authorizer: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "5cf37266-3473-4006-984f-9325122678b7",
+		},
+		Roles:  []string{userbus.Roles.User.String()},
+		Tenant: orgbus.DefaultID.String(),
+	}
+
+	userID := uuid.MustParse(claims.Subject)
+
+	// The decisions are asked for twice so the second answer comes from
+	// the cache.
+	for i := 0; i < 2; i++ {
+		if err := authz.Authorize(context.Background(), claims, userID, auth.RuleAdminOrSubject); err != nil {
+			t.Errorf("Should be able to authorize the RuleAdminOrSubject claim with Roles.User only : %s", err)
+		}
+
+		err := authz.Authorize(context.Background(), claims, userID, auth.RuleAdminOnly)
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Errorf("Should get ErrForbidden for the RuleAdminOnly claim with Roles.User only, got %v", err)
+		}
+	}
+
+	// The verified email is part of the decision so a cached answer for an
+	// unverified email doesn't apply once it's verified.
+	err = authz.Authorize(context.Background(), claims, userID, auth.RuleVerified)
+	if !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("Should get ErrForbidden for the RuleVerified claim without a verified email, got %v", err)
+	}
+
+	claims.EmailVerified = true
+	if err := authz.Authorize(context.Background(), claims, userID, auth.RuleVerified); err != nil {
+		t.Errorf("Should be able to authorize the RuleVerified claim with a verified email : %s", err)
+	}
+
+	err = authz.Authorize(context.Background(), claims, userID, "rule_unknown")
+	if err == nil || errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("Should NOT be able to authorize an unknown rule, got %v", err)
+	}
 }
 
 func test1(ath *auth.Auth) func(t *testing.T) {
@@ -44,7 +179,8 @@ func test1(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.Admin.String()},
+			Roles:  []string{userbus.Roles.Admin.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 
 		token, err := ath.GenerateToken(kid, claims)
@@ -87,7 +223,8 @@ func test2(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.User.String()},
+			Roles:  []string{userbus.Roles.User.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 
 		token, err := ath.GenerateToken(kid, claims)
@@ -135,7 +272,8 @@ func test3(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.User.String()},
+			Roles:  []string{userbus.Roles.User.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 
 		token, err := ath.GenerateToken(kid, claims)
@@ -168,7 +306,8 @@ func test4(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.User.String(), userbus.Roles.Admin.String()},
+			Roles:  []string{userbus.Roles.User.String(), userbus.Roles.Admin.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 		userID := uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
@@ -200,7 +339,8 @@ func test5(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.User.String()},
+			Roles:  []string{userbus.Roles.User.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 		userID := uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
@@ -232,7 +372,8 @@ func test6(ath *auth.Auth) func(t *testing.T) {
 				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 			},
-			Roles: []string{userbus.Roles.Admin.String()},
+			Roles:  []string{userbus.Roles.Admin.String()},
+			Tenant: orgbus.DefaultID.String(),
 		}
 		userID := uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
@@ -255,9 +396,113 @@ func test6(ath *auth.Auth) func(t *testing.T) {
 	return f
 }
 
+func jwks(ath *auth.Auth) func(t *testing.T) {
+	f := func(t *testing.T) {
+		got, err := ath.JWKS()
+		if err != nil {
+			t.Fatalf("Should be able to get the key set : %s", err)
+		}
+
+		if len(got.Keys) != 1 {
+			t.Fatalf("Should get one key, got %d", len(got.Keys))
+		}
+
+		key := got.Keys[0]
+		if key.KeyID != kid || key.KeyType != "RSA" || key.Algorithm != "RS256" || key.E != "AQAB" {
+			t.Errorf("Should get the public key of the keystore, got %+v", key)
+		}
+	}
+
+	return f
+}
+
 // =============================================================================
 
-func newUnit(t *testing.T) *logger.Logger {
+// Benchmark_Authorize compares evaluating the prepared authorization query
+// with compiling the policy on every call, as was done before the queries
+// were prepared when the Auth was constructed. Both evaluate the same input.
+func Benchmark_Authorize(b *testing.B) {
+	ctx := context.Background()
+
+	ath, err := auth.New(auth.Config{
+		Log:       newUnit(b),
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+	})
+	if err != nil {
+		b.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "5cf37266-3473-4006-984f-9325122678b7",
+		},
+		Roles:  []string{userbus.Roles.User.String()},
+		Tenant: orgbus.DefaultID.String(),
+	}
+
+	userID := uuid.MustParse(claims.Subject)
+
+	input, err := auth.PolicyInput(ctx, ath, claims, userID, auth.RuleAdminOrSubject)
+	if err != nil {
+		b.Fatalf("Should be able to build the policy input: %s", err)
+	}
+
+	b.Run("prepared", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := auth.EvalPrepared(ctx, ath, auth.RuleAdminOrSubject, input); err != nil {
+				b.Fatalf("Should be allowed by the prepared query : %s", err)
+			}
+		}
+	})
+
+	b.Run("compiled-per-call", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := auth.EvalCompiled(ctx, auth.RuleAdminOrSubject, input); err != nil {
+				b.Fatalf("Should be allowed by the compiled query : %s", err)
+			}
+		}
+	})
+}
+
+// Benchmark_AuthorizeParallel shows the prepared queries being evaluated
+// concurrently.
+func Benchmark_AuthorizeParallel(b *testing.B) {
+	ath, err := auth.New(auth.Config{
+		Log:       newUnit(b),
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+	})
+	if err != nil {
+		b.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "5cf37266-3473-4006-984f-9325122678b7",
+		},
+		Roles:  []string{userbus.Roles.User.String()},
+		Tenant: orgbus.DefaultID.String(),
+	}
+
+	userID := uuid.MustParse(claims.Subject)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := ath.Authorize(context.Background(), claims, userID, auth.RuleAdminOrSubject); err != nil {
+				b.Errorf("Should be able to authorize : %s", err)
+				return
+			}
+		}
+	})
+}
+
+// =============================================================================
+
+func newUnit(t testing.TB) *logger.Logger {
 	var buf bytes.Buffer
 	log := logger.New("TEST")
 
@@ -278,6 +523,10 @@ func newUnit(t *testing.T) *logger.Logger {
 
 type keyStore struct{}
 
+func (ks *keyStore) ActiveKID() string {
+	return kid
+}
+
 func (ks *keyStore) PrivateKey(kid string) (string, error) {
 	return privateKeyPEM, nil
 }
@@ -286,6 +535,10 @@ func (ks *keyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+func (ks *keyStore) PublicKeys() map[string]string {
+	return map[string]string{kid: publicKeyPEM}
+}
+
 const (
 	kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ"
 
@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/rolebus/stores/roledb"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// maxDecisions is the number of decisions the cache holds before the expired
+// decisions are removed.
+const maxDecisions = 10_000
+
+// AuthorizerConfig represents information required to initialize an
+// authorizer. When the cache ttl is zero, decisions are not cached.
+type AuthorizerConfig struct {
+	Log      *logger.Logger
+	DB       *sqlx.DB
+	CacheTTL time.Duration
+}
+
+// decisionKey identifies the input of an authorization decision.
+type decisionKey struct {
+	roles    string
+	subject  string
+	verified bool
+	userID   uuid.UUID
+	rule     string
+}
+
+// decision represents the result of an authorization and when it expires.
+type decision struct {
+	err     error
+	expires time.Time
+}
+
+// Authorizer evaluates the authorization policy in process so a service
+// doesn't have to call the auth service for every request. The permissions
+// of the roles and the permissions that satisfy each rule are read from the
+// database. Decisions are cached for a short time since they rarely change.
+type Authorizer struct {
+	roleBus *rolebus.Business
+	queries map[string]rego.PreparedEvalQuery
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	decisions map[decisionKey]decision
+}
+
+// NewAuthorizer constructs an authorizer with the authorization policy
+// compiled and ready for use.
+func NewAuthorizer(cfg AuthorizerConfig) (*Authorizer, error) {
+
+	// If a database connection is not provided, only the built-in roles
+	// have permissions.
+	var roleBus *rolebus.Business
+	if cfg.DB != nil {
+		roleBus = rolebus.NewBusiness(cfg.Log, roledb.NewStore(cfg.Log, cfg.DB))
+	}
+
+	// The policy is compiled once here since compiling it is far more
+	// expensive than evaluating it. Prepared queries are safe to evaluate
+	// concurrently.
+	ctx := context.Background()
+
+	queries := make(map[string]rego.PreparedEvalQuery, len(authorizationRules))
+	for _, rule := range authorizationRules {
+		q, err := prepareQuery(ctx, regoAuthorization, rule)
+		if err != nil {
+			return nil, fmt.Errorf("prepare rule[%s]: %w", rule, err)
+		}
+		queries[rule] = q
+	}
+
+	az := Authorizer{
+		roleBus:   roleBus,
+		queries:   queries,
+		ttl:       cfg.CacheTTL,
+		decisions: make(map[decisionKey]decision),
+	}
+
+	return &az, nil
+}
+
+// Authorize attempts to authorize the user with the permissions granted to
+// the roles in the user's claims. If the rule isn't satisfied by those
+// permissions, an error that wraps ErrForbidden is returned.
+func (az *Authorizer) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
+	q, exists := az.queries[rule]
+	if !exists {
+		return fmt.Errorf("unknown rule %q", rule)
+	}
+
+	key := newDecisionKey(claims, userID, rule)
+
+	if err, found := az.lookup(key); found {
+		return err
+	}
+
+	input, err := az.input(ctx, claims, userID, rule)
+	if err != nil {
+		return err
+	}
+
+	err = opaPolicyEvaluation(ctx, q, input)
+	if err != nil {
+		err = fmt.Errorf("rego evaluation failed : %w", err)
+	}
+
+	// Only decisions are cached. A failure to evaluate the policy has to
+	// be tried again.
+	if err == nil || errors.Is(err, ErrForbidden) {
+		az.store(key, err)
+	}
+
+	return err
+}
+
+// =============================================================================
+
+func newDecisionKey(claims Claims, userID uuid.UUID, rule string) decisionKey {
+	roles := slices.Clone(claims.Roles)
+	slices.Sort(roles)
+
+	return decisionKey{
+		roles:    strings.Join(roles, ","),
+		subject:  claims.Subject,
+		verified: claims.EmailVerified,
+		userID:   userID,
+		rule:     rule,
+	}
+}
+
+func (az *Authorizer) lookup(key decisionKey) (error, bool) {
+	if az.ttl <= 0 {
+		return nil, false
+	}
+
+	az.mu.Lock()
+	defer az.mu.Unlock()
+
+	d, found := az.decisions[key]
+	if !found {
+		return nil, false
+	}
+
+	if time.Now().After(d.expires) {
+		delete(az.decisions, key)
+		return nil, false
+	}
+
+	return d.err, true
+}
+
+func (az *Authorizer) store(key decisionKey, err error) {
+	if az.ttl <= 0 {
+		return
+	}
+
+	az.mu.Lock()
+	defer az.mu.Unlock()
+
+	now := time.Now()
+
+	if len(az.decisions) >= maxDecisions {
+		for k, d := range az.decisions {
+			if now.After(d.expires) {
+				delete(az.decisions, k)
+			}
+		}
+
+		// Every decision is still valid so start over rather than let
+		// the cache grow without bounds.
+		if len(az.decisions) >= maxDecisions {
+			az.decisions = make(map[decisionKey]decision)
+		}
+	}
+
+	az.decisions[key] = decision{
+		err:     err,
+		expires: now.Add(az.ttl),
+	}
+}
+
+// input builds the input the rule is evaluated with from the claims and the
+// permissions granted to the roles in the claims.
+func (az *Authorizer) input(ctx context.Context, claims Claims, userID uuid.UUID, rule string) (map[string]any, error) {
+	perms, err := az.permissions(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("permissions: %w", err)
+	}
+
+	rp, err := az.rulePermissions(ctx, rule)
+	if err != nil {
+		return nil, fmt.Errorf("rulepermissions: %w", err)
+	}
+
+	input := map[string]any{
+		"Roles":              claims.Roles,
+		"Permissions":        perms,
+		"RulePermissions":    rolebus.ParsePermissionsToString(rp.Permissions),
+		"SubjectPermissions": rolebus.ParsePermissionsToString(rp.Subject),
+		"Subject":            claims.Subject,
+		"EmailVerified":      claims.EmailVerified,
+		"UserID":             userID,
+	}
+
+	return input, nil
+}
+
+// permissions resolves the permissions granted to the roles in the claims.
+// Roles that are unknown are ignored. If no database connection was provided,
+// the permissions of the built-in roles are used.
+func (az *Authorizer) permissions(ctx context.Context, claims Claims) ([]string, error) {
+	if az.roleBus == nil {
+		var perms []string
+		for _, role := range claims.Roles {
+			perms = append(perms, defaultPermissions[role]...)
+		}
+		return perms, nil
+	}
+
+	roles := make([]userbus.Role, 0, len(claims.Roles))
+	for _, value := range claims.Roles {
+		role, err := userbus.ParseRole(value)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, role)
+	}
+
+	perms, err := az.roleBus.QueryPermissions(ctx, roles)
+	if err != nil {
+		return nil, fmt.Errorf("query permissions: %w", err)
+	}
+
+	return rolebus.ParsePermissionsToString(perms), nil
+}
+
+// rulePermissions resolves the permissions that satisfy the rule. If no
+// database connection was provided, the permissions of the built-in rules
+// are used.
+func (az *Authorizer) rulePermissions(ctx context.Context, rule string) (rolebus.RulePermissions, error) {
+	if az.roleBus == nil {
+		return defaultRulePermissions[rule], nil
+	}
+
+	rp, err := az.roleBus.QueryRulePermissions(ctx, rule)
+	if err != nil {
+		return rolebus.RulePermissions{}, fmt.Errorf("query rule permissions: %w", err)
+	}
+
+	return rp, nil
+}
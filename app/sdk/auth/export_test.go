@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PolicyInput returns the input the authorizer of the Auth evaluates the
+// rule with for the claims.
+func PolicyInput(ctx context.Context, ath *Auth, claims Claims, userID uuid.UUID, rule string) (map[string]any, error) {
+	return ath.authorizer.input(ctx, claims, userID, rule)
+}
+
+// EvalPrepared evaluates the input against the query the authorizer of the
+// Auth prepared for the rule.
+func EvalPrepared(ctx context.Context, ath *Auth, rule string, input map[string]any) error {
+	return opaPolicyEvaluation(ctx, ath.authorizer.queries[rule], input)
+}
+
+// EvalCompiled compiles the policy for the rule before evaluating the input,
+// as was done for every call before the queries were prepared.
+func EvalCompiled(ctx context.Context, rule string, input map[string]any) error {
+	q, err := prepareQuery(ctx, regoAuthorization, rule)
+	if err != nil {
+		return err
+	}
+
+	return opaPolicyEvaluation(ctx, q, input)
+}
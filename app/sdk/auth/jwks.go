@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// JWK represents a public key in the JSON Web Key format. The fields that
+// are used depend on the type of the key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS represents the set of public keys tokens can be verified with so other
+// services can verify the tokens without calling this service.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys tokens can be verified with, ordered by kid.
+func (a *Auth) JWKS() (JWKS, error) {
+	keys := a.keyLookup.PublicKeys()
+
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{
+		Keys: make([]JWK, 0, len(kids)),
+	}
+
+	for _, kid := range kids {
+		publicKey, method, err := parsePublicKey(keys[kid])
+		if err != nil {
+			return JWKS{}, fmt.Errorf("parsing public pem: kid[%s]: %w", kid, err)
+		}
+
+		jwk, err := toJWK(publicKey)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("kid[%s]: %w", kid, err)
+		}
+
+		jwk.Use = "sig"
+		jwk.KeyID = kid
+		jwk.Algorithm = method.Alg()
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks, nil
+}
+
+func toJWK(key crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk := JWK{
+			KeyType: "RSA",
+			N:       enc.EncodeToString(k.N.Bytes()),
+			E:       enc.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}
+		return jwk, nil
+
+	case *ecdsa.PublicKey:
+		// The coordinates have the size of the curve, leading zeros included.
+		size := (k.Curve.Params().BitSize + 7) / 8
+
+		jwk := JWK{
+			KeyType: "EC",
+			Curve:   k.Curve.Params().Name,
+			X:       enc.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y:       enc.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}
+		return jwk, nil
+
+	case ed25519.PublicKey:
+		jwk := JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       enc.EncodeToString(k),
+		}
+		return jwk, nil
+	}
+
+	return JWK{}, fmt.Errorf("unsupported key type %T", key)
+}
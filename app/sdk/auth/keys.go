@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signingMethods are the methods tokens can be signed with. The method used
+// for a token follows the type of the key that signs it.
+var signingMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// signingMethod returns the signing method for the type of the public key.
+func signingMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// parsePrivateKey parses a PEM encoded PKCS1, PKCS8 or SEC1 private key and
+// returns it with the method used to sign with it.
+func parsePrivateKey(privatePEM string) (crypto.Signer, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, nil, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	// The type in the PEM header isn't always accurate so every encoding
+	// is tried.
+	var key any
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, errors.New("invalid key: key must be a PKCS1, PKCS8 or SEC1 private key")
+			}
+		}
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported key type %T", key)
+	}
+
+	method, err := signingMethod(signer.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return signer, method, nil
+}
+
+// parsePublicKey parses a PEM encoded PKIX public key and returns it with
+// the method used to verify with it.
+func parsePublicKey(publicPEM string) (crypto.PublicKey, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return nil, nil, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	method, err := signingMethod(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, method, nil
+}
@@ -4,12 +4,34 @@ import rego.v1
 
 default auth := false
 
+# OPA verifies the RSA and ECDSA signatures itself. The algorithm is pinned to
+# the one that belongs to the key so a token can't choose another one.
 auth if {
+	input.ALG != "EdDSA"
 	[valid, _, _] := verify_jwt
 	valid = true
 }
 
+# OPA can't verify EdDSA signatures, so the signature is verified before the
+# policy is evaluated and only the header and claims are checked here.
+auth if {
+	input.ALG == "EdDSA"
+	input.Verified == true
+	[header, payload, _] := io.jwt.decode(input.Token)
+	header.alg == "EdDSA"
+	payload.iss == input.ISS
+	not expired(payload)
+	not premature(payload)
+}
+
 verify_jwt := io.jwt.decode_verify(input.Token, {
 	"cert": input.Key,
 	"iss": input.ISS,
+	"alg": input.ALG,
 })
+
+now := time.now_ns() / 1000000000
+
+expired(payload) if payload.exp <= now
+
+premature(payload) if payload.nbf > now
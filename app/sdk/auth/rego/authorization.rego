@@ -10,37 +10,34 @@ default rule_user_only := false
 
 default rule_admin_or_subject := false
 
-role_user := "USER"
+default rule_verified := false
 
-role_admin := "ADMIN"
+# The permissions that satisfy the rule being evaluated are part of the
+# input, so roles and permissions can be granted without changing the policy.
+# The subject permissions only satisfy the rule for the user's own data.
 
-role_all := {role_admin, role_user}
-
-rule_any if {
-	claim_roles := {role | some role in input.Roles}
-	input_roles := role_all & claim_roles
-	count(input_roles) > 0
+granted if {
+	some perm in input.Permissions
+	perm in input.RulePermissions
 }
 
-rule_admin_only if {
-	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
-	count(input_admin) > 0
+granted_for_subject if {
+	some perm in input.Permissions
+	perm in input.SubjectPermissions
+	input.UserID == input.Subject
 }
 
-rule_user_only if {
-	claim_roles := {role | some role in input.Roles}
-	input_user := {role_user} & claim_roles
-	count(input_user) > 0
-}
+rule_any if granted
 
-rule_admin_or_subject if {
-	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
-	count(input_admin) > 0
-} else if {
-	claim_roles := {role | some role in input.Roles}
-	input_user := {role_user} & claim_roles
-	count(input_user) > 0
-	input.UserID == input.Subject
+rule_admin_only if granted
+
+rule_user_only if granted
+
+rule_admin_or_subject if granted
+
+rule_admin_or_subject if granted_for_subject
+
+rule_verified if {
+	granted
+	input.EmailVerified == true
 }
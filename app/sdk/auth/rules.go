@@ -2,6 +2,10 @@ package auth
 
 import (
 	_ "embed"
+	"slices"
+
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
 )
 
 // These the current set of rules we have for auth.
@@ -11,8 +15,24 @@ const (
 	RuleAdminOnly      = "rule_admin_only"
 	RuleUserOnly       = "rule_user_only"
 	RuleAdminOrSubject = "rule_admin_or_subject"
+	RuleVerified       = "rule_verified"
 )
 
+// authorizationRules are the rules of the authorization policy a request
+// can be authorized with.
+var authorizationRules = []string{
+	RuleAny,
+	RuleAdminOnly,
+	RuleUserOnly,
+	RuleAdminOrSubject,
+	RuleVerified,
+}
+
+// IsRule reports whether the rule is part of the authorization policy.
+func IsRule(rule string) bool {
+	return slices.Contains(authorizationRules, rule)
+}
+
 // Package name of our rego code.
 const (
 	opaPackage string = "ardan.rego"
@@ -26,3 +46,31 @@ var (
 	//go:embed rego/authorization.rego
 	regoAuthorization string
 )
+
+// defaultPermissions are the permissions the built-in roles are granted when
+// the roles can't be looked up in the database.
+var defaultPermissions = map[string][]string{
+	userbus.Roles.Admin.String(): {rolebus.Permissions.Admin.String()},
+	userbus.Roles.User.String():  {rolebus.Permissions.User.String()},
+}
+
+// defaultRulePermissions are the permissions that satisfy each rule when the
+// rules can't be looked up in the database.
+var defaultRulePermissions = map[string]rolebus.RulePermissions{
+	RuleAny: {
+		Permissions: []rolebus.Permission{rolebus.Permissions.Admin, rolebus.Permissions.User},
+	},
+	RuleAdminOnly: {
+		Permissions: []rolebus.Permission{rolebus.Permissions.Admin},
+	},
+	RuleUserOnly: {
+		Permissions: []rolebus.Permission{rolebus.Permissions.User},
+	},
+	RuleAdminOrSubject: {
+		Permissions: []rolebus.Permission{rolebus.Permissions.Admin},
+		Subject:     []rolebus.Permission{rolebus.Permissions.User},
+	},
+	RuleVerified: {
+		Permissions: []rolebus.Permission{rolebus.Permissions.Admin, rolebus.Permissions.User},
+	},
+}
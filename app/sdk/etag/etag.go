@@ -0,0 +1,50 @@
+// Package etag provides support for the entity tags used to detect that a
+// resource changed between the time a client read it and tried to write it.
+package etag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set of error variables for the checks of the If-Match header.
+var (
+	ErrRequired = errors.New("if-match header is required")
+	ErrMismatch = errors.New("resource was modified since it was read")
+)
+
+// Precondition represents the conditional headers a client can provide on
+// requests that don't carry a body.
+type Precondition struct {
+	IfMatch string `header:"If-Match"`
+}
+
+// Format returns the entity tag for the specified version of a resource.
+func Format(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Check compares the value of an If-Match header against the specified
+// version of a resource. Writes are conditional, so ErrRequired is returned
+// when the header is empty. Only a wildcard matches any version.
+func Check(ifMatch string, version int) error {
+	ifMatch = strings.TrimSpace(ifMatch)
+	switch ifMatch {
+	case "":
+		return ErrRequired
+	case "*":
+		return nil
+	}
+
+	current := Format(version)
+
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == current {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("if-match[%s] etag[%s]: %w", ifMatch, current, ErrMismatch)
+}
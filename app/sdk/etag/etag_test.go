@@ -0,0 +1,34 @@
+package etag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ardanlabs/encore/app/sdk/etag"
+)
+
+func Test_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		version int
+		err     error
+	}{
+		{name: "empty", ifMatch: "", version: 3, err: etag.ErrRequired},
+		{name: "blank", ifMatch: "  ", version: 3, err: etag.ErrRequired},
+		{name: "wildcard", ifMatch: "*", version: 3},
+		{name: "match", ifMatch: `"3"`, version: 3},
+		{name: "list", ifMatch: `"1", "3"`, version: 3},
+		{name: "mismatch", ifMatch: `"2"`, version: 3, err: etag.ErrMismatch},
+		{name: "unquoted", ifMatch: "3", version: 3, err: etag.ErrMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := etag.Check(tt.ifMatch, tt.version)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Should get back the expected error: got %v, exp %v", err, tt.err)
+			}
+		})
+	}
+}
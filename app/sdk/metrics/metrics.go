@@ -4,6 +4,7 @@ package metrics
 import (
 	"expvar"
 	"runtime"
+	"time"
 
 	"encore.dev"
 	"encore.dev/metrics"
@@ -13,6 +14,13 @@ var devGoroutines = expvar.NewInt("goroutines")
 var devRequests = expvar.NewInt("requests")
 var devFailures = expvar.NewInt("errors")
 var devPanics = expvar.NewInt("panics")
+var devDelegate = expvar.NewMap("delegate")
+
+// DelegateLabels identifies the delegate functions a metric is recorded for.
+type DelegateLabels struct {
+	Domain string
+	Action string
+}
 
 // Config lists the set of metrics that is tracked.
 type Config struct {
@@ -20,33 +28,47 @@ type Config struct {
 	Requests   *metrics.Counter[uint64]
 	Failures   *metrics.Counter[uint64]
 	Panics     *metrics.Counter[uint64]
+
+	// The latency is the total time in milliseconds the delegate functions
+	// took, so the average is the latency over the successes and failures.
+	DelegateSuccesses *metrics.CounterGroup[DelegateLabels, uint64]
+	DelegateFailures  *metrics.CounterGroup[DelegateLabels, uint64]
+	DelegateLatency   *metrics.CounterGroup[DelegateLabels, uint64]
 }
 
 // Values provides an api to work with metrics.
 type Values struct {
-	devEnv        bool
-	goroutines    *metrics.Gauge[uint64]
-	requests      *metrics.Counter[uint64]
-	failures      *metrics.Counter[uint64]
-	panics        *metrics.Counter[uint64]
-	devGoroutines *expvar.Int
-	devRequests   *expvar.Int
-	devFailures   *expvar.Int
-	devPanics     *expvar.Int
+	devEnv            bool
+	goroutines        *metrics.Gauge[uint64]
+	requests          *metrics.Counter[uint64]
+	failures          *metrics.Counter[uint64]
+	panics            *metrics.Counter[uint64]
+	delegateSuccesses *metrics.CounterGroup[DelegateLabels, uint64]
+	delegateFailures  *metrics.CounterGroup[DelegateLabels, uint64]
+	delegateLatency   *metrics.CounterGroup[DelegateLabels, uint64]
+	devGoroutines     *expvar.Int
+	devRequests       *expvar.Int
+	devFailures       *expvar.Int
+	devPanics         *expvar.Int
+	devDelegate       *expvar.Map
 }
 
 // New constructs a Values for working with metrics.
 func New(cfg Config) *Values {
 	return &Values{
-		devEnv:        encore.Meta().Environment.Type == encore.EnvDevelopment,
-		goroutines:    cfg.Goroutines,
-		requests:      cfg.Requests,
-		failures:      cfg.Failures,
-		panics:        cfg.Panics,
-		devGoroutines: devGoroutines,
-		devRequests:   devRequests,
-		devFailures:   devFailures,
-		devPanics:     devPanics,
+		devEnv:            encore.Meta().Environment.Type == encore.EnvDevelopment,
+		goroutines:        cfg.Goroutines,
+		requests:          cfg.Requests,
+		failures:          cfg.Failures,
+		panics:            cfg.Panics,
+		delegateSuccesses: cfg.DelegateSuccesses,
+		delegateFailures:  cfg.DelegateFailures,
+		delegateLatency:   cfg.DelegateLatency,
+		devGoroutines:     devGoroutines,
+		devRequests:       devRequests,
+		devFailures:       devFailures,
+		devPanics:         devPanics,
+		devDelegate:       devDelegate,
 	}
 }
 
@@ -85,3 +107,38 @@ func (v *Values) IncPanics() {
 		v.devPanics.Add(1)
 	}
 }
+
+// IncDelegateSuccesses increments the successes of the delegate functions for
+// the domain and action by 1 and adds the time they took to the latency.
+func (v *Values) IncDelegateSuccesses(domain string, action string, took time.Duration) {
+	v.incDelegate(v.delegateSuccesses, "successes", domain, action, took)
+}
+
+// IncDelegateFailures increments the failures of the delegate functions for
+// the domain and action by 1 and adds the time they took to the latency.
+func (v *Values) IncDelegateFailures(domain string, action string, took time.Duration) {
+	v.incDelegate(v.delegateFailures, "failures", domain, action, took)
+}
+
+func (v *Values) incDelegate(counter *metrics.CounterGroup[DelegateLabels, uint64], name string, domain string, action string, took time.Duration) {
+	labels := DelegateLabels{
+		Domain: domain,
+		Action: action,
+	}
+
+	ms := uint64(took.Milliseconds())
+
+	if counter != nil {
+		counter.With(labels).Add(1)
+	}
+
+	if v.delegateLatency != nil {
+		v.delegateLatency.With(labels).Add(ms)
+	}
+
+	if v.devEnv {
+		key := domain + "." + action
+		v.devDelegate.Add(key+"."+name, 1)
+		v.devDelegate.Add(key+".latency_ms", int64(ms))
+	}
+}
@@ -3,6 +3,7 @@ package mid
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/mail"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	eauth "encore.dev/beta/auth"
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/app/sdk/errs"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -35,8 +38,9 @@ func Bearer(ctx context.Context, ath *auth.Auth, authorization string) (eauth.UI
 	return eauth.UID(subjectID.String()), &claims, nil
 }
 
-// Basic processes basic authentication logic.
-func Basic(ctx context.Context, ath *auth.Auth, userBus *userbus.Business, authorization string) (eauth.UID, *auth.Claims, error) {
+// Basic processes basic authentication logic. The email and the client ip
+// are locked out after too many failed attempts.
+func Basic(ctx context.Context, ath *auth.Auth, lockoutBus *lockoutbus.Business, authorization string, ip string) (eauth.UID, *auth.Claims, error) {
 	email, pass, ok := parseBasicAuth(authorization)
 	if !ok {
 		return "", nil, errs.Newf(errs.Unauthenticated, "invalid Basic auth")
@@ -47,8 +51,11 @@ func Basic(ctx context.Context, ath *auth.Auth, userBus *userbus.Business, autho
 		return "", nil, errs.New(errs.Unauthenticated, err)
 	}
 
-	usr, err := userBus.Authenticate(ctx, *addr, pass)
+	usr, err := lockoutBus.Authenticate(ctx, *addr, ip, pass)
 	if err != nil {
+		if errors.Is(err, lockoutbus.ErrLocked) {
+			return "", nil, errs.New(errs.ResourceExhausted, lockoutbus.ErrLocked)
+		}
 		return "", nil, errs.New(errs.Unauthenticated, err)
 	}
 
@@ -59,7 +66,9 @@ func Basic(ctx context.Context, ath *auth.Auth, userBus *userbus.Business, autho
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8760 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: userbus.ParseRolesToString(usr.Roles),
+		Roles:         userbus.ParseRolesToString(usr.Roles),
+		Tenant:        usr.TenantID.String(),
+		EmailVerified: usr.EmailVerified,
 	}
 
 	subjectID, err := uuid.Parse(claims.Subject)
@@ -70,6 +79,51 @@ func Basic(ctx context.Context, ath *auth.Auth, userBus *userbus.Business, autho
 	return eauth.UID(subjectID.String()), &claims, nil
 }
 
+// APIKey processes api key authentication logic. The roles of the claims are
+// the scopes of the key and the claims expire with the key.
+func APIKey(ctx context.Context, ath *auth.Auth, apiKeyBus *apikeybus.Business, authorization string) (eauth.UID, *auth.Claims, error) {
+	key, ok := parseAPIKey(authorization)
+	if !ok {
+		return "", nil, errs.Newf(errs.Unauthenticated, "invalid ApiKey auth")
+	}
+
+	ak, usr, err := apiKeyBus.Authenticate(ctx, key)
+	if err != nil {
+		return "", nil, errs.New(errs.Unauthenticated, err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   ak.UserID.String(),
+			Issuer:    ath.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(ak.DateExpires.UTC()),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Roles:         userbus.ParseRolesToString(ak.Scopes),
+		Tenant:        usr.TenantID.String(),
+		EmailVerified: usr.EmailVerified,
+	}
+
+	return eauth.UID(ak.UserID.String()), &claims, nil
+}
+
+// ClientIP returns the ip of the client from the X-Forwarded-For header. The
+// last address is used since it was added by the gateway in front of the
+// service, the others are provided by the client and can't be trusted.
+func ClientIP(forwardedFor string) string {
+	addrs := strings.Split(forwardedFor, ",")
+	return strings.TrimSpace(addrs[len(addrs)-1])
+}
+
+func parseAPIKey(auth string) (string, bool) {
+	parts := strings.Split(auth, " ")
+	if len(parts) != 2 || parts[0] != "ApiKey" || parts[1] == "" {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
 func parseBasicAuth(auth string) (string, string, bool) {
 	parts := strings.Split(auth, " ")
 	if len(parts) != 2 || parts[0] != "Basic" {
@@ -1,13 +1,16 @@
 package mid
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	eauth "encore.dev/beta/auth"
 	"encore.dev/middleware"
 	"github.com/ardanlabs/encore/app/sdk/auth"
+	"github.com/ardanlabs/encore/app/sdk/errs"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/google/uuid"
@@ -20,6 +23,11 @@ var ErrInvalidID = errors.New("ID is not in its proper form")
 func Authorize(req middleware.Request) (AuthInfo, middleware.Request, error) {
 	claims := eauth.Data().(*auth.Claims)
 
+	req, _, err := setTenant(req, claims)
+	if err != nil {
+		return AuthInfo{}, req, err
+	}
+
 	rule := auth.RuleAdminOnly
 	for _, tag := range req.Data().API.Tags {
 		switch tag {
@@ -27,6 +35,8 @@ func Authorize(req middleware.Request) (AuthInfo, middleware.Request, error) {
 			rule = auth.RuleAny
 		case "as_user_role":
 			rule = auth.RuleUserOnly
+		case "as_verified_email":
+			rule = auth.RuleVerified
 		}
 	}
 
@@ -36,10 +46,9 @@ func Authorize(req middleware.Request) (AuthInfo, middleware.Request, error) {
 		Rule:   rule,
 	}
 
-	// We should call the Auth Service from here and keep things in the app
-	// layer but Encore won't allow it. The API layer middleware calls
-	// this function first and then calls the Auth Service. This is the same
-	// for the other Authorize middleware functions.
+	// The API layer middleware calls this function first and then checks
+	// the authorization information with CheckAuthorization. This is the
+	// same for the other Authorize middleware functions.
 
 	return authInfo, req, nil
 }
@@ -47,6 +56,13 @@ func Authorize(req middleware.Request) (AuthInfo, middleware.Request, error) {
 // AuthorizeUser checks the user making the call has specified a user id on
 // the route that matches the claims.
 func AuthorizeUser(userBus *userbus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
+	claims := eauth.Data().(*auth.Claims)
+
+	req, tenantID, err := setTenant(req, claims)
+	if err != nil {
+		return AuthInfo{}, req, err
+	}
+
 	ctx := req.Context()
 	var userID uuid.UUID
 
@@ -61,7 +77,6 @@ func AuthorizeUser(userBus *userbus.Business, req middleware.Request) (AuthInfo,
 	if len(req.Data().PathParams) == 1 {
 		id := req.Data().PathParams[0]
 
-		var err error
 		userID, err = uuid.Parse(id.Value)
 		if err != nil {
 			return AuthInfo{}, req, ErrInvalidID
@@ -78,11 +93,15 @@ func AuthorizeUser(userBus *userbus.Business, req middleware.Request) (AuthInfo,
 			}
 		}
 
+		// The row level security policies hide the rows of other tenants but
+		// the check is repeated so it holds without them.
+		if usr.TenantID != tenantID {
+			return AuthInfo{}, req, userbus.ErrNotFound
+		}
+
 		req = setUser(req, usr)
 	}
 
-	claims := eauth.Data().(*auth.Claims)
-
 	authInfo := AuthInfo{
 		Claims: *claims,
 		UserID: userID,
@@ -95,6 +114,13 @@ func AuthorizeUser(userBus *userbus.Business, req middleware.Request) (AuthInfo,
 // AuthorizeProduct checks the user making the call has specified a product id on
 // the route that matches the claims.
 func AuthorizeProduct(productBus *productbus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
+	claims := eauth.Data().(*auth.Claims)
+
+	req, tenantID, err := setTenant(req, claims)
+	if err != nil {
+		return AuthInfo{}, req, err
+	}
+
 	ctx := req.Context()
 	var userID uuid.UUID
 
@@ -117,12 +143,14 @@ func AuthorizeProduct(productBus *productbus.Business, req middleware.Request) (
 			}
 		}
 
+		if prd.TenantID != tenantID {
+			return AuthInfo{}, req, productbus.ErrNotFound
+		}
+
 		userID = prd.UserID
 		req = setProduct(req, prd)
 	}
 
-	claims := eauth.Data().(*auth.Claims)
-
 	authInfo := AuthInfo{
 		Claims: *claims,
 		UserID: userID,
@@ -135,6 +163,13 @@ func AuthorizeProduct(productBus *productbus.Business, req middleware.Request) (
 // AuthorizeHome checks the user making the call has specified a home id on
 // the route that matches the claims.
 func AuthorizeHome(homeBus *homebus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
+	claims := eauth.Data().(*auth.Claims)
+
+	req, tenantID, err := setTenant(req, claims)
+	if err != nil {
+		return AuthInfo{}, req, err
+	}
+
 	ctx := req.Context()
 	var userID uuid.UUID
 
@@ -157,12 +192,63 @@ func AuthorizeHome(homeBus *homebus.Business, req middleware.Request) (AuthInfo,
 			}
 		}
 
+		if hme.TenantID != tenantID {
+			return AuthInfo{}, req, homebus.ErrNotFound
+		}
+
 		userID = hme.UserID
 		req = setHome(req, hme)
 	}
 
+	authInfo := AuthInfo{
+		Claims: *claims,
+		UserID: userID,
+		Rule:   auth.RuleAdminOrSubject,
+	}
+
+	return authInfo, req, nil
+}
+
+// AuthorizeOrder checks the user making the call has specified an order id on
+// the route that matches the claims.
+func AuthorizeOrder(orderBus *orderbus.Business, req middleware.Request) (AuthInfo, middleware.Request, error) {
 	claims := eauth.Data().(*auth.Claims)
 
+	req, tenantID, err := setTenant(req, claims)
+	if err != nil {
+		return AuthInfo{}, req, err
+	}
+
+	ctx := req.Context()
+	var userID uuid.UUID
+
+	if len(req.Data().PathParams) == 1 {
+		id := req.Data().PathParams[0]
+
+		orderID, err := uuid.Parse(id.Value)
+		if err != nil {
+			return AuthInfo{}, req, ErrInvalidID
+		}
+
+		ord, err := orderBus.QueryByID(ctx, orderID)
+		if err != nil {
+			switch {
+			case errors.Is(err, orderbus.ErrNotFound):
+				return AuthInfo{}, req, err
+
+			default:
+				return AuthInfo{}, req, fmt.Errorf("querybyid: orderID[%s]: %s", orderID, err)
+			}
+		}
+
+		if ord.TenantID != tenantID {
+			return AuthInfo{}, req, orderbus.ErrNotFound
+		}
+
+		userID = ord.UserID
+		req = setOrder(req, ord)
+	}
+
 	authInfo := AuthInfo{
 		Claims: *claims,
 		UserID: userID,
@@ -171,3 +257,27 @@ func AuthorizeHome(homeBus *homebus.Business, req middleware.Request) (AuthInfo,
 
 	return authInfo, req, nil
 }
+
+// =============================================================================
+
+// Authorizer declares the behavior required to authorize a request.
+type Authorizer interface {
+	Authorize(ctx context.Context, claims auth.Claims, userID uuid.UUID, rule string) error
+}
+
+// CheckAuthorization evaluates the authorization information with the
+// authorizer. A request that isn't authorized gets an unauthenticated error,
+// a failure to evaluate the policy gets an internal error.
+func CheckAuthorization(ctx context.Context, authz Authorizer, authInfo AuthInfo) error {
+	err := authz.Authorize(ctx, authInfo.Claims, authInfo.UserID, authInfo.Rule)
+	switch {
+	case err == nil:
+		return nil
+
+	case errors.Is(err, auth.ErrForbidden):
+		return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", authInfo.Claims.Roles, authInfo.Rule, err)
+
+	default:
+		return errs.Newf(errs.Internal, "authorize: claims[%v] rule[%v]: %s", authInfo.Claims.Roles, authInfo.Rule, err)
+	}
+}
@@ -4,11 +4,13 @@ package mid
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	eauth "encore.dev/beta/auth"
 	"encore.dev/middleware"
 	"github.com/ardanlabs/encore/app/sdk/auth"
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -31,9 +33,48 @@ const (
 	userKey
 	productKey
 	homeKey
+	orderKey
 	trKey
 )
 
+// setTenant scopes the database access made for the request to the tenant in
+// the claims.
+func setTenant(req middleware.Request, claims *auth.Claims) (middleware.Request, uuid.UUID, error) {
+	tenantID, err := uuid.Parse(claims.Tenant)
+	if err != nil {
+		return req, uuid.UUID{}, fmt.Errorf("parse tenant: %w", err)
+	}
+
+	ctx := sqldb.WithTenant(req.Context(), tenantID)
+	return req.WithContext(ctx), tenantID, nil
+}
+
+// Tenancy scopes the database access made for the request to the tenant in
+// the claims when the request is authenticated. A request that isn't is left
+// without a tenant so the row level security policies deny it every row of
+// the tenant tables. The endpoints that must act for the system, like
+// signing in, say so themselves.
+func Tenancy(req middleware.Request) (middleware.Request, error) {
+	claims, ok := eauth.Data().(*auth.Claims)
+	if !ok {
+		return req, nil
+	}
+
+	req, _, err := setTenant(req, claims)
+
+	return req, err
+}
+
+// GetTenantID extracts the tenant id from the context.
+func GetTenantID(ctx context.Context) (uuid.UUID, error) {
+	v, ok := sqldb.GetTenant(ctx)
+	if !ok {
+		return uuid.UUID{}, errors.New("tenant id not found")
+	}
+
+	return v, nil
+}
+
 func setUser(req middleware.Request, usr userbus.User) middleware.Request {
 	ctx := context.WithValue(req.Context(), userKey, usr)
 	return req.WithContext(ctx)
@@ -94,6 +135,21 @@ func GetHome(ctx context.Context) (homebus.Home, error) {
 	return v, nil
 }
 
+func setOrder(req middleware.Request, ord orderbus.Order) middleware.Request {
+	ctx := context.WithValue(req.Context(), orderKey, ord)
+	return req.WithContext(ctx)
+}
+
+// GetOrder returns the order from the context.
+func GetOrder(ctx context.Context) (orderbus.Order, error) {
+	v, ok := ctx.Value(orderKey).(orderbus.Order)
+	if !ok {
+		return orderbus.Order{}, errors.New("order not found in context")
+	}
+
+	return v, nil
+}
+
 func setTran(req middleware.Request, tx sqldb.CommitRollbacker) middleware.Request {
 	ctx := context.WithValue(req.Context(), trKey, tx)
 	return req.WithContext(ctx)
@@ -38,9 +38,11 @@ func BeginCommitRollback(log *logger.Logger, bgn sqldb.Beginner, req middleware.
 
 	req = setTran(req, tx)
 
+	// The error of the call is returned as is so the caller still gets its
+	// code. The deferred rollback undoes the changes.
 	resp := next(req)
 	if resp.Err != nil {
-		return errs.NewResponsef(errs.Internal, "EXECUTE TRANSACTION: %s", resp.Err)
+		return resp
 	}
 
 	log.Info(ctx, "COMMIT TRANSACTION")
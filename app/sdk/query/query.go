@@ -2,15 +2,21 @@
 package query
 
 import (
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 )
 
-// Result is the data model used when returning a query result.
+// Result is the data model used when returning a query result. Results for a
+// page addressed by cursor carry no total and provide the cursor for the next
+// page, which is empty once the last page has been returned.
 type Result[T any] struct {
-	Items       []T `json:"items"`
-	Total       int `json:"total"`
-	Page        int `json:"page"`
-	RowsPerPage int `json:"rowsPerPage"`
+	Items       []T    `json:"items"`
+	Total       int    `json:"total"`
+	Page        int    `json:"page"`
+	RowsPerPage int    `json:"rowsPerPage"`
+	NextCursor  string `json:"nextCursor,omitempty"`
 }
 
 // NewResult constructs a result value to return query results.
@@ -22,3 +28,47 @@ func NewResult[T any](items []T, total int, page page.Page) Result[T] {
 		RowsPerPage: page.RowsPerPage(),
 	}
 }
+
+// NewCursorResult constructs a result value to return query results for a
+// page addressed by cursor.
+func NewCursorResult[T any](items []T, page page.Page, nextCursor string) Result[T] {
+	return Result[T]{
+		Items:       items,
+		RowsPerPage: page.RowsPerPage(),
+		NextCursor:  nextCursor,
+	}
+}
+
+// ParsePage parses the paging query strings. A page is addressed by cursor
+// when one is provided, otherwise by number.
+func ParsePage(number string, rows string, cursor string, orderBy order.By) (page.Page, error) {
+	if cursor == "" {
+		return page.Parse(number, rows)
+	}
+
+	if number != "" {
+		return page.Page{}, fmt.Errorf("page and cursor can't be used together")
+	}
+
+	return page.ParseCursor(cursor, rows, orderBy)
+}
+
+// NextCursor returns the cursor for the page following the items. The key
+// function returns the value of the order by field and the id for an item.
+// An empty cursor is returned when there are no more rows to read.
+func NextCursor[T any](items []T, pg page.Page, orderBy order.By, key func(item T, field string) (string, string)) string {
+	if len(items) == 0 || len(items) < pg.RowsPerPage() {
+		return ""
+	}
+
+	last := items[len(items)-1]
+	keys := orderBy.Keys()
+
+	values := make([]string, len(keys))
+	var id string
+	for i, by := range keys {
+		values[i], id = key(last, by.Field)
+	}
+
+	return page.NewCursor(orderBy, values, id).String()
+}
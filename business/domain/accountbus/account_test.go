@@ -0,0 +1,306 @@
+package accountbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Account(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, reset(db), "reset")
+	unitest.Run(t, verify(db), "verify")
+}
+
+// =============================================================================
+
+// newUser creates a user with an email address that wasn't verified.
+func newUser(ctx context.Context, busDomain dbtest.BusDomain) (userbus.User, error) {
+	nu := userbus.TestNewUsers(1, userbus.Roles.User)[0]
+
+	return busDomain.User.Create(ctx, uuid.Nil, nu)
+}
+
+// lastToken returns the token in the last message mailed to the address.
+func lastToken(db *dbtest.Database, to mail.Address) (string, error) {
+	msgs, err := db.Mailer.Messages()
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].To.Address != to.Address {
+			continue
+		}
+
+		fields := strings.Fields(msgs[i].Body)
+		if len(fields) == 0 {
+			return "", errors.New("empty message")
+		}
+
+		return fields[len(fields)-1], nil
+	}
+
+	return "", fmt.Errorf("no message sent to %s", to.Address)
+}
+
+// =============================================================================
+
+func reset(db *dbtest.Database) []unitest.Table {
+	busDomain := db.BusDomain
+
+	table := []unitest.Table{
+		{
+			Name:    "reset",
+			ExpResp: []any{true, accountbus.ErrUsed.Error()},
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Account.RequestPasswordReset(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				token, err := lastToken(db, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				usr, err = busDomain.Account.ResetPassword(ctx, token, "new-password")
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Authenticate(ctx, usr.Email, "new-password"); err != nil {
+					return fmt.Errorf("expected the new password to work, got %v", err)
+				}
+
+				// A token can only be used once.
+				_, err = busDomain.Account.ResetPassword(ctx, token, "another-password")
+				if !errors.Is(err, accountbus.ErrUsed) {
+					return fmt.Errorf("expected the token to be used, got %v", err)
+				}
+
+				return []any{usr.EmailVerified, err.Error()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "superseded",
+			ExpResp: accountbus.ErrUsed.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Account.RequestPasswordReset(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				first, err := lastToken(db, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				// Asking again makes the token sent earlier unusable.
+				if err := busDomain.Account.RequestPasswordReset(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Account.ResetPassword(ctx, first, "new-password")
+				if !errors.Is(err, accountbus.ErrUsed) {
+					return fmt.Errorf("expected the token to be used, got %v", err)
+				}
+
+				return err.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "unknown",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				before, err := db.Mailer.Messages()
+				if err != nil {
+					return err
+				}
+
+				// Nothing tells the caller the email doesn't exist.
+				email := mail.Address{Address: "unknown-" + uuid.NewString() + "@example.com"}
+				if err := busDomain.Account.RequestPasswordReset(ctx, email); err != nil {
+					return err
+				}
+
+				if _, err := lastToken(db, email); err == nil {
+					return errors.New("expected no message to be sent")
+				}
+
+				after, err := db.Mailer.Messages()
+				if err != nil {
+					return err
+				}
+
+				return len(after) - len(before)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "invalid",
+			ExpResp: accountbus.ErrNotFound.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Account.ResetPassword(ctx, "not-a-token", "new-password")
+				if !errors.Is(err, accountbus.ErrNotFound) {
+					return fmt.Errorf("expected the token to not be found, got %v", err)
+				}
+
+				return accountbus.ErrNotFound.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func verify(db *dbtest.Database) []unitest.Table {
+	busDomain := db.BusDomain
+
+	table := []unitest.Table{
+		{
+			Name:    "verify",
+			ExpResp: []any{false, true, accountbus.ErrAlreadyVerified.Error()},
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Account.RequestVerification(ctx, usr); err != nil {
+					return err
+				}
+
+				token, err := lastToken(db, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				vusr, err := busDomain.Account.VerifyEmail(ctx, token)
+				if err != nil {
+					return err
+				}
+
+				err = busDomain.Account.RequestVerification(ctx, vusr)
+				if !errors.Is(err, accountbus.ErrAlreadyVerified) {
+					return fmt.Errorf("expected the email to be verified, got %v", err)
+				}
+
+				return []any{usr.EmailVerified, vusr.EmailVerified, err.Error()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purpose",
+			ExpResp: accountbus.ErrNotFound.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Account.RequestPasswordReset(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				token, err := lastToken(db, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				// A reset token can't verify the email.
+				_, err = busDomain.Account.VerifyEmail(ctx, token)
+				if !errors.Is(err, accountbus.ErrNotFound) {
+					return fmt.Errorf("expected the token to not be found, got %v", err)
+				}
+
+				return err.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "changed",
+			ExpResp: accountbus.ErrEmailChanged.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Account.RequestVerification(ctx, usr); err != nil {
+					return err
+				}
+
+				token, err := lastToken(db, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				uu := userbus.UpdateUser{
+					Email: &mail.Address{Address: "changed-" + uuid.NewString() + "@example.com"},
+				}
+
+				if _, err := busDomain.User.Update(ctx, uuid.Nil, usr, uu); err != nil {
+					return err
+				}
+
+				// The token was sent to the old address.
+				_, err = busDomain.Account.VerifyEmail(ctx, token)
+				if !errors.Is(err, accountbus.ErrEmailChanged) {
+					return fmt.Errorf("expected the email to have changed, got %v", err)
+				}
+
+				return err.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
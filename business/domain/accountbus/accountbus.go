@@ -0,0 +1,277 @@
+// Package accountbus provides business access to the tokens mailed to users
+// so they can reset their password and verify their email address.
+package accountbus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/mailer"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for account token operations.
+var (
+	ErrNotFound        = errors.New("token not found")
+	ErrExpired         = errors.New("token expired")
+	ErrUsed            = errors.New("token already used")
+	ErrEmailChanged    = errors.New("email changed since the token was sent")
+	ErrAlreadyVerified = errors.New("email already verified")
+)
+
+// A reset token can change the password so it is only good for a short time.
+const (
+	resetTTL  = time.Hour
+	verifyTTL = 48 * time.Hour
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, tkn Token) error
+	Use(ctx context.Context, tkn Token) error
+	UseByUserID(ctx context.Context, userID uuid.UUID, purpose Purpose, dateUsed time.Time) error
+	QueryByHash(ctx context.Context, hash []byte) (Token, error)
+}
+
+// Business manages the set of APIs for account token access.
+type Business struct {
+	log     *logger.Logger
+	userBus *userbus.Business
+	mailer  mailer.Mailer
+	storer  Storer
+}
+
+// NewBusiness constructs an account business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, mailer mailer.Mailer, storer Storer) *Business {
+	return &Business{
+		log:     log,
+		userBus: userBus,
+		mailer:  mailer,
+		storer:  storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:     b.log,
+		userBus: userBus,
+		mailer:  b.mailer,
+		storer:  storer,
+	}
+
+	return &bus, nil
+}
+
+// RequestPasswordReset mails a password reset token to the user with the
+// email address. Nothing is sent when there is no enabled user with the
+// address, and no error is returned so the caller can't learn which accounts
+// exist. Tokens sent earlier can no longer be used.
+func (b *Business) RequestPasswordReset(ctx context.Context, email mail.Address) error {
+	usr, err := b.userBus.QueryByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			b.log.Info(ctx, "password reset requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if !usr.Enabled {
+		b.log.Info(ctx, "password reset requested for disabled user", "userID", usr.ID)
+		return nil
+	}
+
+	token, err := b.issue(ctx, usr, Purposes.PasswordReset, resetTTL)
+	if err != nil {
+		return fmt.Errorf("issue: userID[%s]: %w", usr.ID, err)
+	}
+
+	msg := mailer.Message{
+		To:      usr.Email,
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf("Use this token to reset your password. It expires in %s.\n\n%s\n", resetTTL, token),
+	}
+
+	if err := b.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword uses the password reset token to replace the password of the
+// user it was sent to. Since the token was received by mail, the email
+// address of the user is verified as well.
+func (b *Business) ResetPassword(ctx context.Context, token string, password string) (userbus.User, error) {
+	usr, err := b.use(ctx, token, Purposes.PasswordReset)
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	uu := userbus.UpdateUser{
+		Password: &password,
+	}
+
+	updUsr, err := b.userBus.Update(ctx, usr.ID, usr, uu)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("update: userID[%s]: %w", usr.ID, err)
+	}
+
+	updUsr, err = b.userBus.VerifyEmail(ctx, usr.ID, updUsr)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("verifyemail: userID[%s]: %w", usr.ID, err)
+	}
+
+	return updUsr, nil
+}
+
+// RequestVerification mails an email verification token to the user. Tokens
+// sent earlier can no longer be used.
+func (b *Business) RequestVerification(ctx context.Context, usr userbus.User) error {
+	if usr.EmailVerified {
+		return ErrAlreadyVerified
+	}
+
+	token, err := b.issue(ctx, usr, Purposes.VerifyEmail, verifyTTL)
+	if err != nil {
+		return fmt.Errorf("issue: userID[%s]: %w", usr.ID, err)
+	}
+
+	msg := mailer.Message{
+		To:      usr.Email,
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Use this token to verify your email. It expires in %s.\n\n%s\n", verifyTTL, token),
+	}
+
+	if err := b.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail uses the email verification token to mark the email address of
+// the user it was sent to as verified.
+func (b *Business) VerifyEmail(ctx context.Context, token string) (userbus.User, error) {
+	usr, err := b.use(ctx, token, Purposes.VerifyEmail)
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	updUsr, err := b.userBus.VerifyEmail(ctx, usr.ID, usr)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("verifyemail: userID[%s]: %w", usr.ID, err)
+	}
+
+	return updUsr, nil
+}
+
+// =============================================================================
+
+func (b *Business) issue(ctx context.Context, usr userbus.User, purpose Purpose, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+
+	// Only the last token that was sent can be used.
+	if err := b.storer.UseByUserID(ctx, usr.ID, purpose, now); err != nil {
+		return "", fmt.Errorf("usebyuserid: %w", err)
+	}
+
+	tkn := Token{
+		ID:          uuid.New(),
+		UserID:      usr.ID,
+		Purpose:     purpose,
+		Email:       usr.Email,
+		Hash:        hash(token),
+		DateExpires: now.Add(ttl),
+		DateCreated: now,
+	}
+
+	if err := b.storer.Create(ctx, tkn); err != nil {
+		return "", fmt.Errorf("create: %w", err)
+	}
+
+	return token, nil
+}
+
+// use marks the token as used and returns the user it was sent to.
+func (b *Business) use(ctx context.Context, token string, purpose Purpose) (userbus.User, error) {
+	tkn, err := b.storer.QueryByHash(ctx, hash(token))
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("query: %w", err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case tkn.Purpose != purpose:
+		return userbus.User{}, ErrNotFound
+
+	case !tkn.DateUsed.IsZero():
+		return userbus.User{}, ErrUsed
+
+	case now.After(tkn.DateExpires):
+		return userbus.User{}, ErrExpired
+	}
+
+	tkn.DateUsed = now
+
+	// Another request could have used the token since it was read, so the
+	// store only marks a token that is still unused.
+	if err := b.storer.Use(ctx, tkn); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return userbus.User{}, ErrUsed
+		}
+		return userbus.User{}, fmt.Errorf("use: tokenID[%s]: %w", tkn.ID, err)
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, tkn.UserID)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("query: userID[%s]: %w", tkn.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return userbus.User{}, ErrNotFound
+	}
+
+	// The token proves ownership of the address it was sent to, not the
+	// address the user has now.
+	if usr.Email.Address != tkn.Email.Address {
+		return userbus.User{}, ErrEmailChanged
+	}
+
+	return usr, nil
+}
+
+func hash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
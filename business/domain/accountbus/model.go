@@ -0,0 +1,21 @@
+package accountbus
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Token represents a token that was mailed to a user. Only the hash of the
+// token is kept.
+type Token struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Purpose     Purpose
+	Email       mail.Address
+	Hash        []byte
+	DateExpires time.Time
+	DateCreated time.Time
+	DateUsed    time.Time
+}
@@ -0,0 +1,145 @@
+// Package accountdb contains account token related CRUD functionality.
+package accountdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for account token database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (accountbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new account token into the database.
+func (s *Store) Create(ctx context.Context, tkn accountbus.Token) error {
+	const q = `
+	INSERT INTO account_tokens
+		(token_id, user_id, purpose, email, token_hash, date_expires, date_created, date_used)
+	VALUES
+		(:token_id, :user_id, :purpose, :email, :token_hash, :date_expires, :date_created, :date_used)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBToken(tkn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks the account token as used. The token is only marked when it
+// hasn't been used, otherwise accountbus.ErrNotFound is returned.
+func (s *Store) Use(ctx context.Context, tkn accountbus.Token) error {
+	const q = `
+	UPDATE
+		account_tokens
+	SET
+		date_used = :date_used
+	WHERE
+		token_id = :token_id AND
+		date_used IS NULL
+	RETURNING
+		token_id`
+
+	var dbTkn struct {
+		ID uuid.UUID `db:"token_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBToken(tkn), &dbTkn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", accountbus.ErrNotFound)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// UseByUserID marks every unused account token sent to the user for the
+// purpose as used.
+func (s *Store) UseByUserID(ctx context.Context, userID uuid.UUID, purpose accountbus.Purpose, dateUsed time.Time) error {
+	data := struct {
+		UserID   uuid.UUID `db:"user_id"`
+		Purpose  string    `db:"purpose"`
+		DateUsed time.Time `db:"date_used"`
+	}{
+		UserID:   userID,
+		Purpose:  purpose.String(),
+		DateUsed: dateUsed.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		account_tokens
+	SET
+		date_used = :date_used
+	WHERE
+		user_id = :user_id AND
+		purpose = :purpose AND
+		date_used IS NULL`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByHash gets the account token with the specified hash from the
+// database.
+func (s *Store) QueryByHash(ctx context.Context, hash []byte) (accountbus.Token, error) {
+	data := struct {
+		Hash []byte `db:"token_hash"`
+	}{
+		Hash: hash,
+	}
+
+	const q = `
+	SELECT
+		token_id, user_id, purpose, email, token_hash, date_expires, date_created, date_used
+	FROM
+		account_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbTkn token
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTkn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return accountbus.Token{}, fmt.Errorf("db: %w", accountbus.ErrNotFound)
+		}
+		return accountbus.Token{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusToken(dbTkn)
+}
@@ -0,0 +1,63 @@
+package accountdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/google/uuid"
+)
+
+type token struct {
+	ID          uuid.UUID    `db:"token_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Purpose     string       `db:"purpose"`
+	Email       string       `db:"email"`
+	Hash        []byte       `db:"token_hash"`
+	DateExpires time.Time    `db:"date_expires"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUsed    sql.NullTime `db:"date_used"`
+}
+
+func toDBToken(bus accountbus.Token) token {
+	db := token{
+		ID:          bus.ID,
+		UserID:      bus.UserID,
+		Purpose:     bus.Purpose.String(),
+		Email:       bus.Email.Address,
+		Hash:        bus.Hash,
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  bus.DateUsed.UTC(),
+			Valid: !bus.DateUsed.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusToken(db token) (accountbus.Token, error) {
+	purpose, err := accountbus.ParsePurpose(db.Purpose)
+	if err != nil {
+		return accountbus.Token{}, fmt.Errorf("parse purpose: %w", err)
+	}
+
+	bus := accountbus.Token{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		Purpose:     purpose,
+		Email:       mail.Address{Address: db.Email},
+		Hash:        db.Hash,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateUsed.Valid {
+		bus.DateUsed = db.DateUsed.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
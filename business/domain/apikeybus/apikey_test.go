@@ -0,0 +1,242 @@
+package apikeybus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_APIKey(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, authenticate(db.BusDomain, sd), "authenticate")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User: usrs[0],
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu2 := unitest.User{
+		User: usrs[0],
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Users:  []unitest.User{tu1},
+		Admins: []unitest.User{tu2},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "scope",
+			ExpResp: apikeybus.ErrInvalidScope,
+			ExcFunc: func(ctx context.Context) any {
+				nak := apikeybus.NewAPIKey{
+					UserID:      sd.Users[0].ID,
+					Name:        "scope",
+					Scopes:      []userbus.Role{userbus.Roles.Admin},
+					DateExpires: time.Now().Add(time.Hour),
+				}
+
+				_, err := busDomain.APIKey.Create(ctx, nak)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "duplicate",
+			ExpResp: apikeybus.ErrDuplicateName,
+			ExcFunc: func(ctx context.Context) any {
+				nak := apikeybus.NewAPIKey{
+					UserID:      sd.Users[0].ID,
+					Name:        "duplicate",
+					Scopes:      []userbus.Role{userbus.Roles.User},
+					DateExpires: time.Now().Add(time.Hour),
+				}
+
+				if _, err := busDomain.APIKey.Create(ctx, nak); err != nil {
+					return err
+				}
+
+				_, err := busDomain.APIKey.Create(ctx, nak)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func authenticate(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: []any{sd.Admins[0].ID, 2, []string{"ADMIN"}},
+			ExcFunc: func(ctx context.Context) any {
+				nak := apikeybus.NewAPIKey{
+					UserID:      sd.Admins[0].ID,
+					Name:        "basic",
+					Scopes:      []userbus.Role{userbus.Roles.Admin},
+					DateExpires: time.Now().Add(time.Hour),
+				}
+
+				m, err := busDomain.APIKey.Create(ctx, nak)
+				if err != nil {
+					return err
+				}
+
+				if _, _, err := busDomain.APIKey.Authenticate(ctx, m.Key); err != nil {
+					return err
+				}
+
+				ak, usr, err := busDomain.APIKey.Authenticate(ctx, m.Key)
+				if err != nil {
+					return err
+				}
+
+				// The use of the key is recorded.
+				ak, err = busDomain.APIKey.QueryByID(ctx, ak.ID)
+				if err != nil {
+					return err
+				}
+
+				if ak.DateLastUsed.IsZero() {
+					return errors.New("should record when the key was used")
+				}
+
+				return []any{usr.ID, ak.RequestCount, userbus.ParseRolesToString(ak.Scopes)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "expired",
+			ExpResp: apikeybus.ErrExpired,
+			ExcFunc: func(ctx context.Context) any {
+				nak := apikeybus.NewAPIKey{
+					UserID:      sd.Admins[0].ID,
+					Name:        "expired",
+					Scopes:      []userbus.Role{userbus.Roles.Admin},
+					DateExpires: time.Now().Add(-time.Minute),
+				}
+
+				m, err := busDomain.APIKey.Create(ctx, nak)
+				if err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.APIKey.Authenticate(ctx, m.Key)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "unknown",
+			ExpResp: apikeybus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, _, err := busDomain.APIKey.Authenticate(ctx, "unknown")
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: apikeybus.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				nak := apikeybus.NewAPIKey{
+					UserID:      sd.Users[0].ID,
+					Name:        "revoke",
+					Scopes:      []userbus.Role{userbus.Roles.User},
+					DateExpires: time.Now().Add(time.Hour),
+				}
+
+				m, err := busDomain.APIKey.Create(ctx, nak)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.APIKey.Revoke(ctx, m.APIKey); err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.APIKey.Authenticate(ctx, m.Key)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	gotErr, exists := got.(error)
+	if !exists {
+		return fmt.Sprintf("expected an error, got %v", got)
+	}
+
+	if !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("got %v, expected %v", gotErr, exp)
+	}
+
+	return ""
+}
@@ -0,0 +1,217 @@
+// Package apikeybus provides business access to the api keys machine clients
+// use to authenticate.
+package apikeybus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for api key operations.
+var (
+	ErrNotFound      = errors.New("api key not found")
+	ErrExpired       = errors.New("api key expired")
+	ErrRevoked       = errors.New("api key revoked")
+	ErrDuplicateName = errors.New("api key name already exists")
+	ErrInvalidScope  = errors.New("scope is not a role of the user")
+	ErrUserDisabled  = errors.New("user disabled")
+)
+
+// keyPrefix marks the keys minted by this package so they can be recognized
+// when they leak.
+const keyPrefix = "ek_"
+
+// prefixLen is the number of characters of the key that are kept so the
+// user can tell their keys apart.
+const prefixLen = len(keyPrefix) + 8
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, key APIKey) error
+	Revoke(ctx context.Context, key APIKey) error
+	RecordUse(ctx context.Context, key APIKey) error
+	QueryByID(ctx context.Context, keyID uuid.UUID) (APIKey, error)
+	QueryByHash(ctx context.Context, hash []byte) (APIKey, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+}
+
+// Business manages the set of APIs for api key access.
+type Business struct {
+	log     *logger.Logger
+	userBus *userbus.Business
+	storer  Storer
+}
+
+// NewBusiness constructs an api key business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, storer Storer) *Business {
+	return &Business{
+		log:     log,
+		userBus: userBus,
+		storer:  storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:     b.log,
+		userBus: userBus,
+		storer:  storer,
+	}
+
+	return &bus, nil
+}
+
+// Create mints a new api key for the user. The scopes have to be roles the
+// user has so a key can't do more than its user.
+func (b *Business) Create(ctx context.Context, nak NewAPIKey) (Minted, error) {
+	usr, err := b.userBus.QueryByID(ctx, nak.UserID)
+	if err != nil {
+		return Minted{}, fmt.Errorf("user: %w", err)
+	}
+
+	for _, scope := range nak.Scopes {
+		if !slices.ContainsFunc(usr.Roles, scope.Equal) {
+			return Minted{}, fmt.Errorf("scope[%s]: %w", scope, ErrInvalidScope)
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return Minted{}, fmt.Errorf("generating key: %w", err)
+	}
+
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+
+	ak := APIKey{
+		ID:          uuid.New(),
+		UserID:      nak.UserID,
+		Name:        nak.Name,
+		Prefix:      key[:prefixLen],
+		Hash:        hash(key),
+		Scopes:      nak.Scopes,
+		DateExpires: nak.DateExpires,
+		DateCreated: now,
+	}
+
+	if err := b.storer.Create(ctx, ak); err != nil {
+		return Minted{}, fmt.Errorf("create: %w", err)
+	}
+
+	m := Minted{
+		APIKey: ak,
+		Key:    key,
+	}
+
+	return m, nil
+}
+
+// Revoke revokes the api key so it can't be used anymore.
+func (b *Business) Revoke(ctx context.Context, ak APIKey) error {
+	if !ak.DateRevoked.IsZero() {
+		return nil
+	}
+
+	ak.DateRevoked = time.Now()
+
+	if err := b.storer.Revoke(ctx, ak); err != nil {
+		return fmt.Errorf("revoke: keyID[%s]: %w", ak.ID, err)
+	}
+
+	return nil
+}
+
+// Authenticate finds the api key for the key presented by a client and
+// records its use. The user the key acts for is returned along with the key.
+// The scopes of the key that are returned are narrowed to the roles the user
+// still has.
+func (b *Business) Authenticate(ctx context.Context, key string) (APIKey, userbus.User, error) {
+	ak, err := b.storer.QueryByHash(ctx, hash(key))
+	if err != nil {
+		return APIKey{}, userbus.User{}, fmt.Errorf("query: %w", err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case !ak.DateRevoked.IsZero():
+		return APIKey{}, userbus.User{}, ErrRevoked
+
+	case now.After(ak.DateExpires):
+		return APIKey{}, userbus.User{}, ErrExpired
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, ak.UserID)
+	if err != nil {
+		return APIKey{}, userbus.User{}, fmt.Errorf("user: %w", err)
+	}
+
+	if !usr.Enabled {
+		return APIKey{}, userbus.User{}, ErrUserDisabled
+	}
+
+	ak.Scopes = slices.DeleteFunc(ak.Scopes, func(scope userbus.Role) bool {
+		return !slices.ContainsFunc(usr.Roles, scope.Equal)
+	})
+
+	ak.DateLastUsed = now
+	ak.RequestCount++
+
+	if err := b.storer.RecordUse(ctx, ak); err != nil {
+		return APIKey{}, userbus.User{}, fmt.Errorf("recorduse: keyID[%s]: %w", ak.ID, err)
+	}
+
+	return ak, usr, nil
+}
+
+// QueryByID finds the api key by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, keyID uuid.UUID) (APIKey, error) {
+	ak, err := b.storer.QueryByID(ctx, keyID)
+	if err != nil {
+		return APIKey{}, fmt.Errorf("query: keyID[%s]: %w", keyID, err)
+	}
+
+	return ak, nil
+}
+
+// QueryByUserID finds the api keys minted by the user.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	aks, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return aks, nil
+}
+
+// =============================================================================
+
+func hash(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
@@ -0,0 +1,40 @@
+package apikeybus
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/google/uuid"
+)
+
+// APIKey represents a key a machine client uses to call the service as the
+// user that minted it. Only the hash of the key is kept. The prefix is the
+// start of the key so the user can tell their keys apart.
+type APIKey struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Name         string
+	Prefix       string
+	Hash         []byte
+	Scopes       []userbus.Role
+	RequestCount int
+	DateExpires  time.Time
+	DateCreated  time.Time
+	DateLastUsed time.Time
+	DateRevoked  time.Time
+}
+
+// NewAPIKey is what we require from clients when minting an APIKey.
+type NewAPIKey struct {
+	UserID      uuid.UUID
+	Name        string
+	Scopes      []userbus.Role
+	DateExpires time.Time
+}
+
+// Minted represents an api key that was minted along with the key that has
+// to be handed to the client. The key can't be recovered later.
+type Minted struct {
+	APIKey
+	Key string
+}
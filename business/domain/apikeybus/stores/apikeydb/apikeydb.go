@@ -0,0 +1,180 @@
+// Package apikeydb contains api key related CRUD functionality.
+package apikeydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for api key database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (apikeybus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new api key into the database.
+func (s *Store) Create(ctx context.Context, ak apikeybus.APIKey) error {
+	const q = `
+	INSERT INTO api_keys
+		(api_key_id, user_id, name, prefix, key_hash, scopes, request_count, date_expires, date_created, date_last_used, date_revoked)
+	VALUES
+		(:api_key_id, :user_id, :name, :prefix, :key_hash, :scopes, :request_count, :date_expires, :date_created, :date_last_used, :date_revoked)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAPIKey(ak)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", apikeybus.ErrDuplicateName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke marks the api key as revoked.
+func (s *Store) Revoke(ctx context.Context, ak apikeybus.APIKey) error {
+	const q = `
+	UPDATE
+		api_keys
+	SET
+		date_revoked = :date_revoked
+	WHERE
+		api_key_id = :api_key_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAPIKey(ak)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// RecordUse records when the api key was last used and counts the request.
+// The count is incremented in the database so concurrent requests are all
+// counted.
+func (s *Store) RecordUse(ctx context.Context, ak apikeybus.APIKey) error {
+	const q = `
+	UPDATE
+		api_keys
+	SET
+		date_last_used = :date_last_used,
+		request_count = request_count + 1
+	WHERE
+		api_key_id = :api_key_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAPIKey(ak)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified api key from the database.
+func (s *Store) QueryByID(ctx context.Context, keyID uuid.UUID) (apikeybus.APIKey, error) {
+	data := struct {
+		ID uuid.UUID `db:"api_key_id"`
+	}{
+		ID: keyID,
+	}
+
+	const q = `
+	SELECT
+		api_key_id, user_id, name, prefix, key_hash, scopes, request_count, date_expires, date_created, date_last_used, date_revoked
+	FROM
+		api_keys
+	WHERE
+		api_key_id = :api_key_id`
+
+	var dbAK apiKey
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbAK); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return apikeybus.APIKey{}, fmt.Errorf("db: %w", apikeybus.ErrNotFound)
+		}
+		return apikeybus.APIKey{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusAPIKey(dbAK)
+}
+
+// QueryByHash gets the api key with the specified hash from the database.
+func (s *Store) QueryByHash(ctx context.Context, hash []byte) (apikeybus.APIKey, error) {
+	data := struct {
+		Hash []byte `db:"key_hash"`
+	}{
+		Hash: hash,
+	}
+
+	const q = `
+	SELECT
+		api_key_id, user_id, name, prefix, key_hash, scopes, request_count, date_expires, date_created, date_last_used, date_revoked
+	FROM
+		api_keys
+	WHERE
+		key_hash = :key_hash`
+
+	var dbAK apiKey
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbAK); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return apikeybus.APIKey{}, fmt.Errorf("db: %w", apikeybus.ErrNotFound)
+		}
+		return apikeybus.APIKey{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusAPIKey(dbAK)
+}
+
+// QueryByUserID gets the api keys minted by the user from the database,
+// newest first.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]apikeybus.APIKey, error) {
+	data := struct {
+		UserID uuid.UUID `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	SELECT
+		api_key_id, user_id, name, prefix, key_hash, scopes, request_count, date_expires, date_created, date_last_used, date_revoked
+	FROM
+		api_keys
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created DESC`
+
+	var dbAKs []apiKey
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbAKs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAPIKeys(dbAKs)
+}
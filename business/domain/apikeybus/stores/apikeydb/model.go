@@ -0,0 +1,93 @@
+package apikeydb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
+	"github.com/google/uuid"
+)
+
+type apiKey struct {
+	ID           uuid.UUID      `db:"api_key_id"`
+	UserID       uuid.UUID      `db:"user_id"`
+	Name         string         `db:"name"`
+	Prefix       string         `db:"prefix"`
+	Hash         []byte         `db:"key_hash"`
+	Scopes       dbarray.String `db:"scopes"`
+	RequestCount int            `db:"request_count"`
+	DateExpires  time.Time      `db:"date_expires"`
+	DateCreated  time.Time      `db:"date_created"`
+	DateLastUsed sql.NullTime   `db:"date_last_used"`
+	DateRevoked  sql.NullTime   `db:"date_revoked"`
+}
+
+func toDBAPIKey(bus apikeybus.APIKey) apiKey {
+	db := apiKey{
+		ID:           bus.ID,
+		UserID:       bus.UserID,
+		Name:         bus.Name,
+		Prefix:       bus.Prefix,
+		Hash:         bus.Hash,
+		Scopes:       userbus.ParseRolesToString(bus.Scopes),
+		RequestCount: bus.RequestCount,
+		DateExpires:  bus.DateExpires.UTC(),
+		DateCreated:  bus.DateCreated.UTC(),
+		DateLastUsed: sql.NullTime{
+			Time:  bus.DateLastUsed.UTC(),
+			Valid: !bus.DateLastUsed.IsZero(),
+		},
+		DateRevoked: sql.NullTime{
+			Time:  bus.DateRevoked.UTC(),
+			Valid: !bus.DateRevoked.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusAPIKey(db apiKey) (apikeybus.APIKey, error) {
+	scopes, err := userbus.ParseRoles(db.Scopes)
+	if err != nil {
+		return apikeybus.APIKey{}, fmt.Errorf("parse scopes: %w", err)
+	}
+
+	bus := apikeybus.APIKey{
+		ID:           db.ID,
+		UserID:       db.UserID,
+		Name:         db.Name,
+		Prefix:       db.Prefix,
+		Hash:         db.Hash,
+		Scopes:       scopes,
+		RequestCount: db.RequestCount,
+		DateExpires:  db.DateExpires.In(time.Local),
+		DateCreated:  db.DateCreated.In(time.Local),
+	}
+
+	if db.DateLastUsed.Valid {
+		bus.DateLastUsed = db.DateLastUsed.Time.In(time.Local)
+	}
+
+	if db.DateRevoked.Valid {
+		bus.DateRevoked = db.DateRevoked.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusAPIKeys(dbs []apiKey) ([]apikeybus.APIKey, error) {
+	bus := make([]apikeybus.APIKey, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusAPIKey(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
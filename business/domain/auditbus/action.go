@@ -0,0 +1,69 @@
+package auditbus
+
+import "fmt"
+
+type actionSet struct {
+	Create     Action
+	Update     Action
+	UpdateRole Action
+	Delete     Action
+	Restore    Action
+}
+
+// Actions represents the set of actions that can be audited.
+var Actions = actionSet{
+	Create:     newAction("CREATE"),
+	Update:     newAction("UPDATE"),
+	UpdateRole: newAction("UPDATE ROLE"),
+	Delete:     newAction("DELETE"),
+	Restore:    newAction("RESTORE"),
+}
+
+// =============================================================================
+
+// Set of known actions.
+var actions = make(map[string]Action)
+
+// Action represents the kind of change that was made to an entity.
+type Action struct {
+	name string
+}
+
+func newAction(action string) Action {
+	a := Action{action}
+	actions[action] = a
+	return a
+}
+
+// String returns the name of the action.
+func (a Action) String() string {
+	return a.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (a Action) Equal(a2 Action) bool {
+	return a.name == a2.name
+}
+
+// =============================================================================
+
+// ParseAction parses the string value and returns an action if one exists.
+func ParseAction(value string) (Action, error) {
+	action, exists := actions[value]
+	if !exists {
+		return Action{}, fmt.Errorf("invalid action %q", value)
+	}
+
+	return action, nil
+}
+
+// MustParseAction parses the string value and returns an action if one
+// exists. If an error occurs the function panics.
+func MustParseAction(value string) Action {
+	action, err := ParseAction(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return action
+}
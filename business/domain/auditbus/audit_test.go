@@ -0,0 +1,216 @@
+package auditbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, transaction(db, sd), "transaction")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User: usrs[0],
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu2 := unitest.User{
+		User: usrs[0],
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{tu1},
+		Users:  []unitest.User{tu2},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0].User
+	actorID := sd.Admins[0].ID
+
+	table := []unitest.Table{
+		{
+			Name:    "create",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := auditbus.QueryFilter{
+					Entity:   &auditbus.Entities.User,
+					EntityID: &usr.ID,
+					Action:   &auditbus.Actions.Create,
+				}
+
+				resp, err := busDomain.Audit.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "update",
+			ExpResp: map[string]any{
+				"name": map[string]any{
+					"before": usr.Name.String(),
+					"after":  "Jack Kennedy",
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Name: dbtest.UserNamePointer("Jack Kennedy"),
+				}
+
+				if _, err := busDomain.User.Update(ctx, actorID, usr, uu); err != nil {
+					return err
+				}
+
+				filter := auditbus.QueryFilter{
+					ActorID:  &actorID,
+					EntityID: &usr.ID,
+				}
+
+				resp, err := busDomain.Audit.Query(ctx, filter, auditbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(resp) != 1 {
+					return fmt.Errorf("expected 1 audit record, got %d", len(resp))
+				}
+
+				if !resp[0].Action.Equal(auditbus.Actions.Update) {
+					return fmt.Errorf("expected action %s, got %s", auditbus.Actions.Update, resp[0].Action)
+				}
+
+				var diff map[string]any
+				if err := json.Unmarshal(resp[0].Diff, &diff); err != nil {
+					return err
+				}
+
+				return diff
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func transaction(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	actorID := sd.Admins[0].ID
+
+	createUser := func(ctx context.Context, commit bool) any {
+		tx, err := sqldb.NewBeginner(db.DB).Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		userBus, err := db.BusDomain.User.NewWithTx(tx)
+		if err != nil {
+			return err
+		}
+
+		usr, err := userBus.Create(ctx, actorID, userbus.TestNewUsers(1, userbus.Roles.User)[0])
+		if err != nil {
+			return err
+		}
+
+		if commit {
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+		}
+
+		filter := auditbus.QueryFilter{
+			EntityID: &usr.ID,
+		}
+
+		resp, err := db.BusDomain.Audit.Count(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		return resp
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "rollback",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				return createUser(ctx, false)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "commit",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				return createUser(ctx, true)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
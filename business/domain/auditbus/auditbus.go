@@ -0,0 +1,92 @@
+// Package auditbus provides business access to audit domain.
+package auditbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, adt Audit) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
+
+// Business manages the set of APIs for audit access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an audit business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create records a change to an entity in the audit log.
+func (b *Business) Create(ctx context.Context, na NewAudit) (Audit, error) {
+	dif, err := diff(na.Before, na.After)
+	if err != nil {
+		return Audit{}, fmt.Errorf("diff: %w", err)
+	}
+
+	adt := Audit{
+		ID:          uuid.New(),
+		ActorID:     na.ActorID,
+		Entity:      na.Entity,
+		EntityID:    na.EntityID,
+		Action:      na.Action,
+		Diff:        dif,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, adt); err != nil {
+		return Audit{}, fmt.Errorf("create: %w", err)
+	}
+
+	return adt, nil
+}
+
+// Query retrieves a list of existing audit records.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error) {
+	adts, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return adts, nil
+}
+
+// Count returns the total number of audit records.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
@@ -0,0 +1,74 @@
+package auditbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// change records the value of a single field on either side of a change. A
+// missing side means the field didn't exist there.
+type change struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// diff compares the JSON representation of the before and after values and
+// returns a JSON object keyed by the fields that changed.
+func diff(before any, after any) (json.RawMessage, error) {
+	bm, err := toFields(before)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+
+	am, err := toFields(after)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+
+	changes := make(map[string]change)
+
+	for field, bv := range bm {
+		av, exists := am[field]
+		if !exists {
+			changes[field] = change{Before: bv}
+			continue
+		}
+
+		if !reflect.DeepEqual(bv, av) {
+			changes[field] = change{Before: bv, After: av}
+		}
+	}
+
+	for field, av := range am {
+		if _, exists := bm[field]; !exists {
+			changes[field] = change{After: av}
+		}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	return data, nil
+}
+
+// toFields converts the value into a map of its JSON fields.
+func toFields(v any) (map[string]any, error) {
+	if v == nil {
+		return map[string]any{}, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return m, nil
+}
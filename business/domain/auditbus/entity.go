@@ -0,0 +1,65 @@
+package auditbus
+
+import "fmt"
+
+type entitySet struct {
+	User    Entity
+	Product Entity
+	Home    Entity
+}
+
+// Entities represents the set of entities that can be audited.
+var Entities = entitySet{
+	User:    newEntity("USER"),
+	Product: newEntity("PRODUCT"),
+	Home:    newEntity("HOME"),
+}
+
+// =============================================================================
+
+// Set of known entities.
+var entities = make(map[string]Entity)
+
+// Entity represents the type of entity that was changed.
+type Entity struct {
+	name string
+}
+
+func newEntity(entity string) Entity {
+	e := Entity{entity}
+	entities[entity] = e
+	return e
+}
+
+// String returns the name of the entity.
+func (e Entity) String() string {
+	return e.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (e Entity) Equal(e2 Entity) bool {
+	return e.name == e2.name
+}
+
+// =============================================================================
+
+// ParseEntity parses the string value and returns an entity if one exists.
+func ParseEntity(value string) (Entity, error) {
+	entity, exists := entities[value]
+	if !exists {
+		return Entity{}, fmt.Errorf("invalid entity %q", value)
+	}
+
+	return entity, nil
+}
+
+// MustParseEntity parses the string value and returns an entity if one exists.
+// If an error occurs the function panics.
+func MustParseEntity(value string) Entity {
+	entity, err := ParseEntity(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return entity
+}
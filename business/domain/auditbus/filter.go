@@ -0,0 +1,19 @@
+package auditbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	ActorID          *uuid.UUID
+	Entity           *Entity
+	EntityID         *uuid.UUID
+	Action           *Action
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
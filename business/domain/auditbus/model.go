@@ -0,0 +1,32 @@
+package auditbus
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit represents a single change that was made to an entity.
+type Audit struct {
+	ID          uuid.UUID
+	ActorID     uuid.UUID
+	Entity      Entity
+	EntityID    uuid.UUID
+	Action      Action
+	Diff        json.RawMessage
+	DateCreated time.Time
+}
+
+// NewAudit is what we require to record a change. Before and After hold the
+// state of the entity on either side of the change and must marshal to a
+// JSON object. Before is nil when the entity is created and After is nil when
+// it is deleted.
+type NewAudit struct {
+	ActorID  uuid.UUID
+	Entity   Entity
+	EntityID uuid.UUID
+	Action   Action
+	Before   any
+	After    any
+}
@@ -0,0 +1,16 @@
+package auditbus
+
+import "github.com/ardanlabs/encore/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "audit_id"
+	OrderByActorID     = "actor_id"
+	OrderByEntity      = "entity"
+	OrderByEntityID    = "entity_id"
+	OrderByAction      = "action"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,50 @@
+package auditdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+)
+
+func (s *Store) applyFilter(filter auditbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
+	if filter.ID != nil {
+		data["audit_id"] = *filter.ID
+		wc = append(wc, "audit_id = :audit_id")
+	}
+
+	if filter.ActorID != nil {
+		data["actor_id"] = *filter.ActorID
+		wc = append(wc, "actor_id = :actor_id")
+	}
+
+	if filter.Entity != nil {
+		data["entity"] = filter.Entity.String()
+		wc = append(wc, "entity = :entity")
+	}
+
+	if filter.EntityID != nil {
+		data["entity_id"] = *filter.EntityID
+		wc = append(wc, "entity_id = :entity_id")
+	}
+
+	if filter.Action != nil {
+		data["action"] = filter.Action.String()
+		wc = append(wc, "action = :action")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,72 @@
+package auditdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+type audit struct {
+	ID          uuid.UUID `db:"audit_id"`
+	ActorID     uuid.UUID `db:"actor_id"`
+	Entity      string    `db:"entity"`
+	EntityID    uuid.UUID `db:"entity_id"`
+	Action      string    `db:"action"`
+	Diff        string    `db:"diff"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBAudit(bus auditbus.Audit) audit {
+	db := audit{
+		ID:          bus.ID,
+		ActorID:     bus.ActorID,
+		Entity:      bus.Entity.String(),
+		EntityID:    bus.EntityID,
+		Action:      bus.Action.String(),
+		Diff:        string(bus.Diff),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusAudit(db audit) (auditbus.Audit, error) {
+	entity, err := auditbus.ParseEntity(db.Entity)
+	if err != nil {
+		return auditbus.Audit{}, fmt.Errorf("parse entity: %w", err)
+	}
+
+	action, err := auditbus.ParseAction(db.Action)
+	if err != nil {
+		return auditbus.Audit{}, fmt.Errorf("parse action: %w", err)
+	}
+
+	bus := auditbus.Audit{
+		ID:          db.ID,
+		ActorID:     db.ActorID,
+		Entity:      entity,
+		EntityID:    db.EntityID,
+		Action:      action,
+		Diff:        json.RawMessage(db.Diff),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusAudits(dbs []audit) ([]auditbus.Audit, error) {
+	bus := make([]auditbus.Audit, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusAudit(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
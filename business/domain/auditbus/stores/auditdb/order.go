@@ -0,0 +1,28 @@
+package auditdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+var orderByFields = map[string]string{
+	auditbus.OrderByID:          "audit_id",
+	auditbus.OrderByActorID:     "actor_id",
+	auditbus.OrderByEntity:      "entity",
+	auditbus.OrderByEntityID:    "entity_id",
+	auditbus.OrderByAction:      "action",
+	auditbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return sqldb.OrderByClause(orderBy, orderByFields, "audit_id")
+}
+
+// cursorClause returns the condition that resumes the query after the row the
+// page cursor points at. No condition is returned when the page isn't
+// addressed by a cursor.
+func cursorClause(orderBy order.By, page page.Page, data map[string]any) ([]string, error) {
+	return sqldb.CursorClause(orderBy, page, orderByFields, "audit_id", data)
+}
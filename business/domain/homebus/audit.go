@@ -0,0 +1,68 @@
+package homebus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+// auditHome is the view of a home that is recorded in the audit log.
+type auditHome struct {
+	UserID      string     `json:"userID"`
+	Type        string     `json:"type"`
+	Address1    string     `json:"address1"`
+	Address2    string     `json:"address2"`
+	ZipCode     string     `json:"zipCode"`
+	City        string     `json:"city"`
+	State       string     `json:"state"`
+	Country     string     `json:"country"`
+	Status      string     `json:"status"`
+	DateDeleted *time.Time `json:"dateDeleted,omitempty"`
+}
+
+func toAuditHome(hme Home) auditHome {
+	var dateDeleted *time.Time
+	if !hme.DateDeleted.IsZero() {
+		dateDeleted = &hme.DateDeleted
+	}
+
+	return auditHome{
+		UserID:      hme.UserID.String(),
+		Type:        hme.Type.String(),
+		Address1:    hme.Address.Address1,
+		Address2:    hme.Address.Address2,
+		ZipCode:     hme.Address.ZipCode,
+		City:        hme.Address.City,
+		State:       hme.Address.State,
+		Country:     hme.Address.Country,
+		Status:      hme.Status.String(),
+		DateDeleted: dateDeleted,
+	}
+}
+
+// audit records the change made to the home in the audit log. If the
+// business was constructed for query only, there won't be an audit api
+// provided.
+func (b *Business) audit(ctx context.Context, actorID uuid.UUID, action auditbus.Action, homeID uuid.UUID, before any, after any) error {
+	if b.auditBus == nil {
+		return nil
+	}
+
+	na := auditbus.NewAudit{
+		ActorID:  actorID,
+		Entity:   auditbus.Entities.Home,
+		EntityID: homeID,
+		Action:   action,
+		Before:   before,
+		After:    after,
+	}
+
+	if _, err := b.auditBus.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: action[%s]: %w", action, err)
+	}
+
+	return nil
+}
@@ -1 +1,157 @@
 package homebus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
+	"github.com/google/uuid"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system. If the business was constructed for query only, there won't be a
+// delegate provided.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		delegate.Register(b.delegate, "homebus.user-updated", b.actionUserUpdated)
+		delegate.Register(b.delegate, "homebus.user-deleted", b.actionUserDeleted)
+		delegate.Register(b.delegate, "homebus.user-restored", b.actionUserRestored)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is updated.
+func (b *Business) actionUserUpdated(ctx context.Context, claim delegate.Claim, params userbus.ActionUpdatedParms) error {
+	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", params.Enabled)
+
+	// Only a change to the enabled flag affects the homes. When a user is
+	// disabled their homes are deactivated and when they are enabled again
+	// the homes are restored.
+	if params.Enabled == nil {
+		return nil
+	}
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	status := Statuses.Active
+	if !*params.Enabled {
+		status = Statuses.Inactive
+	}
+
+	homes, err := bus.storer.UpdateStatusByUserID(ctx, params.UserID, status, time.Now())
+	if err != nil {
+		return fmt.Errorf("updatestatusbyuserid: userID[%s] status[%s]: %w", params.UserID, status, err)
+	}
+
+	for _, hme := range homes {
+		if err := bus.publishEvent(ctx, events.TypeUpdated, hme); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// actionUserDeleted is executed by the user domain indirectly when a user is
+// deleted. The user's homes are deleted with the same timestamp so they can
+// be told apart from homes that were deleted on their own.
+func (b *Business) actionUserDeleted(ctx context.Context, claim delegate.Claim, params userbus.ActionDeletedParms) error {
+	b.log.Info(ctx, "action-userdeleted", "user_id", params.UserID)
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	homes, err := bus.storer.DeleteByUserID(ctx, params.UserID, params.DateDeleted)
+	if err != nil {
+		return fmt.Errorf("deletebyuserid: userID[%s]: %w", params.UserID, err)
+	}
+
+	for _, hme := range homes {
+		if err := bus.publishEvent(ctx, events.TypeDeleted, hme); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// actionUserRestored is executed by the user domain indirectly when a user is
+// restored. Only the homes that were deleted along with the user are restored.
+func (b *Business) actionUserRestored(ctx context.Context, claim delegate.Claim, params userbus.ActionRestoredParms) error {
+	b.log.Info(ctx, "action-userrestored", "user_id", params.UserID)
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	homes, err := bus.storer.RestoreByUserID(ctx, params.UserID, params.DateDeleted, time.Now())
+	if err != nil {
+		return fmt.Errorf("restorebyuserid: userID[%s]: %w", params.UserID, err)
+	}
+
+	for _, hme := range homes {
+		if err := bus.publishEvent(ctx, events.TypeRestored, hme); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// publishEvent writes the public event for the change to the home. When the
+// business runs in a transaction, the event is only sent once it commits.
+func (b *Business) publishEvent(ctx context.Context, typ string, hme Home) error {
+	if b.delegate == nil {
+		return nil
+	}
+
+	if err := delegate.Publish(ctx, b.delegate, toHomeEvent(typ, hme)); err != nil {
+		return fmt.Errorf("publish: %s event: homeID[%s]: %w", typ, hme.ID, err)
+	}
+
+	return nil
+}
+
+func toHomeEvent(typ string, hme Home) events.HomeEvent {
+	evt := events.HomeEvent{
+		Version:    events.Version,
+		ID:         uuid.NewString(),
+		Type:       typ,
+		HomeID:     hme.ID.String(),
+		OccurredAt: time.Now().UTC(),
+		Home: events.Home{
+			ID:       hme.ID.String(),
+			TenantID: hme.TenantID.String(),
+			UserID:   hme.UserID.String(),
+			Type:     hme.Type.String(),
+			Address: events.HomeAddress{
+				Address1: hme.Address.Address1,
+				Address2: hme.Address.Address2,
+				ZipCode:  hme.Address.ZipCode,
+				City:     hme.Address.City,
+				State:    hme.Address.State,
+				Country:  hme.Address.Country,
+			},
+			Status:      hme.Status.String(),
+			DateCreated: hme.DateCreated.UTC(),
+			DateUpdated: hme.DateUpdated.UTC(),
+		},
+	}
+
+	if !hme.DateDeleted.IsZero() {
+		dateDeleted := hme.DateDeleted.UTC()
+		evt.Home.DateDeleted = &dateDeleted
+	}
+
+	return evt
+}
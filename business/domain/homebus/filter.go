@@ -8,10 +8,24 @@ import (
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// When no Status is provided only active homes are returned. Deleted homes
+// are excluded unless Deleted is set to true, in which case only the deleted
+// homes are returned. City and State match homes whose value contains the
+// filter value, ZipCode homes whose zip code starts with it and Country homes
+// in that country, all ignoring case.
 type QueryFilter struct {
 	ID               *uuid.UUID
+	IDs              []uuid.UUID
 	UserID           *uuid.UUID
+	UserIDs          []uuid.UUID
 	Type             *Type
+	Types            []Type
+	City             *string
+	State            *string
+	ZipCode          *string
+	Country          *string
+	Status           *Status
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
+	Deleted          *bool
 }
@@ -2,6 +2,7 @@ package homebus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -37,12 +39,14 @@ func Test_Home(t *testing.T) {
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+	unitest.Run(t, cascade(db.BusDomain, sd), "cascade")
+	unitest.Run(t, restore(db.BusDomain, sd), "restore")
 }
 
 // =============================================================================
 
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := sqldb.WithSystem(context.Background())
 
 	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
 	if err != nil {
@@ -192,8 +196,9 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: homebus.Home{
-				UserID: sd.Users[0].ID,
-				Type:   homebus.Types.Single,
+				TenantID: sd.Users[0].TenantID,
+				UserID:   sd.Users[0].ID,
+				Type:     homebus.Types.Single,
 				Address: homebus.Address{
 					Address1: "123 Mocking Bird Lane",
 					ZipCode:  "35810",
@@ -201,6 +206,8 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Status:  homebus.Statuses.Active,
+				Version: 1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nh := homebus.NewHome{
@@ -215,7 +222,7 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					},
 				}
 
-				resp, err := busDomain.Home.Create(ctx, nh)
+				resp, err := busDomain.Home.Create(ctx, sd.Users[0].ID, nh)
 				if err != nil {
 					return err
 				}
@@ -247,9 +254,10 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: homebus.Home{
-				ID:     sd.Users[0].Homes[0].ID,
-				UserID: sd.Users[0].ID,
-				Type:   homebus.Types.Single,
+				ID:       sd.Users[0].Homes[0].ID,
+				TenantID: sd.Users[0].TenantID,
+				UserID:   sd.Users[0].ID,
+				Type:     homebus.Types.Single,
 				Address: homebus.Address{
 					Address1: "123 Mocking Bird Lane",
 					Address2: "apt 105",
@@ -258,8 +266,10 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					State:    "AL",
 					Country:  "US",
 				},
+				Status:      homebus.Statuses.Active,
 				DateCreated: sd.Users[0].Homes[0].DateCreated,
 				DateUpdated: sd.Users[0].Homes[0].DateCreated,
+				Version:     2,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				uh := homebus.UpdateHome{
@@ -274,7 +284,7 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					},
 				}
 
-				resp, err := busDomain.Home.Update(ctx, sd.Users[0].Homes[0], uh)
+				resp, err := busDomain.Home.Update(ctx, sd.Users[0].ID, sd.Users[0].Homes[0], uh)
 				if err != nil {
 					return err
 				}
@@ -296,6 +306,26 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "stale",
+			ExpResp: homebus.ErrVersionConflict,
+			ExcFunc: func(ctx context.Context) any {
+				uh := homebus.UpdateHome{
+					Type: &homebus.Types.Condo,
+				}
+
+				_, err := busDomain.Home.Update(ctx, sd.Users[0].ID, sd.Users[0].Homes[0], uh)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -307,7 +337,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "user",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.Home.Delete(ctx, sd.Users[0].Homes[1]); err != nil {
+				if err := busDomain.Home.Delete(ctx, sd.Users[0].ID, sd.Users[0].Homes[1]); err != nil {
 					return err
 				}
 
@@ -321,7 +351,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "admin",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.Home.Delete(ctx, sd.Admins[0].Homes[1]); err != nil {
+				if err := busDomain.Home.Delete(ctx, sd.Admins[0].ID, sd.Admins[0].Homes[1]); err != nil {
 					return err
 				}
 
@@ -335,3 +365,272 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func cascade(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	hme := sd.Users[0].Homes[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "disable",
+			ExpResp: homebus.Statuses.Inactive,
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(false),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Home.QueryByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp.Status
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "hidden",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				filter := homebus.QueryFilter{
+					ID: &hme.ID,
+				}
+
+				resp, err := busDomain.Home.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "enable",
+			ExpResp: homebus.Statuses.Active,
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(true),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Home.QueryByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp.Status
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "restored",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := homebus.QueryFilter{
+					ID: &hme.ID,
+				}
+
+				resp, err := busDomain.Home.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restore(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	hme := sd.Users[0].Homes[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "hidden",
+			ExpResp: homebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Home.QueryByID(ctx, hme.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "deleted",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := homebus.QueryFilter{
+					ID:      &hme.ID,
+					Deleted: dbtest.BoolPointer(true),
+				}
+
+				resp, err := busDomain.Home.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "basic",
+			ExpResp: hme,
+			ExcFunc: func(ctx context.Context) any {
+				dltHme, err := busDomain.Home.QueryDeletedByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Home.Restore(ctx, sd.Users[0].ID, dltHme); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Home.QueryByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(homebus.Home)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(homebus.Home)
+
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Version = gotResp.Version
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "user-deleted",
+			ExpResp: homebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Users[0].User); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Home.QueryByID(ctx, hme.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "owner-deleted",
+			ExpResp: userbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				dltHme, err := busDomain.Home.QueryDeletedByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Home.Restore(ctx, sd.Users[0].ID, dltHme)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "user-restored",
+			ExpResp: 3,
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := busDomain.User.QueryDeletedByID(ctx, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Restore(ctx, sd.Admins[0].ID, usr); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Home.QueryByUserID(ctx, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Home.Purge(ctx, 0)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purged",
+			ExpResp: homebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Home.QueryDeletedByID(ctx, sd.Admins[0].Homes[1].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
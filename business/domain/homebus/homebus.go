@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/auditbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -18,8 +20,9 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound     = errors.New("home not found")
-	ErrUserDisabled = errors.New("user disabled")
+	ErrNotFound        = errors.New("home not found")
+	ErrUserDisabled    = errors.New("user disabled")
+	ErrVersionConflict = errors.New("home was modified by another request")
 )
 
 // Storer interface declares the behaviour this package needs to persist and
@@ -29,28 +32,40 @@ type Storer interface {
 	Create(ctx context.Context, hme Home) error
 	Update(ctx context.Context, hme Home) error
 	Delete(ctx context.Context, hme Home) error
+	Restore(ctx context.Context, hme Home) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time) ([]Home, error)
+	RestoreByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time, dateUpdated time.Time) ([]Home, error)
+	UpdateStatusByUserID(ctx context.Context, userID uuid.UUID, status Status, dateUpdated time.Time) ([]Home, error)
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Home, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, homeID uuid.UUID) (Home, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Home, error)
+	QueryDeletedByID(ctx context.Context, homeID uuid.UUID) (Home, error)
 }
 
 // Business manages the set of APIs for home api access.
 type Business struct {
 	log      *logger.Logger
 	userBus  *userbus.Business
+	auditBus *auditbus.Business
 	delegate *delegate.Delegate
 	storer   Storer
 }
 
 // NewBusiness constructs a home business API for use.
-func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
-	return &Business{
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, auditBus *auditbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+	b := Business{
 		log:      log,
 		userBus:  userBus,
+		auditBus: auditBus,
 		delegate: delegate,
 		storer:   storer,
 	}
+
+	b.registerDelegateFunctions()
+
+	return &b
 }
 
 // NewWithTx constructs a new domain value that will use the
@@ -66,10 +81,26 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	auditBus := b.auditBus
+	if auditBus != nil {
+		auditBus, err = auditBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Events are published as part of the transaction so consumers only
+	// hear about changes that were committed.
+	delegate := b.delegate
+	if delegate != nil {
+		delegate = delegate.NewWithTx(tx)
+	}
+
 	bus := Business{
 		log:      b.log,
 		userBus:  userBus,
-		delegate: b.delegate,
+		auditBus: auditBus,
+		delegate: delegate,
 		storer:   storer,
 	}
 
@@ -77,7 +108,7 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 }
 
 // Create adds a new home to the system.
-func (b *Business) Create(ctx context.Context, nh NewHome) (Home, error) {
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nh NewHome) (Home, error) {
 	usr, err := b.userBus.QueryByID(ctx, nh.UserID)
 	if err != nil {
 		return Home{}, fmt.Errorf("user.querybyid: %s: %w", nh.UserID, err)
@@ -100,20 +131,34 @@ func (b *Business) Create(ctx context.Context, nh NewHome) (Home, error) {
 			State:    nh.Address.State,
 			Country:  nh.Address.Country,
 		},
+		TenantID:    usr.TenantID,
 		UserID:      nh.UserID,
+		Status:      Statuses.Active,
 		DateCreated: now,
 		DateUpdated: now,
+		Version:     1,
 	}
 
 	if err := b.storer.Create(ctx, hme); err != nil {
 		return Home{}, fmt.Errorf("create: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Create, hme.ID, nil, toAuditHome(hme)); err != nil {
+		return Home{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeCreated, hme); err != nil {
+		return Home{}, err
+	}
+
 	return hme, nil
 }
 
-// Update modifies information about a home.
-func (b *Business) Update(ctx context.Context, hme Home, uh UpdateHome) (Home, error) {
+// Update modifies information about a home. The home must still be at the
+// version it was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Update(ctx context.Context, actorID uuid.UUID, hme Home, uh UpdateHome) (Home, error) {
+	before := toAuditHome(hme)
+
 	if uh.Type != nil {
 		hme.Type = *uh.Type
 	}
@@ -145,23 +190,88 @@ func (b *Business) Update(ctx context.Context, hme Home, uh UpdateHome) (Home, e
 	}
 
 	hme.DateUpdated = time.Now()
+	hme.Version++
 
 	if err := b.storer.Update(ctx, hme); err != nil {
 		return Home{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Update, hme.ID, before, toAuditHome(hme)); err != nil {
+		return Home{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeUpdated, hme); err != nil {
+		return Home{}, err
+	}
+
 	return hme, nil
 }
 
-// Delete removes the specified home.
-func (b *Business) Delete(ctx context.Context, hme Home) error {
+// Delete marks the specified home as deleted. The home can be brought back
+// with Restore until it is purged. The home must still be at the version it
+// was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Delete(ctx context.Context, actorID uuid.UUID, hme Home) error {
+	before := toAuditHome(hme)
+
+	hme.DateDeleted = time.Now()
+	hme.Version++
+
 	if err := b.storer.Delete(ctx, hme); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Delete, hme.ID, before, toAuditHome(hme)); err != nil {
+		return err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeDeleted, hme); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Restore brings back the specified deleted home. The home can't be restored
+// while the user that owns it is deleted.
+func (b *Business) Restore(ctx context.Context, actorID uuid.UUID, hme Home) (Home, error) {
+	if _, err := b.userBus.QueryByID(ctx, hme.UserID); err != nil {
+		return Home{}, fmt.Errorf("user.querybyid: %s: %w", hme.UserID, err)
+	}
+
+	before := toAuditHome(hme)
+
+	hme.DateDeleted = time.Time{}
+	hme.DateUpdated = time.Now()
+	hme.Version++
+
+	if err := b.storer.Restore(ctx, hme); err != nil {
+		return Home{}, fmt.Errorf("restore: %w", err)
+	}
+
+	if err := b.audit(ctx, actorID, auditbus.Actions.Restore, hme.ID, before, toAuditHome(hme)); err != nil {
+		return Home{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeRestored, hme); err != nil {
+		return Home{}, err
+	}
+
+	return hme, nil
+}
+
+// Purge permanently removes the homes that were deleted longer ago than the
+// specified retention window. It returns the number of homes removed.
+func (b *Business) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	deletedBefore := time.Now().Add(-retention)
+
+	n, err := b.storer.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: deletedBefore[%s]: %w", deletedBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
 // Query retrieves a list of existing homes.
 func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Home, error) {
 	hmes, err := b.storer.Query(ctx, filter, orderBy, page)
@@ -196,3 +306,13 @@ func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Home,
 
 	return hmes, nil
 }
+
+// QueryDeletedByID finds the deleted home by the specified ID.
+func (b *Business) QueryDeletedByID(ctx context.Context, homeID uuid.UUID) (Home, error) {
+	hme, err := b.storer.QueryDeletedByID(ctx, homeID)
+	if err != nil {
+		return Home{}, fmt.Errorf("query: homeID[%s]: %w", homeID, err)
+	}
+
+	return hme, nil
+}
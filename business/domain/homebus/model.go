@@ -19,11 +19,15 @@ type Address struct {
 // Home represents an individual home.
 type Home struct {
 	ID          uuid.UUID
+	TenantID    uuid.UUID
 	UserID      uuid.UUID
 	Type        Type
 	Address     Address
+	Status      Status
 	DateCreated time.Time
 	DateUpdated time.Time
+	DateDeleted time.Time
+	Version     int
 }
 
 // NewHome is what we require from clients when adding a Home.
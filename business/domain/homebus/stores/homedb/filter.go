@@ -2,29 +2,68 @@ package homedb
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter homebus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(filter homebus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
 	if filter.ID != nil {
 		data["home_id"] = *filter.ID
 		wc = append(wc, "home_id = :home_id")
 	}
 
+	if len(filter.IDs) > 0 {
+		data["home_ids"] = filter.IDs
+		wc = append(wc, "home_id IN (:home_ids)")
+	}
+
 	if filter.UserID != nil {
 		data["user_id"] = *filter.UserID
 		wc = append(wc, "user_id = :user_id")
 	}
 
+	if len(filter.UserIDs) > 0 {
+		data["user_ids"] = filter.UserIDs
+		wc = append(wc, "user_id IN (:user_ids)")
+	}
+
 	if filter.Type != nil {
 		data["type"] = filter.Type.String()
 		wc = append(wc, "type = :type")
 	}
 
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, typ := range filter.Types {
+			types[i] = typ.String()
+		}
+		data["types"] = types
+		wc = append(wc, "type IN (:types)")
+	}
+
+	if filter.City != nil {
+		data["city"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(*filter.City))
+		wc = append(wc, "city ILIKE :city")
+	}
+
+	if filter.State != nil {
+		data["state"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(*filter.State))
+		wc = append(wc, "state ILIKE :state")
+	}
+
+	if filter.ZipCode != nil {
+		data["zip_code"] = fmt.Sprintf("%s%%", sqldb.EscapeLike(*filter.ZipCode))
+		wc = append(wc, "zip_code ILIKE :zip_code")
+	}
+
+	if filter.Country != nil {
+		data["country"] = sqldb.EscapeLike(*filter.Country)
+		wc = append(wc, "country ILIKE :country")
+	}
+
 	if filter.StartCreatedDate != nil {
 		data["start_date_created"] = filter.StartCreatedDate.UTC()
 		wc = append(wc, "date_created >= :start_date_created")
@@ -35,6 +74,20 @@ func (s *Store) applyFilter(filter homebus.QueryFilter, data map[string]any, buf
 		wc = append(wc, "date_created <= :end_date_created")
 	}
 
+	status := homebus.Statuses.Active
+	if filter.Status != nil {
+		status = *filter.Status
+	}
+	data["status"] = status.String()
+	wc = append(wc, "status = :status")
+
+	switch {
+	case filter.Deleted != nil && *filter.Deleted:
+		wc = append(wc, "date_deleted IS NOT NULL")
+	default:
+		wc = append(wc, "date_deleted IS NULL")
+	}
+
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
 		buf.WriteString(strings.Join(wc, " AND "))
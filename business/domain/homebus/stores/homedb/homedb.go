@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/sdk/order"
@@ -50,9 +51,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (homebus.Storer, error) {
 func (s *Store) Create(ctx context.Context, hme homebus.Home) error {
 	const q = `
     INSERT INTO homes
-        (home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated)
+        (home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, version)
     VALUES
-        (:home_id, :user_id, :type, :address_1, :address_2, :zip_code, :city, :state, :country, :date_created, :date_updated)`
+        (:home_id, :tenant_id, :user_id, :type, :address_1, :address_2, :zip_code, :city, :state, :country, :status, :date_created, :date_updated, :version)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBHome(hme)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -61,28 +62,160 @@ func (s *Store) Create(ctx context.Context, hme homebus.Home) error {
 	return nil
 }
 
-// Delete removes a home from the database.
+// Delete marks a home in the database as deleted. The home is only deleted
+// if the stored home is at the version before the one specified, otherwise
+// ErrVersionConflict is returned.
 func (s *Store) Delete(ctx context.Context, hme homebus.Home) error {
-	data := struct {
-		ID string `db:"home_id"`
-	}{
-		ID: hme.ID.String(),
+	const q = `
+    UPDATE
+        homes
+    SET
+        "date_deleted" = :date_deleted,
+        "version" = :version
+    WHERE
+        home_id = :home_id AND
+        version = :version - 1 AND
+        date_deleted IS NULL
+    RETURNING
+        version`
+
+	var dbVer struct {
+		Version int `db:"version"`
 	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBHome(hme), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", homebus.ErrVersionConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
 
+// Restore clears the deleted mark from a home in the database.
+func (s *Store) Restore(ctx context.Context, hme homebus.Home) error {
 	const q = `
-    DELETE FROM
-	    homes
-	WHERE
-	  	home_id = :home_id`
+    UPDATE
+        homes
+    SET
+        "date_deleted" = NULL,
+        "date_updated" = :date_updated,
+        "version" = version + 1
+    WHERE
+        home_id = :home_id AND
+        date_deleted IS NOT NULL`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBHome(hme)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
 	return nil
 }
 
-// Update replaces a home document in the database.
+// Purge removes the homes that were deleted before the specified time from
+// the database. Homes orders were delivered to are kept so the orders stay
+// whole. It returns the number of homes removed.
+func (s *Store) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	data := struct {
+		DeletedBefore time.Time `db:"deleted_before"`
+	}{
+		DeletedBefore: deletedBefore.UTC(),
+	}
+
+	const q = `
+    WITH purged AS (
+        DELETE FROM
+            homes AS h
+        WHERE
+            h.date_deleted < :deleted_before AND
+            NOT EXISTS (SELECT 1 FROM orders AS o WHERE o.home_id = h.home_id)
+        RETURNING
+            home_id
+    )
+    SELECT
+        count(1)
+    FROM
+        purged`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// DeleteByUserID marks all the homes that belong to the specified user as
+// deleted. The homes that were deleted are returned.
+func (s *Store) DeleteByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time) ([]homebus.Home, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateDeleted time.Time `db:"date_deleted"`
+	}{
+		UserID:      userID.String(),
+		DateDeleted: dateDeleted.UTC(),
+	}
+
+	const q = `
+    UPDATE
+        homes
+    SET
+        "date_deleted" = :date_deleted,
+        "version" = version + 1
+    WHERE
+        user_id = :user_id AND
+        date_deleted IS NULL
+    RETURNING
+        home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version`
+
+	var dbHmes []home
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbHmes); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusHomes(dbHmes)
+}
+
+// RestoreByUserID clears the deleted mark from the homes that belong to the
+// specified user and were deleted at the specified time. The homes that were
+// restored are returned.
+func (s *Store) RestoreByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time, dateUpdated time.Time) ([]homebus.Home, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateDeleted time.Time `db:"date_deleted"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		DateDeleted: dateDeleted.UTC(),
+		DateUpdated: dateUpdated.UTC(),
+	}
+
+	const q = `
+    UPDATE
+        homes
+    SET
+        "date_deleted" = NULL,
+        "date_updated" = :date_updated,
+        "version" = version + 1
+    WHERE
+        user_id = :user_id AND
+        date_deleted = :date_deleted
+    RETURNING
+        home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version`
+
+	var dbHmes []home
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbHmes); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusHomes(dbHmes)
+}
+
+// Update replaces a home document in the database. The home carries the
+// version being written and the update only happens if the stored home is at
+// the version before it, otherwise ErrVersionConflict is returned.
 func (s *Store) Update(ctx context.Context, hme homebus.Home) error {
 	const q = `
     UPDATE
@@ -95,17 +228,61 @@ func (s *Store) Update(ctx context.Context, hme homebus.Home) error {
         "state"         = :state,
         "country"       = :country,
         "type"          = :type,
-        "date_updated"  = :date_updated
+        "date_updated"  = :date_updated,
+        "version"       = :version
     WHERE
-        home_id = :home_id`
+        home_id = :home_id AND
+        version = :version - 1
+    RETURNING
+        version`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBHome(hme)); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	var dbVer struct {
+		Version int `db:"version"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBHome(hme), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", homebus.ErrVersionConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 
 	return nil
 }
 
+// UpdateStatusByUserID sets the status for all the homes that belong to the
+// specified user. The homes whose status changed are returned.
+func (s *Store) UpdateStatusByUserID(ctx context.Context, userID uuid.UUID, status homebus.Status, dateUpdated time.Time) ([]homebus.Home, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		Status      string    `db:"status"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		Status:      status.String(),
+		DateUpdated: dateUpdated.UTC(),
+	}
+
+	const q = `
+    UPDATE
+        homes
+    SET
+        "status"        = :status,
+        "date_updated"  = :date_updated,
+        "version"       = version + 1
+    WHERE
+        user_id = :user_id AND
+        status != :status
+    RETURNING
+        home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version`
+
+	var dbHmes []home
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbHmes); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusHomes(dbHmes)
+}
+
 // Query retrieves a list of existing homes from the database.
 func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, orderBy order.By, page page.Page) ([]homebus.Home, error) {
 	data := map[string]any{
@@ -115,12 +292,17 @@ func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, orderBy o
 
 	const q = `
     SELECT
-	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
+	    home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version
 	FROM
 	  	homes`
 
+	cursorClause, err := cursorClause(orderBy, page, data)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	s.applyFilter(filter, data, buf, cursorClause...)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -131,8 +313,8 @@ func (s *Store) Query(ctx context.Context, filter homebus.QueryFilter, orderBy o
 	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
 
 	var dbHmes []home
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbHmes); err != nil {
-		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, buf.String(), data, &dbHmes); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
 	}
 
 	hmes, err := toBusHomes(dbHmes)
@@ -159,7 +341,7 @@ func (s *Store) Count(ctx context.Context, filter homebus.QueryFilter) (int, err
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStructUsingIn(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
@@ -176,11 +358,12 @@ func (s *Store) QueryByID(ctx context.Context, homeID uuid.UUID) (homebus.Home,
 
 	const q = `
     SELECT
-	  	home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
+	  	home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version
     FROM
         homes
     WHERE
-        home_id = :home_id`
+        home_id = :home_id AND
+        date_deleted IS NULL`
 
 	var dbHme home
 	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbHme); err != nil {
@@ -203,11 +386,12 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]homebus.
 
 	const q = `
 	SELECT
-	    home_id, user_id, type, address_1, address_2, zip_code, city, state, country, date_created, date_updated
+	    home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version
 	FROM
 		homes
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND
+		date_deleted IS NULL`
 
 	var dbHmes []home
 	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbHmes); err != nil {
@@ -216,3 +400,31 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]homebus.
 
 	return toBusHomes(dbHmes)
 }
+
+// QueryDeletedByID gets the specified deleted home from the database.
+func (s *Store) QueryDeletedByID(ctx context.Context, homeID uuid.UUID) (homebus.Home, error) {
+	data := struct {
+		ID string `db:"home_id"`
+	}{
+		ID: homeID.String(),
+	}
+
+	const q = `
+    SELECT
+	  	home_id, tenant_id, user_id, type, address_1, address_2, zip_code, city, state, country, status, date_created, date_updated, date_deleted, version
+    FROM
+        homes
+    WHERE
+        home_id = :home_id AND
+        date_deleted IS NOT NULL`
+
+	var dbHme home
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbHme); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return homebus.Home{}, fmt.Errorf("db: %w", homebus.ErrNotFound)
+		}
+		return homebus.Home{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusHome(dbHme)
+}
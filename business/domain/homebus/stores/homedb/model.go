@@ -1,6 +1,7 @@
 package homedb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -9,22 +10,27 @@ import (
 )
 
 type home struct {
-	ID          uuid.UUID `db:"home_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Type        string    `db:"type"`
-	Address1    string    `db:"address_1"`
-	Address2    string    `db:"address_2"`
-	ZipCode     string    `db:"zip_code"`
-	City        string    `db:"city"`
-	Country     string    `db:"country"`
-	State       string    `db:"state"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID          uuid.UUID    `db:"home_id"`
+	TenantID    uuid.UUID    `db:"tenant_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Type        string       `db:"type"`
+	Address1    string       `db:"address_1"`
+	Address2    string       `db:"address_2"`
+	ZipCode     string       `db:"zip_code"`
+	City        string       `db:"city"`
+	Country     string       `db:"country"`
+	State       string       `db:"state"`
+	Status      string       `db:"status"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUpdated time.Time    `db:"date_updated"`
+	DateDeleted sql.NullTime `db:"date_deleted"`
+	Version     int          `db:"version"`
 }
 
 func toDBHome(bus homebus.Home) home {
 	db := home{
 		ID:          bus.ID,
+		TenantID:    bus.TenantID,
 		UserID:      bus.UserID,
 		Type:        bus.Type.String(),
 		Address1:    bus.Address.Address1,
@@ -33,8 +39,14 @@ func toDBHome(bus homebus.Home) home {
 		City:        bus.Address.City,
 		Country:     bus.Address.Country,
 		State:       bus.Address.State,
+		Status:      bus.Status.String(),
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  bus.DateDeleted.UTC(),
+			Valid: !bus.DateDeleted.IsZero(),
+		},
+		Version: bus.Version,
 	}
 
 	return db
@@ -46,10 +58,16 @@ func toBusHome(db home) (homebus.Home, error) {
 		return homebus.Home{}, fmt.Errorf("parse type: %w", err)
 	}
 
+	status, err := homebus.ParseStatus(db.Status)
+	if err != nil {
+		return homebus.Home{}, fmt.Errorf("parse status: %w", err)
+	}
+
 	bus := homebus.Home{
-		ID:     db.ID,
-		UserID: db.UserID,
-		Type:   typ,
+		ID:       db.ID,
+		TenantID: db.TenantID,
+		UserID:   db.UserID,
+		Type:     typ,
 		Address: homebus.Address{
 			Address1: db.Address1,
 			Address2: db.Address2,
@@ -58,8 +76,14 @@ func toBusHome(db home) (homebus.Home, error) {
 			Country:  db.Country,
 			State:    db.State,
 		},
+		Status:      status,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
+		Version:     db.Version,
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
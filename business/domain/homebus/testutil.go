@@ -46,7 +46,7 @@ func TestGenerateSeedHomes(ctx context.Context, n int, api *Business, userID uui
 
 	hmes := make([]Home, len(newHmes))
 	for i, nh := range newHmes {
-		hme, err := api.Create(ctx, nh)
+		hme, err := api.Create(ctx, userID, nh)
 		if err != nil {
 			return nil, fmt.Errorf("seeding home: idx: %d : %w", i, err)
 		}
@@ -0,0 +1,37 @@
+package lockoutbus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "lockout"
+
+// Set of delegate actions.
+const (
+	ActionLocked = "locked"
+)
+
+// ActionLockedParms represents the parameters for the locked action. The
+// UserID is only set when an email that belongs to a user was locked out.
+type ActionLockedParms struct {
+	Kind            string
+	Subject         string
+	UserID          uuid.UUID
+	Lockouts        int
+	DateLockedUntil time.Time
+}
+
+// Schema implements the delegate.Params interface.
+func (ActionLockedParms) Schema() delegate.Schema {
+	return delegate.Schema{Domain: DomainName, Action: ActionLocked, Version: 1}
+}
+
+// String returns a string representation of the action parameters.
+func (al ActionLockedParms) String() string {
+	return fmt.Sprintf("&EventParamsLocked{Kind:%v, UserID:%v, DateLockedUntil:%v}", al.Kind, al.UserID, al.DateLockedUntil)
+}
@@ -0,0 +1,63 @@
+package lockoutbus
+
+import "fmt"
+
+type kindSet struct {
+	Email Kind
+	IP    Kind
+}
+
+// Kinds represents the set of subjects failed attempts are tracked for.
+var Kinds = kindSet{
+	Email: newKind("EMAIL"),
+	IP:    newKind("IP"),
+}
+
+// =============================================================================
+
+// Set of known kinds.
+var kinds = make(map[string]Kind)
+
+// Kind represents what the subject of a lockout is.
+type Kind struct {
+	name string
+}
+
+func newKind(kind string) Kind {
+	k := Kind{kind}
+	kinds[kind] = k
+	return k
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	return k.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (k Kind) Equal(k2 Kind) bool {
+	return k.name == k2.name
+}
+
+// =============================================================================
+
+// ParseKind parses the string value and returns a kind if one exists.
+func ParseKind(value string) (Kind, error) {
+	kind, exists := kinds[value]
+	if !exists {
+		return Kind{}, fmt.Errorf("invalid kind %q", value)
+	}
+
+	return kind, nil
+}
+
+// MustParseKind parses the string value and returns a kind if one exists. If
+// an error occurs the function panics.
+func MustParseKind(value string) Kind {
+	kind, err := ParseKind(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return kind
+}
@@ -0,0 +1,222 @@
+package lockoutbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Lockout(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, email(db.BusDomain), "email")
+	unitest.Run(t, ip(db.BusDomain), "ip")
+	unitest.Run(t, unlock(db.BusDomain), "unlock")
+}
+
+// =============================================================================
+
+// newUser creates a user and returns it with its password.
+func newUser(ctx context.Context, busDomain dbtest.BusDomain) (userbus.User, string, error) {
+	nu := userbus.TestNewUsers(1, userbus.Roles.User)[0]
+
+	usr, err := busDomain.User.Create(ctx, uuid.Nil, nu)
+	if err != nil {
+		return userbus.User{}, "", err
+	}
+
+	return usr, nu.Password, nil
+}
+
+// fail makes failed attempts for the user from the client ip.
+func fail(ctx context.Context, busDomain dbtest.BusDomain, usr userbus.User, ip string, n int) error {
+	for i := 0; i < n; i++ {
+		_, err := busDomain.Lockout.Authenticate(ctx, usr.Email, ip, "wrong")
+		if !errors.Is(err, userbus.ErrAuthenticationFailure) {
+			return fmt.Errorf("attempt %d: got %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+func email(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "locked",
+			ExpResp: []any{lockoutbus.ErrLocked.Error(), 1},
+			ExcFunc: func(ctx context.Context) any {
+				usr, pass, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				var locked []lockoutbus.ActionLockedParms
+				delegate.Register(busDomain.Delegate, "test.locked", func(ctx context.Context, claim delegate.Claim, params lockoutbus.ActionLockedParms) error {
+					if params.UserID == usr.ID {
+						locked = append(locked, params)
+					}
+
+					return nil
+				})
+
+				if err := fail(ctx, busDomain, usr, "", 5); err != nil {
+					return err
+				}
+
+				// The right password is refused while the email is locked.
+				_, err = busDomain.Lockout.Authenticate(ctx, usr.Email, "", pass)
+				if !errors.Is(err, lockoutbus.ErrLocked) {
+					return fmt.Errorf("expected a lockout, got %v", err)
+				}
+
+				return []any{lockoutbus.ErrLocked.Error(), len(locked)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reset",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				usr, pass, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := fail(ctx, busDomain, usr, "", 4); err != nil {
+					return err
+				}
+
+				// A success clears the failures of the email.
+				if _, err := busDomain.Lockout.Authenticate(ctx, usr.Email, "", pass); err != nil {
+					return err
+				}
+
+				sub := lockoutbus.Subject{Kind: lockoutbus.Kinds.Email, Value: usr.Email.Address}
+
+				_, err = busDomain.Lockout.QueryBySubject(ctx, sub)
+				if !errors.Is(err, lockoutbus.ErrNotFound) {
+					return fmt.Errorf("expected the failures to be cleared, got %v", err)
+				}
+
+				return 0
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func ip(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "locked",
+			ExpResp: lockoutbus.ErrLocked.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				const clientIP = "10.0.0.1"
+
+				// The failures are spread over users so only the ip is
+				// locked out.
+				for i := 0; i < 5; i++ {
+					usr, _, err := newUser(ctx, busDomain)
+					if err != nil {
+						return err
+					}
+
+					if err := fail(ctx, busDomain, usr, clientIP, 4); err != nil {
+						return err
+					}
+				}
+
+				usr, pass, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Lockout.Authenticate(ctx, usr.Email, clientIP, pass)
+				if !errors.Is(err, lockoutbus.ErrLocked) {
+					return fmt.Errorf("expected a lockout, got %v", err)
+				}
+
+				// The user can still sign in from another client.
+				if _, err := busDomain.Lockout.Authenticate(ctx, usr.Email, "10.0.0.2", pass); err != nil {
+					return err
+				}
+
+				return lockoutbus.ErrLocked.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func unlock(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				usr, pass, err := newUser(ctx, busDomain)
+				if err != nil {
+					return err
+				}
+
+				if err := fail(ctx, busDomain, usr, "", 5); err != nil {
+					return err
+				}
+
+				sub := lockoutbus.Subject{Kind: lockoutbus.Kinds.Email, Value: usr.Email.Address}
+
+				l, err := busDomain.Lockout.QueryBySubject(ctx, sub)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Lockout.Unlock(ctx, sub); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Lockout.Authenticate(ctx, usr.Email, "", pass); err != nil {
+					return err
+				}
+
+				return l.Lockouts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,238 @@
+// Package lockoutbus provides business access to the failed sign in attempts
+// of an email or client ip and the lockouts they lead to.
+package lockoutbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for lockout operations.
+var (
+	ErrNotFound = errors.New("lockout not found")
+	ErrLocked   = errors.New("too many failed attempts, try again later")
+)
+
+// Failures are only counted within the failure window. A subject that is
+// locked out again within the lockout window is locked out twice as long
+// as the last time, up to the longest lockout.
+const (
+	failureWindow = 15 * time.Minute
+	lockoutWindow = 24 * time.Hour
+	baseLockout   = time.Minute
+	maxLockout    = 24 * time.Hour
+)
+
+// maxFailures is the number of failures a subject can have before it is
+// locked out. Many users can share a client ip so it gets more attempts.
+var maxFailures = map[Kind]int{
+	Kinds.Email: 5,
+	Kinds.IP:    20,
+}
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	RecordFailure(ctx context.Context, sub Subject, now time.Time, failuresSince time.Time, lockoutsSince time.Time) (Lockout, error)
+	Lock(ctx context.Context, l Lockout) error
+	Delete(ctx context.Context, sub Subject) error
+	QueryBySubject(ctx context.Context, sub Subject) (Lockout, error)
+}
+
+// Business manages the set of APIs for lockout access.
+type Business struct {
+	log      *logger.Logger
+	userBus  *userbus.Business
+	delegate *delegate.Delegate
+	storer   Storer
+}
+
+// NewBusiness constructs a lockout business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+	return &Business{
+		log:      log,
+		userBus:  userBus,
+		delegate: delegate,
+		storer:   storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		userBus:  userBus,
+		delegate: b.delegate,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Authenticate authenticates the user with the password unless the email or
+// the client ip is locked out, in which case the password isn't checked. A
+// failure counts against both, a success clears the failures of the email.
+// The ip is optional.
+func (b *Business) Authenticate(ctx context.Context, email mail.Address, ip string, password string) (userbus.User, error) {
+	subs := subjects(email, ip)
+	now := time.Now()
+
+	for _, sub := range subs {
+		l, err := b.storer.QueryBySubject(ctx, sub)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return userbus.User{}, fmt.Errorf("query: subject[%s]: %w", sub.Kind, err)
+		}
+
+		if l.IsLocked(now) {
+			return userbus.User{}, fmt.Errorf("subject[%s] until[%s]: %w", sub.Kind, l.DateLockedUntil.Format(time.RFC3339), ErrLocked)
+		}
+	}
+
+	usr, err := b.userBus.Authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) || errors.Is(err, userbus.ErrAuthenticationFailure) {
+			if err := b.fail(ctx, subs, now); err != nil {
+				return userbus.User{}, fmt.Errorf("fail: %w", err)
+			}
+		}
+		return userbus.User{}, err
+	}
+
+	// The failures of the ip are kept since an attacker that owns one
+	// account could clear them otherwise.
+	if err := b.storer.Delete(ctx, subs[0]); err != nil {
+		return userbus.User{}, fmt.Errorf("delete: subject[%s]: %w", subs[0].Kind, err)
+	}
+
+	return usr, nil
+}
+
+// Unlock clears the failed attempts and the lockout of the subject.
+func (b *Business) Unlock(ctx context.Context, sub Subject) error {
+	sub = normalize(sub)
+
+	if err := b.storer.Delete(ctx, sub); err != nil {
+		return fmt.Errorf("delete: subject[%s]: %w", sub.Kind, err)
+	}
+
+	return nil
+}
+
+// QueryBySubject finds the lockout of the subject.
+func (b *Business) QueryBySubject(ctx context.Context, sub Subject) (Lockout, error) {
+	sub = normalize(sub)
+
+	l, err := b.storer.QueryBySubject(ctx, sub)
+	if err != nil {
+		return Lockout{}, fmt.Errorf("query: subject[%s]: %w", sub.Kind, err)
+	}
+
+	return l, nil
+}
+
+// =============================================================================
+
+func (b *Business) fail(ctx context.Context, subs []Subject, now time.Time) error {
+	for _, sub := range subs {
+		l, err := b.storer.RecordFailure(ctx, sub, now, now.Add(-failureWindow), now.Add(-lockoutWindow))
+		if err != nil {
+			return fmt.Errorf("recordfailure: subject[%s]: %w", sub.Kind, err)
+		}
+
+		if l.Failures < maxFailures[sub.Kind] {
+			continue
+		}
+
+		if err := b.lock(ctx, l, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Business) lock(ctx context.Context, l Lockout, now time.Time) error {
+	duration := maxLockout
+	if l.Lockouts < 16 {
+		duration = min(baseLockout<<l.Lockouts, maxLockout)
+	}
+
+	l.Failures = 0
+	l.Lockouts++
+	l.DateLockedUntil = now.Add(duration)
+
+	if err := b.storer.Lock(ctx, l); err != nil {
+		return fmt.Errorf("lock: subject[%s]: %w", l.Subject.Kind, err)
+	}
+
+	b.log.Info(ctx, "lockout", "kind", l.Subject.Kind, "lockouts", l.Lockouts, "until", l.DateLockedUntil)
+
+	// The other domains are told which account was locked, when there is
+	// one for the email.
+	var userID uuid.UUID
+	if l.Subject.Kind == Kinds.Email {
+		if addr, err := mail.ParseAddress(l.Subject.Value); err == nil {
+			if usr, err := b.userBus.QueryByEmail(ctx, *addr); err == nil {
+				userID = usr.ID
+			}
+		}
+	}
+
+	if b.delegate != nil {
+		params := ActionLockedParms{
+			Kind:            l.Subject.Kind.String(),
+			Subject:         l.Subject.Value,
+			UserID:          userID,
+			Lockouts:        l.Lockouts,
+			DateLockedUntil: l.DateLockedUntil,
+		}
+
+		if err := delegate.Emit(ctx, b.delegate, params); err != nil {
+			return fmt.Errorf("failed to execute `%s` action: %w", ActionLocked, err)
+		}
+	}
+
+	return nil
+}
+
+func subjects(email mail.Address, ip string) []Subject {
+	subs := []Subject{
+		normalize(Subject{Kind: Kinds.Email, Value: email.Address}),
+	}
+
+	if ip != "" {
+		subs = append(subs, normalize(Subject{Kind: Kinds.IP, Value: ip}))
+	}
+
+	return subs
+}
+
+func normalize(sub Subject) Subject {
+	sub.Value = strings.ToLower(strings.TrimSpace(sub.Value))
+	return sub
+}
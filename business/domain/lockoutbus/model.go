@@ -0,0 +1,25 @@
+package lockoutbus
+
+import "time"
+
+// Subject identifies what failed attempts are tracked for.
+type Subject struct {
+	Kind  Kind
+	Value string
+}
+
+// Lockout represents the failed attempts of a subject and until when the
+// subject is locked out. Lockouts counts the times the subject was locked
+// out so the next lockout can last longer.
+type Lockout struct {
+	Subject         Subject
+	Failures        int
+	Lockouts        int
+	DateLastFailure time.Time
+	DateLockedUntil time.Time
+}
+
+// IsLocked reports whether the subject is locked out at the specified time.
+func (l Lockout) IsLocked(now time.Time) bool {
+	return now.Before(l.DateLockedUntil)
+}
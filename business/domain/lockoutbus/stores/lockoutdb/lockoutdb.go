@@ -0,0 +1,158 @@
+// Package lockoutdb contains lockout related CRUD functionality.
+package lockoutdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for lockout database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (lockoutbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// RecordFailure counts a failed attempt for the subject and returns the
+// lockout with the failures counted so far. The count is done in the
+// database so concurrent attempts are all counted. Failures before the
+// failures since time and lockouts before the lockouts since time are
+// forgotten.
+func (s *Store) RecordFailure(ctx context.Context, sub lockoutbus.Subject, now time.Time, failuresSince time.Time, lockoutsSince time.Time) (lockoutbus.Lockout, error) {
+	data := struct {
+		Kind          string    `db:"kind"`
+		Subject       string    `db:"subject"`
+		Now           time.Time `db:"now"`
+		FailuresSince time.Time `db:"failures_since"`
+		LockoutsSince time.Time `db:"lockouts_since"`
+	}{
+		Kind:          sub.Kind.String(),
+		Subject:       sub.Value,
+		Now:           now.UTC(),
+		FailuresSince: failuresSince.UTC(),
+		LockoutsSince: lockoutsSince.UTC(),
+	}
+
+	const q = `
+	INSERT INTO lockouts AS l
+		(kind, subject, failures, lockouts, date_last_failure, date_locked_until)
+	VALUES
+		(:kind, :subject, 1, 0, :now, NULL)
+	ON CONFLICT (kind, subject) DO UPDATE SET
+		failures = CASE WHEN l.date_last_failure < :failures_since THEN 1 ELSE l.failures + 1 END,
+		lockouts = CASE WHEN l.date_last_failure < :lockouts_since THEN 0 ELSE l.lockouts END,
+		date_last_failure = :now
+	RETURNING
+		kind, subject, failures, lockouts, date_last_failure, date_locked_until`
+
+	var dbL lockout
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbL); err != nil {
+		return lockoutbus.Lockout{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusLockout(dbL)
+}
+
+// Lock stores the lockout of the subject.
+func (s *Store) Lock(ctx context.Context, l lockoutbus.Lockout) error {
+	const q = `
+	UPDATE
+		lockouts
+	SET
+		failures = :failures,
+		lockouts = :lockouts,
+		date_locked_until = :date_locked_until
+	WHERE
+		kind = :kind AND
+		subject = :subject`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBLockout(l)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the failed attempts and lockout of the subject.
+func (s *Store) Delete(ctx context.Context, sub lockoutbus.Subject) error {
+	data := struct {
+		Kind    string `db:"kind"`
+		Subject string `db:"subject"`
+	}{
+		Kind:    sub.Kind.String(),
+		Subject: sub.Value,
+	}
+
+	const q = `
+	DELETE FROM
+		lockouts
+	WHERE
+		kind = :kind AND
+		subject = :subject`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryBySubject gets the lockout of the subject from the database.
+func (s *Store) QueryBySubject(ctx context.Context, sub lockoutbus.Subject) (lockoutbus.Lockout, error) {
+	data := struct {
+		Kind    string `db:"kind"`
+		Subject string `db:"subject"`
+	}{
+		Kind:    sub.Kind.String(),
+		Subject: sub.Value,
+	}
+
+	const q = `
+	SELECT
+		kind, subject, failures, lockouts, date_last_failure, date_locked_until
+	FROM
+		lockouts
+	WHERE
+		kind = :kind AND
+		subject = :subject`
+
+	var dbL lockout
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbL); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return lockoutbus.Lockout{}, fmt.Errorf("db: %w", lockoutbus.ErrNotFound)
+		}
+		return lockoutbus.Lockout{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusLockout(dbL)
+}
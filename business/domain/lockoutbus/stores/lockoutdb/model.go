@@ -0,0 +1,57 @@
+package lockoutdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+)
+
+type lockout struct {
+	Kind            string       `db:"kind"`
+	Subject         string       `db:"subject"`
+	Failures        int          `db:"failures"`
+	Lockouts        int          `db:"lockouts"`
+	DateLastFailure time.Time    `db:"date_last_failure"`
+	DateLockedUntil sql.NullTime `db:"date_locked_until"`
+}
+
+func toDBLockout(bus lockoutbus.Lockout) lockout {
+	db := lockout{
+		Kind:            bus.Subject.Kind.String(),
+		Subject:         bus.Subject.Value,
+		Failures:        bus.Failures,
+		Lockouts:        bus.Lockouts,
+		DateLastFailure: bus.DateLastFailure.UTC(),
+		DateLockedUntil: sql.NullTime{
+			Time:  bus.DateLockedUntil.UTC(),
+			Valid: !bus.DateLockedUntil.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusLockout(db lockout) (lockoutbus.Lockout, error) {
+	kind, err := lockoutbus.ParseKind(db.Kind)
+	if err != nil {
+		return lockoutbus.Lockout{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	bus := lockoutbus.Lockout{
+		Subject: lockoutbus.Subject{
+			Kind:  kind,
+			Value: db.Subject,
+		},
+		Failures:        db.Failures,
+		Lockouts:        db.Lockouts,
+		DateLastFailure: db.DateLastFailure.In(time.Local),
+	}
+
+	if db.DateLockedUntil.Valid {
+		bus.DateLockedUntil = db.DateLockedUntil.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,55 @@
+package orderbus
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/uuid"
+)
+
+// Item represents an individual product that was purchased in an order.
+type Item struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Cost      productbus.Money
+}
+
+// Order represents an individual sale.
+type Order struct {
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	UserID      uuid.UUID
+	HomeID      uuid.UUID
+	Items       []Item
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Total returns the total cost of all the items in the order. All the items
+// in an order share the same currency.
+func (o Order) Total() productbus.Money {
+	currency := productbus.DefaultCurrency
+	if len(o.Items) > 0 {
+		currency = o.Items[0].Cost.Currency()
+	}
+
+	var cents int64
+	for _, itm := range o.Items {
+		cents += itm.Cost.Mul(itm.Quantity).Cents()
+	}
+
+	return productbus.NewMoney(cents, currency)
+}
+
+// NewItem is what we require from clients for each product being purchased.
+type NewItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// NewOrder is what we require from clients when adding an Order.
+type NewOrder struct {
+	UserID uuid.UUID
+	HomeID uuid.UUID
+	Items  []NewItem
+}
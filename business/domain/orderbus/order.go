@@ -0,0 +1,14 @@
+package orderbus
+
+import "github.com/ardanlabs/encore/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "order_id"
+	OrderByUserID      = "user_id"
+	OrderByHomeID      = "home_id"
+	OrderByDateCreated = "date_created"
+)
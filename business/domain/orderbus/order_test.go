@@ -0,0 +1,348 @@
+package orderbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Order(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, createBad(db.BusDomain, sd), "create-bad")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestGenerateSeedProducts(ctx, 3, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	hmes, err := homebus.TestGenerateSeedHomes(ctx, 1, busDomain.Home, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding homes : %w", err)
+	}
+
+	// The first product is left untouched so the create tests can buy
+	// its entire stock.
+	ords, err := orderbus.TestGenerateSeedOrders(ctx, 1, busDomain.Order, usrs[0].ID, hmes[0].ID, prds[1:])
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding orders : %w", err)
+	}
+
+	for i := 1; i < len(prds); i++ {
+		prds[i].Quantity--
+		prds[i].Version++
+	}
+
+	tu1 := unitest.User{
+		User:     usrs[0],
+		Products: prds,
+		Homes:    hmes,
+		Orders:   ords,
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err = productbus.TestGenerateSeedProducts(ctx, 2, busDomain.Product, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	hmes, err = homebus.TestGenerateSeedHomes(ctx, 1, busDomain.Home, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding homes : %w", err)
+	}
+
+	ords, err = orderbus.TestGenerateSeedOrders(ctx, 1, busDomain.Order, usrs[0].ID, hmes[0].ID, prds)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding orders : %w", err)
+	}
+
+	for i := range prds {
+		prds[i].Quantity--
+		prds[i].Version++
+	}
+
+	tu2 := unitest.User{
+		User:     usrs[0],
+		Products: prds,
+		Homes:    hmes,
+		Orders:   ords,
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Users:  []unitest.User{tu1},
+		Admins: []unitest.User{tu2},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	ords := make([]orderbus.Order, 0, len(sd.Admins[0].Orders)+len(sd.Users[0].Orders))
+	ords = append(ords, sd.Admins[0].Orders...)
+	ords = append(ords, sd.Users[0].Orders...)
+
+	sort.Slice(ords, func(i, j int) bool {
+		return ords[i].ID.String() <= ords[j].ID.String()
+	})
+
+	table := []unitest.Table{
+		{
+			Name:    "all",
+			ExpResp: ords,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Order.Query(ctx, orderbus.QueryFilter{}, orderbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]orderbus.Order)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]orderbus.Order)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "byid",
+			ExpResp: sd.Users[0].Orders[0],
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Order.QueryByID(ctx, sd.Users[0].Orders[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orderbus.Order)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(orderbus.Order)
+
+				if gotResp.DateCreated.Format(time.RFC3339) == expResp.DateCreated.Format(time.RFC3339) {
+					expResp.DateCreated = gotResp.DateCreated
+				}
+
+				if gotResp.DateUpdated.Format(time.RFC3339) == expResp.DateUpdated.Format(time.RFC3339) {
+					expResp.DateUpdated = gotResp.DateUpdated
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Users[0].Products[0]
+
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: orderbus.Order{
+				TenantID: sd.Users[0].TenantID,
+				UserID:   sd.Users[0].ID,
+				HomeID:   sd.Users[0].Homes[0].ID,
+				Items: []orderbus.Item{
+					{
+						ProductID: prd.ID,
+						Quantity:  prd.Quantity,
+						Cost:      prd.Cost,
+					},
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: sd.Users[0].ID,
+					HomeID: sd.Users[0].Homes[0].ID,
+					Items: []orderbus.NewItem{
+						{
+							ProductID: prd.ID,
+							Quantity:  prd.Quantity,
+						},
+					},
+				}
+
+				resp, err := busDomain.Order.Create(ctx, no)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orderbus.Order)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(orderbus.Order)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "stock",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp.Quantity
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func createBad(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "insufficient",
+			ExpResp: productbus.ErrInsufficientStock,
+			ExcFunc: func(ctx context.Context) any {
+				prd := sd.Admins[0].Products[0]
+
+				no := orderbus.NewOrder{
+					UserID: sd.Admins[0].ID,
+					HomeID: sd.Admins[0].Homes[0].ID,
+					Items: []orderbus.NewItem{
+						{
+							ProductID: prd.ID,
+							Quantity:  prd.Quantity + 1,
+						},
+					},
+				}
+
+				_, err := busDomain.Order.Create(ctx, no)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists {
+					return "error occurred"
+				}
+
+				if !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", gotErr, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "homenotowned",
+			ExpResp: orderbus.ErrHomeNotOwned,
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: sd.Users[0].ID,
+					HomeID: sd.Admins[0].Homes[0].ID,
+					Items: []orderbus.NewItem{
+						{
+							ProductID: sd.Admins[0].Products[1].ID,
+							Quantity:  1,
+						},
+					},
+				}
+
+				_, err := busDomain.Order.Create(ctx, no)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists {
+					return "error occurred"
+				}
+
+				if !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", gotErr, exp)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,205 @@
+// Package orderbus provides business access to order domain.
+package orderbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound         = errors.New("order not found")
+	ErrUserDisabled     = errors.New("user disabled")
+	ErrNoItems          = errors.New("order has no items")
+	ErrDuplicateProduct = errors.New("product listed more than once")
+	ErrHomeNotOwned     = errors.New("home does not belong to the buyer")
+	ErrProductInactive  = errors.New("product is not active")
+	ErrCurrencyMismatch = errors.New("products are priced in different currencies")
+)
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, ord Order) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+}
+
+// Business manages the set of APIs for order access.
+type Business struct {
+	log        *logger.Logger
+	userBus    *userbus.Business
+	productBus *productbus.Business
+	homeBus    *homebus.Business
+	storer     Storer
+}
+
+// NewBusiness constructs an order business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, productBus *productbus.Business, homeBus *homebus.Business, storer Storer) *Business {
+	return &Business{
+		log:        log,
+		userBus:    userBus,
+		productBus: productBus,
+		homeBus:    homeBus,
+		storer:     storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	productBus, err := b.productBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	homeBus, err := b.homeBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:        b.log,
+		userBus:    userBus,
+		productBus: productBus,
+		homeBus:    homeBus,
+		storer:     storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new order to the system and removes the purchased quantity
+// of each product from stock. The stock changes and the order are written
+// by separate store calls, so this should be executed inside a transaction
+// to keep them consistent if a later step fails.
+func (b *Business) Create(ctx context.Context, no NewOrder) (Order, error) {
+	if len(no.Items) == 0 {
+		return Order{}, ErrNoItems
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, no.UserID)
+	if err != nil {
+		return Order{}, fmt.Errorf("user.querybyid: %s: %w", no.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return Order{}, ErrUserDisabled
+	}
+
+	hme, err := b.homeBus.QueryByID(ctx, no.HomeID)
+	if err != nil {
+		return Order{}, fmt.Errorf("home.querybyid: %s: %w", no.HomeID, err)
+	}
+
+	if hme.UserID != usr.ID {
+		return Order{}, ErrHomeNotOwned
+	}
+
+	// The stock of the products is locked in product id order, so two orders
+	// for the same products always lock them in the same order and can't
+	// deadlock. It's also the order the store returns the items in.
+	newItems := slices.Clone(no.Items)
+	sort.Slice(newItems, func(i, j int) bool {
+		return newItems[i].ProductID.String() < newItems[j].ProductID.String()
+	})
+
+	items := make([]Item, len(newItems))
+	seen := make(map[uuid.UUID]struct{}, len(newItems))
+
+	for i, ni := range newItems {
+		if _, exists := seen[ni.ProductID]; exists {
+			return Order{}, fmt.Errorf("productID[%s]: %w", ni.ProductID, ErrDuplicateProduct)
+		}
+		seen[ni.ProductID] = struct{}{}
+
+		// The cost, currency and status are taken from the row the stock was
+		// removed from, which stays locked until the order is committed.
+		prd, err := b.productBus.ReduceQuantity(ctx, productbus.Product{ID: ni.ProductID}, ni.Quantity)
+		if err != nil {
+			return Order{}, fmt.Errorf("product.reducequantity: %w", err)
+		}
+
+		if !prd.Status.Equal(productbus.Statuses.Active) {
+			return Order{}, fmt.Errorf("productID[%s]: %w", ni.ProductID, ErrProductInactive)
+		}
+
+		if i > 0 && prd.Cost.Currency() != items[0].Cost.Currency() {
+			return Order{}, fmt.Errorf("productID[%s]: %w", ni.ProductID, ErrCurrencyMismatch)
+		}
+
+		items[i] = Item{
+			ProductID: prd.ID,
+			Quantity:  ni.Quantity,
+			Cost:      prd.Cost,
+		}
+	}
+
+	now := time.Now()
+
+	ord := Order{
+		ID:          uuid.New(),
+		TenantID:    usr.TenantID,
+		UserID:      usr.ID,
+		HomeID:      hme.ID,
+		Items:       items,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, ord); err != nil {
+		return Order{}, fmt.Errorf("create: %w", err)
+	}
+
+	return ord, nil
+}
+
+// Query retrieves a list of existing orders.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error) {
+	ords, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return ords, nil
+}
+
+// Count returns the total number of orders.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the order by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error) {
+	ord, err := b.storer.QueryByID(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("query: orderID[%s]: %w", orderID, err)
+	}
+
+	return ord, nil
+}
@@ -0,0 +1,40 @@
+package orderdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+)
+
+func (s *Store) applyFilter(filter orderbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
+	if filter.ID != nil {
+		data["order_id"] = *filter.ID
+		wc = append(wc, "order_id = :order_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.HomeID != nil {
+		data["home_id"] = *filter.HomeID
+		wc = append(wc, "home_id = :home_id")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
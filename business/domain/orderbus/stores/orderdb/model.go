@@ -0,0 +1,103 @@
+package orderdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/uuid"
+)
+
+type dbOrder struct {
+	ID          uuid.UUID `db:"order_id"`
+	TenantID    uuid.UUID `db:"tenant_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	HomeID      uuid.UUID `db:"home_id"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+type dbItem struct {
+	OrderID   uuid.UUID `db:"order_id"`
+	TenantID  uuid.UUID `db:"tenant_id"`
+	ProductID uuid.UUID `db:"product_id"`
+	Quantity  int       `db:"quantity"`
+	Cost      string    `db:"cost"`
+	Currency  string    `db:"currency"`
+}
+
+func toDBOrder(bus orderbus.Order) dbOrder {
+	db := dbOrder{
+		ID:          bus.ID,
+		TenantID:    bus.TenantID,
+		UserID:      bus.UserID,
+		HomeID:      bus.HomeID,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toDBItems(bus orderbus.Order) []dbItem {
+	db := make([]dbItem, len(bus.Items))
+	for i, itm := range bus.Items {
+		db[i] = dbItem{
+			OrderID:   bus.ID,
+			TenantID:  bus.TenantID,
+			ProductID: itm.ProductID,
+			Quantity:  itm.Quantity,
+			Cost:      itm.Cost.String(),
+			Currency:  itm.Cost.Currency(),
+		}
+	}
+
+	return db
+}
+
+func toBusOrder(db dbOrder, dbItems []dbItem) (orderbus.Order, error) {
+	items := make([]orderbus.Item, len(dbItems))
+	for i, itm := range dbItems {
+		cost, err := productbus.ParseMoney(itm.Cost, itm.Currency)
+		if err != nil {
+			return orderbus.Order{}, fmt.Errorf("parse cost: %w", err)
+		}
+
+		items[i] = orderbus.Item{
+			ProductID: itm.ProductID,
+			Quantity:  itm.Quantity,
+			Cost:      cost,
+		}
+	}
+
+	bus := orderbus.Order{
+		ID:          db.ID,
+		TenantID:    db.TenantID,
+		UserID:      db.UserID,
+		HomeID:      db.HomeID,
+		Items:       items,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusOrders(dbs []dbOrder, dbItems []dbItem) ([]orderbus.Order, error) {
+	itemsByOrder := make(map[uuid.UUID][]dbItem, len(dbs))
+	for _, itm := range dbItems {
+		itemsByOrder[itm.OrderID] = append(itemsByOrder[itm.OrderID], itm)
+	}
+
+	bus := make([]orderbus.Order, len(dbs))
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusOrder(db, itemsByOrder[db.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
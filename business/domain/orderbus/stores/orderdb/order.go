@@ -0,0 +1,26 @@
+package orderdb
+
+import (
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+)
+
+var orderByFields = map[string]string{
+	orderbus.OrderByID:          "order_id",
+	orderbus.OrderByUserID:      "user_id",
+	orderbus.OrderByHomeID:      "home_id",
+	orderbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	return sqldb.OrderByClause(orderBy, orderByFields, "order_id")
+}
+
+// cursorClause returns the condition that resumes the query after the row the
+// page cursor points at. No condition is returned when the page isn't
+// addressed by a cursor.
+func cursorClause(orderBy order.By, page page.Page, data map[string]any) ([]string, error) {
+	return sqldb.CursorClause(orderBy, page, orderByFields, "order_id", data)
+}
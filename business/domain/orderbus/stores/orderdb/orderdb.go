@@ -0,0 +1,206 @@
+// Package orderdb contains order related CRUD functionality.
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for order database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (orderbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new order and its items into the database.
+func (s *Store) Create(ctx context.Context, ord orderbus.Order) error {
+	const q = `
+	INSERT INTO orders
+		(order_id, tenant_id, user_id, home_id, date_created, date_updated)
+	VALUES
+		(:order_id, :tenant_id, :user_id, :home_id, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrder(ord)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	const qi = `
+	INSERT INTO order_items
+		(order_id, tenant_id, product_id, quantity, cost, currency)
+	VALUES
+		(:order_id, :tenant_id, :product_id, :quantity, :cost, :currency)`
+
+	for _, itm := range toDBItems(ord) {
+		if err := sqldb.NamedExecContext(ctx, s.log, s.db, qi, itm); err != nil {
+			return fmt.Errorf("namedexeccontext: item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing orders from the database.
+func (s *Store) Query(ctx context.Context, filter orderbus.QueryFilter, orderBy order.By, page page.Page) ([]orderbus.Order, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		order_id, tenant_id, user_id, home_id, date_created, date_updated
+	FROM
+		orders`
+
+	cursorClause, err := cursorClause(orderBy, page, data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf, cursorClause...)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []dbOrder
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	orderIDs := make([]uuid.UUID, len(dbOrds))
+	for i, dbOrd := range dbOrds {
+		orderIDs[i] = dbOrd.ID
+	}
+
+	dbItems, err := s.queryItems(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBusOrders(dbOrds, dbItems)
+}
+
+// Count returns the total number of orders in the DB.
+func (s *Store) Count(ctx context.Context, filter orderbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified order from the database.
+func (s *Store) QueryByID(ctx context.Context, orderID uuid.UUID) (orderbus.Order, error) {
+	data := struct {
+		ID string `db:"order_id"`
+	}{
+		ID: orderID.String(),
+	}
+
+	const q = `
+	SELECT
+		order_id, tenant_id, user_id, home_id, date_created, date_updated
+	FROM
+		orders
+	WHERE
+		order_id = :order_id`
+
+	var dbOrd dbOrder
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbOrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orderbus.Order{}, fmt.Errorf("db: %w", orderbus.ErrNotFound)
+		}
+		return orderbus.Order{}, fmt.Errorf("db: %w", err)
+	}
+
+	dbItems, err := s.queryItems(ctx, []uuid.UUID{dbOrd.ID})
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return toBusOrder(dbOrd, dbItems)
+}
+
+// queryItems retrieves the items for the specified set of orders.
+func (s *Store) queryItems(ctx context.Context, orderIDs []uuid.UUID) ([]dbItem, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	data := struct {
+		OrderIDs []uuid.UUID `db:"order_ids"`
+	}{
+		OrderIDs: orderIDs,
+	}
+
+	const q = `
+	SELECT
+		order_id, product_id, quantity, cost, currency
+	FROM
+		order_items
+	WHERE
+		order_id IN (:order_ids)
+	ORDER BY
+		order_id, product_id`
+
+	var dbItems []dbItem
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
+	}
+
+	return dbItems, nil
+}
@@ -0,0 +1,52 @@
+package orderbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/google/uuid"
+)
+
+// TestGenerateNewOrders is a helper method for testing. Each order purchases
+// a single unit of every product provided.
+func TestGenerateNewOrders(n int, userID uuid.UUID, homeID uuid.UUID, prds []productbus.Product) []NewOrder {
+	newOrds := make([]NewOrder, n)
+
+	for i := 0; i < n; i++ {
+		items := make([]NewItem, len(prds))
+		for j, prd := range prds {
+			items[j] = NewItem{
+				ProductID: prd.ID,
+				Quantity:  1,
+			}
+		}
+
+		no := NewOrder{
+			UserID: userID,
+			HomeID: homeID,
+			Items:  items,
+		}
+
+		newOrds[i] = no
+	}
+
+	return newOrds
+}
+
+// TestGenerateSeedOrders is a helper method for testing.
+func TestGenerateSeedOrders(ctx context.Context, n int, api *Business, userID uuid.UUID, homeID uuid.UUID, prds []productbus.Product) ([]Order, error) {
+	newOrds := TestGenerateNewOrders(n, userID, homeID, prds)
+
+	ords := make([]Order, len(newOrds))
+	for i, no := range newOrds {
+		ord, err := api.Create(ctx, no)
+		if err != nil {
+			return nil, fmt.Errorf("seeding order: idx: %d : %w", i, err)
+		}
+
+		ords[i] = ord
+	}
+
+	return ords, nil
+}
@@ -0,0 +1,21 @@
+package orgbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization represents a customer organization. Every organization is a
+// tenant and owns the users, products and homes created in it.
+type Organization struct {
+	ID          uuid.UUID
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewOrganization contains information needed to create a new organization.
+type NewOrganization struct {
+	Name string
+}
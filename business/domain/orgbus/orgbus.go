@@ -0,0 +1,89 @@
+// Package orgbus provides business access to organization domain.
+package orgbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// DefaultID is the id of the organization that operates the deployment. The
+// rows that existed before organizations were introduced belong to it.
+var DefaultID = uuid.MustParse("3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10")
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("organization not found")
+	ErrUniqueName = errors.New("organization name is not unique")
+)
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, org Organization) error
+	QueryByID(ctx context.Context, orgID uuid.UUID) (Organization, error)
+}
+
+// Business manages the set of APIs for organization access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an organization business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new organization to the system.
+func (b *Business) Create(ctx context.Context, no NewOrganization) (Organization, error) {
+	now := time.Now()
+
+	org := Organization{
+		ID:          uuid.New(),
+		Name:        no.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, org); err != nil {
+		return Organization{}, fmt.Errorf("create: %w", err)
+	}
+
+	return org, nil
+}
+
+// QueryByID finds the organization by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orgID uuid.UUID) (Organization, error) {
+	org, err := b.storer.QueryByID(ctx, orgID)
+	if err != nil {
+		return Organization{}, fmt.Errorf("query: orgID[%s]: %w", orgID, err)
+	}
+
+	return org, nil
+}
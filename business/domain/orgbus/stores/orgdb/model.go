@@ -0,0 +1,33 @@
+package orgdb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/google/uuid"
+)
+
+type organization struct {
+	ID          uuid.UUID `db:"org_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBOrganization(bus orgbus.Organization) organization {
+	return organization{
+		ID:          bus.ID,
+		Name:        bus.Name,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusOrganization(db organization) orgbus.Organization {
+	return orgbus.Organization{
+		ID:          db.ID,
+		Name:        db.Name,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
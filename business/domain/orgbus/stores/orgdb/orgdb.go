@@ -0,0 +1,89 @@
+// Package orgdb contains organization related CRUD functionality.
+package orgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for organization database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (orgbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new organization into the database.
+func (s *Store) Create(ctx context.Context, org orgbus.Organization) error {
+	const q = `
+	INSERT INTO organizations
+		(org_id, name, date_created, date_updated)
+	VALUES
+		(:org_id, :name, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrganization(org)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", orgbus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified organization from the database.
+func (s *Store) QueryByID(ctx context.Context, orgID uuid.UUID) (orgbus.Organization, error) {
+	data := struct {
+		ID string `db:"org_id"`
+	}{
+		ID: orgID.String(),
+	}
+
+	const q = `
+	SELECT
+		org_id, name, date_created, date_updated
+	FROM
+		organizations
+	WHERE
+		org_id = :org_id`
+
+	var dbOrg organization
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbOrg); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orgbus.Organization{}, fmt.Errorf("db: %w", orgbus.ErrNotFound)
+		}
+		return orgbus.Organization{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusOrganization(dbOrg), nil
+}
@@ -0,0 +1,62 @@
+package productbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+// auditProduct is the view of a product that is recorded in the audit log.
+type auditProduct struct {
+	UserID      string     `json:"userID"`
+	Name        string     `json:"name"`
+	Cost        string     `json:"cost"`
+	Currency    string     `json:"currency"`
+	Quantity    int        `json:"quantity"`
+	Status      string     `json:"status"`
+	DateDeleted *time.Time `json:"dateDeleted,omitempty"`
+}
+
+func toAuditProduct(prd Product) auditProduct {
+	var dateDeleted *time.Time
+	if !prd.DateDeleted.IsZero() {
+		dateDeleted = &prd.DateDeleted
+	}
+
+	return auditProduct{
+		UserID:      prd.UserID.String(),
+		Name:        prd.Name.String(),
+		Cost:        prd.Cost.String(),
+		Currency:    prd.Cost.Currency(),
+		Quantity:    prd.Quantity,
+		Status:      prd.Status.String(),
+		DateDeleted: dateDeleted,
+	}
+}
+
+// audit records the change made to the product in the audit log. If the
+// business was constructed for query only, there won't be an audit api
+// provided.
+func (b *Business) audit(ctx context.Context, actorID uuid.UUID, action auditbus.Action, productID uuid.UUID, before any, after any) error {
+	if b.auditBus == nil {
+		return nil
+	}
+
+	na := auditbus.NewAudit{
+		ActorID:  actorID,
+		Entity:   auditbus.Entities.Product,
+		EntityID: productID,
+		Action:   action,
+		Before:   before,
+		After:    after,
+	}
+
+	if _, err := b.auditBus.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: action[%s]: %w", action, err)
+	}
+
+	return nil
+}
@@ -2,11 +2,13 @@ package productbus
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
+	"github.com/google/uuid"
 )
 
 // registerDelegateFunctions will register action functions with the delegate
@@ -14,23 +16,138 @@ import (
 // delegate provided.
 func (b *Business) registerDelegateFunctions() {
 	if b.delegate != nil {
-		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+		delegate.Register(b.delegate, "productbus.user-updated", b.actionUserUpdated)
+		delegate.Register(b.delegate, "productbus.user-deleted", b.actionUserDeleted)
+		delegate.Register(b.delegate, "productbus.user-restored", b.actionUserRestored)
 	}
 }
 
 // actionUserUpdated is executed by the user domain indirectly when a user is updated.
-func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) error {
-	var params userbus.ActionUpdatedParms
-	err := json.Unmarshal(data.RawParams, &params)
+func (b *Business) actionUserUpdated(ctx context.Context, claim delegate.Claim, params userbus.ActionUpdatedParms) error {
+	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", params.Enabled)
+
+	// Only a change to the enabled flag affects the products. When a user is
+	// disabled their products are hidden and when they are enabled again the
+	// products are restored.
+	if params.Enabled == nil {
+		return nil
+	}
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
 	if err != nil {
-		return fmt.Errorf("expected an encoded %T: %w", params, err)
+		return err
 	}
 
-	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", params.Enabled)
+	status := Statuses.Active
+	if !*params.Enabled {
+		status = Statuses.Inactive
+	}
+
+	products, err := bus.storer.UpdateStatusByUserID(ctx, params.UserID, status, time.Now())
+	if err != nil {
+		return fmt.Errorf("updatestatusbyuserid: userID[%s] status[%s]: %w", params.UserID, status, err)
+	}
+
+	for _, prd := range products {
+		if err := bus.publishEvent(ctx, events.TypeUpdated, prd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// actionUserDeleted is executed by the user domain indirectly when a user is
+// deleted. The user's products are deleted with the same timestamp so they
+// can be told apart from products that were deleted on their own.
+func (b *Business) actionUserDeleted(ctx context.Context, claim delegate.Claim, params userbus.ActionDeletedParms) error {
+	b.log.Info(ctx, "action-userdeleted", "user_id", params.UserID)
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	products, err := bus.storer.DeleteByUserID(ctx, params.UserID, params.DateDeleted)
+	if err != nil {
+		return fmt.Errorf("deletebyuserid: userID[%s]: %w", params.UserID, err)
+	}
+
+	for _, prd := range products {
+		if err := bus.publishEvent(ctx, events.TypeDeleted, prd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// actionUserRestored is executed by the user domain indirectly when a user is
+// restored. Only the products that were deleted along with the user are
+// restored.
+func (b *Business) actionUserRestored(ctx context.Context, claim delegate.Claim, params userbus.ActionRestoredParms) error {
+	b.log.Info(ctx, "action-userrestored", "user_id", params.UserID)
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	products, err := bus.storer.RestoreByUserID(ctx, params.UserID, params.DateDeleted, time.Now())
+	if err != nil {
+		return fmt.Errorf("restorebyuserid: userID[%s]: %w", params.UserID, err)
+	}
+
+	for _, prd := range products {
+		if err := bus.publishEvent(ctx, events.TypeRestored, prd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
 
-	// Now we can see if this user has been disabled. If they have been, we will
-	// want to disable to mark all these products as deleted. Right now we don't
-	// have support for this, but you can see how we can process the event.
+// publishEvent writes the public event for the change to the product. When
+// the business runs in a transaction, the event is only sent once it commits.
+func (b *Business) publishEvent(ctx context.Context, typ string, prd Product) error {
+	if b.delegate == nil {
+		return nil
+	}
+
+	if err := delegate.Publish(ctx, b.delegate, toProductEvent(typ, prd)); err != nil {
+		return fmt.Errorf("publish: %s event: productID[%s]: %w", typ, prd.ID, err)
+	}
 
 	return nil
 }
+
+func toProductEvent(typ string, prd Product) events.ProductEvent {
+	evt := events.ProductEvent{
+		Version:    events.Version,
+		ID:         uuid.NewString(),
+		Type:       typ,
+		ProductID:  prd.ID.String(),
+		OccurredAt: time.Now().UTC(),
+		Product: events.Product{
+			ID:          prd.ID.String(),
+			TenantID:    prd.TenantID.String(),
+			UserID:      prd.UserID.String(),
+			Name:        prd.Name.String(),
+			CostCents:   prd.Cost.Cents(),
+			Currency:    prd.Cost.Currency(),
+			Quantity:    prd.Quantity,
+			Status:      prd.Status.String(),
+			DateCreated: prd.DateCreated.UTC(),
+			DateUpdated: prd.DateUpdated.UTC(),
+		},
+	}
+
+	if !prd.DateDeleted.IsZero() {
+		dateDeleted := prd.DateDeleted.UTC()
+		evt.Product.DateDeleted = &dateDeleted
+	}
+
+	return evt
+}
@@ -1,14 +1,32 @@
 package productbus
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// When no Status is provided only active products are returned. Deleted
+// products are excluded unless Deleted is set to true, in which case only the
+// deleted products are returned. Name matches any product whose name
+// contains the value and NamePrefix any product whose name starts with the
+// value, both ignoring case. The Min and Max fields are inclusive and the
+// cost fields only compare the amount, not the currency.
 type QueryFilter struct {
-	ID       *uuid.UUID
-	Name     *Name
-	Cost     *float64
-	Quantity *int
+	ID               *uuid.UUID
+	IDs              []uuid.UUID
+	Name             *Name
+	NamePrefix       *Name
+	Cost             *Money
+	MinCost          *Money
+	MaxCost          *Money
+	Quantity         *int
+	MinQuantity      *int
+	MaxQuantity      *int
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+	Status           *Status
+	Deleted          *bool
 }
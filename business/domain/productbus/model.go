@@ -9,19 +9,23 @@ import (
 // Product represents an individual product.
 type Product struct {
 	ID          uuid.UUID
+	TenantID    uuid.UUID
 	UserID      uuid.UUID
 	Name        Name
-	Cost        float64
+	Cost        Money
 	Quantity    int
+	Status      Status
 	DateCreated time.Time
 	DateUpdated time.Time
+	DateDeleted time.Time
+	Version     int
 }
 
 // NewProduct is what we require from clients when adding a Product.
 type NewProduct struct {
 	UserID   uuid.UUID
 	Name     Name
-	Cost     float64
+	Cost     Money
 	Quantity int
 }
 
@@ -33,6 +37,14 @@ type NewProduct struct {
 // we make exceptions around marshalling/unmarshalling.
 type UpdateProduct struct {
 	Name     *Name
-	Cost     *float64
+	Cost     *Money
 	Quantity *int
 }
+
+// SearchResult represents a product matching a full text search along with
+// how well it matched and the matching part of its name highlighted.
+type SearchResult struct {
+	Product Product
+	Rank    float64
+	Snippet string
+}
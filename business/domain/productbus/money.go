@@ -0,0 +1,133 @@
+package productbus
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency used when none is provided.
+const DefaultCurrency = "USD"
+
+// maxCents is the largest amount that fits the NUMERIC(10, 2) cost column.
+const maxCents = 99_999_999_99
+
+// Money represents an amount of money in a currency. The amount is held in
+// cents so arithmetic on it is exact.
+type Money struct {
+	cents    int64
+	currency string
+}
+
+// NewMoney constructs a money value from an amount in cents with no checks.
+func NewMoney(cents int64, currency string) Money {
+	return Money{
+		cents:    cents,
+		currency: currency,
+	}
+}
+
+// String returns the amount as a decimal with two fractional digits.
+func (m Money) String() string {
+	cents := m.cents
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Currency returns the ISO-4217 code of the currency.
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// Cents returns the amount in cents.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Float64 returns the amount as a float for clients that expect a number.
+// It must not be used for arithmetic.
+func (m Money) Float64() float64 {
+	return float64(m.cents) / 100
+}
+
+// IsNegative reports whether the amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+// Mul returns the amount multiplied by n in the same currency.
+func (m Money) Mul(n int) Money {
+	return Money{
+		cents:    m.cents * int64(n),
+		currency: m.currency,
+	}
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (m Money) Equal(m2 Money) bool {
+	return m.cents == m2.cents && m.currency == m2.currency
+}
+
+// =============================================================================
+
+var (
+	amountRegEx   = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,2})?$`)
+	currencyRegEx = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// ParseMoney parses the decimal amount and ISO-4217 currency code and returns
+// money if the values comply with the rules for money. The amount can have
+// at most two fractional digits.
+func ParseMoney(amount string, currency string) (Money, error) {
+	if !currencyRegEx.MatchString(currency) {
+		return Money{}, fmt.Errorf("invalid currency %q", currency)
+	}
+
+	if !amountRegEx.MatchString(amount) {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	neg := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, frac, _ := strings.Cut(amount, ".")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || cents > maxCents {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	if neg {
+		cents = -cents
+	}
+
+	return Money{cents: cents, currency: currency}, nil
+}
+
+// MustParseMoney parses the decimal amount and ISO-4217 currency code and
+// returns money if the values comply with the rules for money. If an error
+// occurs the function panics.
+func MustParseMoney(amount string, currency string) Money {
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// ParseMoneyFloat parses the amount provided as a float, as clients of the
+// JSON API send it, and returns money in the currency.
+func ParseMoneyFloat(amount float64, currency string) (Money, error) {
+	return ParseMoney(strconv.FormatFloat(amount, 'f', -1, 64), currency)
+}
@@ -2,6 +2,7 @@ package productbus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -12,8 +13,10 @@ import (
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 func Test_Product(t *testing.T) {
@@ -36,13 +39,16 @@ func Test_Product(t *testing.T) {
 	unitest.Run(t, query(db.BusDomain, sd), "query")
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, reduce(db.BusDomain, sd), "reduce")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+	unitest.Run(t, cascade(db.BusDomain, sd), "cascade")
+	unitest.Run(t, restore(db.BusDomain, sd), "restore")
 }
 
 // =============================================================================
 
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := sqldb.WithSystem(context.Background())
 
 	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
 	if err != nil {
@@ -97,6 +103,16 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		return prds[i].ID.String() <= prds[j].ID.String()
 	})
 
+	ids := make([]uuid.UUID, len(prds))
+	var costPrds []productbus.Product
+	for i, prd := range prds {
+		ids[i] = prd.ID
+		if prd.Cost.Equal(prds[0].Cost) {
+			costPrds = append(costPrds, prd)
+		}
+	}
+	minQuantity := 1
+
 	table := []unitest.Table{
 		{
 			Name:    "all",
@@ -134,6 +150,90 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "range",
+			ExpResp: costPrds,
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					IDs:         ids,
+					NamePrefix:  dbtest.ProductNamePointer("name"),
+					MinCost:     &prds[0].Cost,
+					MaxCost:     &prds[0].Cost,
+					MinQuantity: &minQuantity,
+				}
+
+				resp, err := busDomain.Product.Query(ctx, filter, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]productbus.Product)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]productbus.Product)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name: "search",
+			ExpResp: []productbus.SearchResult{
+				{
+					Product: sd.Users[0].Products[0],
+					Snippet: fmt.Sprintf("<b>%s</b>", sd.Users[0].Products[0].Name),
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				text := sd.Users[0].Products[0].Name.String()
+
+				resp, err := busDomain.Product.Search(ctx, text, productbus.QueryFilter{}, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]productbus.SearchResult)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]productbus.SearchResult)
+
+				for i := range gotResp {
+					if gotResp[i].Rank <= 0 {
+						return fmt.Sprintf("expected a positive rank, got %v", gotResp[i].Rank)
+					}
+					expResp[i].Rank = gotResp[i].Rank
+
+					if gotResp[i].Product.DateCreated.Format(time.RFC3339) == expResp[i].Product.DateCreated.Format(time.RFC3339) {
+						expResp[i].Product.DateCreated = gotResp[i].Product.DateCreated
+					}
+
+					if gotResp[i].Product.DateUpdated.Format(time.RFC3339) == expResp[i].Product.DateUpdated.Format(time.RFC3339) {
+						expResp[i].Product.DateUpdated = gotResp[i].Product.DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
 		{
 			Name:    "byid",
 			ExpResp: sd.Users[0].Products[0],
@@ -174,20 +274,23 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: productbus.Product{
+				TenantID: sd.Users[0].TenantID,
 				UserID:   sd.Users[0].ID,
 				Name:     productbus.MustParseName("Guitar"),
-				Cost:     10.34,
+				Cost:     productbus.MustParseMoney("10.34", productbus.DefaultCurrency),
 				Quantity: 10,
+				Status:   productbus.Statuses.Active,
+				Version:  1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				np := productbus.NewProduct{
 					UserID:   sd.Users[0].ID,
 					Name:     productbus.MustParseName("Guitar"),
-					Cost:     10.34,
+					Cost:     productbus.MustParseMoney("10.34", productbus.DefaultCurrency),
 					Quantity: 10,
 				}
 
-				resp, err := busDomain.Product.Create(ctx, np)
+				resp, err := busDomain.Product.Create(ctx, sd.Users[0].ID, np)
 				if err != nil {
 					return err
 				}
@@ -220,21 +323,24 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name: "basic",
 			ExpResp: productbus.Product{
 				ID:          sd.Users[0].Products[0].ID,
+				TenantID:    sd.Users[0].TenantID,
 				UserID:      sd.Users[0].ID,
 				Name:        productbus.MustParseName("Guitar"),
-				Cost:        10.34,
+				Cost:        productbus.MustParseMoney("10.34", productbus.DefaultCurrency),
 				Quantity:    10,
+				Status:      productbus.Statuses.Active,
 				DateCreated: sd.Users[0].Products[0].DateCreated,
 				DateUpdated: sd.Users[0].Products[0].DateCreated,
+				Version:     2,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				up := productbus.UpdateProduct{
 					Name:     dbtest.ProductNamePointer("Guitar"),
-					Cost:     dbtest.FloatPointer(10.34),
+					Cost:     dbtest.MoneyPointer("10.34", productbus.DefaultCurrency),
 					Quantity: dbtest.IntPointer(10),
 				}
 
-				resp, err := busDomain.Product.Update(ctx, sd.Users[0].Products[0], up)
+				resp, err := busDomain.Product.Update(ctx, sd.Users[0].ID, sd.Users[0].Products[0], up)
 				if err != nil {
 					return err
 				}
@@ -254,6 +360,111 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "stale",
+			ExpResp: productbus.ErrVersionConflict,
+			ExcFunc: func(ctx context.Context) any {
+				up := productbus.UpdateProduct{
+					Name: dbtest.ProductNamePointer("Stale"),
+				}
+
+				_, err := busDomain.Product.Update(ctx, sd.Users[0].ID, sd.Users[0].Products[0], up)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func reduce(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	var prd productbus.Product
+
+	table := []unitest.Table{
+		{
+			Name:    "stale",
+			ExpResp: []any{8, true},
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.TestGenerateNewProducts(1, sd.Admins[0].ID)[0]
+				np.Quantity = 10
+
+				var err error
+				prd, err = busDomain.Product.Create(ctx, sd.Admins[0].ID, np)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Product.ReduceQuantity(ctx, prd, 1); err != nil {
+					return err
+				}
+
+				// The copy of the product is stale by now, but the version
+				// returned is the one of the row.
+				resp, err := busDomain.Product.ReduceQuantity(ctx, prd, 1)
+				if err != nil {
+					return err
+				}
+
+				dbPrd, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return []any{resp.Quantity, resp.Version == dbPrd.Version}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "insufficient",
+			ExpResp: productbus.ErrInsufficientStock,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Product.ReduceQuantity(ctx, prd, 100)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "deleted",
+			ExpResp: productbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				dbPrd, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Product.Delete(ctx, sd.Admins[0].ID, dbPrd); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Product.ReduceQuantity(ctx, prd, 1)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -265,7 +476,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "user",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.Product.Delete(ctx, sd.Users[0].Products[1]); err != nil {
+				if err := busDomain.Product.Delete(ctx, sd.Users[0].ID, sd.Users[0].Products[1]); err != nil {
 					return err
 				}
 
@@ -279,7 +490,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "admin",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.Product.Delete(ctx, sd.Admins[0].Products[1]); err != nil {
+				if err := busDomain.Product.Delete(ctx, sd.Admins[0].ID, sd.Admins[0].Products[1]); err != nil {
 					return err
 				}
 
@@ -293,3 +504,288 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func cascade(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Users[0].Products[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "disable",
+			ExpResp: productbus.Statuses.Inactive,
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(false),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp.Status
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "hidden",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					ID: &prd.ID,
+				}
+
+				resp, err := busDomain.Product.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "inactive",
+			ExpResp: productbus.ErrInactive,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Product.ReduceQuantity(ctx, prd, 1)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "enable",
+			ExpResp: productbus.Statuses.Active,
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(true),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp.Status
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "restored",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					ID: &prd.ID,
+				}
+
+				resp, err := busDomain.Product.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restore(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	prd := sd.Users[0].Products[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "hidden",
+			ExpResp: productbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "deleted",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := productbus.QueryFilter{
+					ID:      &prd.ID,
+					Deleted: dbtest.BoolPointer(true),
+				}
+
+				resp, err := busDomain.Product.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "basic",
+			ExpResp: prd,
+			ExcFunc: func(ctx context.Context) any {
+				dltPrd, err := busDomain.Product.QueryDeletedByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Product.Restore(ctx, sd.Users[0].ID, dltPrd); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(productbus.Product)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(productbus.Product)
+
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Version = gotResp.Version
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "user-deleted",
+			ExpResp: productbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Users[0].User); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Product.QueryByID(ctx, prd.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "owner-deleted",
+			ExpResp: userbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				dltPrd, err := busDomain.Product.QueryDeletedByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Product.Restore(ctx, sd.Users[0].ID, dltPrd)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "user-restored",
+			ExpResp: 3,
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := busDomain.User.QueryDeletedByID(ctx, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Restore(ctx, sd.Admins[0].ID, usr); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Product.QueryByUserID(ctx, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Product.Purge(ctx, 0)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purged",
+			ExpResp: productbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Product.QueryDeletedByID(ctx, sd.Admins[0].Products[1].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/auditbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -18,9 +20,13 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound     = errors.New("product not found")
-	ErrUserDisabled = errors.New("user disabled")
-	ErrInvalidCost  = errors.New("cost not valid")
+	ErrNotFound          = errors.New("product not found")
+	ErrUserDisabled      = errors.New("user disabled")
+	ErrInvalidCost       = errors.New("cost not valid")
+	ErrInvalidQuantity   = errors.New("quantity not valid")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInactive          = errors.New("product is not active")
+	ErrVersionConflict   = errors.New("product was modified by another request")
 )
 
 // Storer interface declares the behavior this package needs to perists and
@@ -30,25 +36,36 @@ type Storer interface {
 	Create(ctx context.Context, prd Product) error
 	Update(ctx context.Context, prd Product) error
 	Delete(ctx context.Context, prd Product) error
+	Restore(ctx context.Context, prd Product) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time) ([]Product, error)
+	RestoreByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time, dateUpdated time.Time) ([]Product, error)
+	ReduceQuantity(ctx context.Context, prd Product, quantity int) (Product, error)
+	UpdateStatusByUserID(ctx context.Context, userID uuid.UUID, status Status, dateUpdated time.Time) ([]Product, error)
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
+	Search(ctx context.Context, text string, filter QueryFilter, page page.Page) ([]SearchResult, error)
+	SearchCount(ctx context.Context, text string, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
+	QueryDeletedByID(ctx context.Context, productID uuid.UUID) (Product, error)
 }
 
 // Business manages the set of APIs for product access.
 type Business struct {
 	log      *logger.Logger
 	userBus  *userbus.Business
+	auditBus *auditbus.Business
 	delegate *delegate.Delegate
 	storer   Storer
 }
 
 // NewBusiness constructs a product business API for use.
-func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, auditBus *auditbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
 	b := Business{
 		log:      log,
 		userBus:  userBus,
+		auditBus: auditBus,
 		delegate: delegate,
 		storer:   storer,
 	}
@@ -71,10 +88,26 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	auditBus := b.auditBus
+	if auditBus != nil {
+		auditBus, err = auditBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Events are published as part of the transaction so consumers only
+	// hear about changes that were committed.
+	delegate := b.delegate
+	if delegate != nil {
+		delegate = delegate.NewWithTx(tx)
+	}
+
 	bus := Business{
 		log:      b.log,
 		userBus:  userBus,
-		delegate: b.delegate,
+		auditBus: auditBus,
+		delegate: delegate,
 		storer:   storer,
 	}
 
@@ -82,13 +115,13 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 }
 
 // Create adds a new product to the system.
-func (b *Business) Create(ctx context.Context, np NewProduct) (Product, error) {
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, np NewProduct) (Product, error) {
 	usr, err := b.userBus.QueryByID(ctx, np.UserID)
 	if err != nil {
 		return Product{}, fmt.Errorf("user.querybyid: %s: %w", np.UserID, err)
 	}
 
-	if np.Cost < 0 {
+	if np.Cost.IsNegative() {
 		return Product{}, ErrInvalidCost
 	}
 
@@ -100,28 +133,45 @@ func (b *Business) Create(ctx context.Context, np NewProduct) (Product, error) {
 
 	prd := Product{
 		ID:          uuid.New(),
+		TenantID:    usr.TenantID,
 		Name:        np.Name,
 		Cost:        np.Cost,
 		Quantity:    np.Quantity,
+		Status:      Statuses.Active,
 		UserID:      np.UserID,
 		DateCreated: now,
 		DateUpdated: now,
+		Version:     1,
 	}
 
 	if err := b.storer.Create(ctx, prd); err != nil {
 		return Product{}, fmt.Errorf("create: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Create, prd.ID, nil, toAuditProduct(prd)); err != nil {
+		return Product{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeCreated, prd); err != nil {
+		return Product{}, err
+	}
+
 	return prd, nil
 }
 
-// Update modifies information about a product.
-func (b *Business) Update(ctx context.Context, prd Product, up UpdateProduct) (Product, error) {
+// Update modifies information about a product. The product must still be
+// at the version it was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Update(ctx context.Context, actorID uuid.UUID, prd Product, up UpdateProduct) (Product, error) {
+	before := toAuditProduct(prd)
+
 	if up.Name != nil {
 		prd.Name = *up.Name
 	}
 
 	if up.Cost != nil {
+		if up.Cost.IsNegative() {
+			return Product{}, ErrInvalidCost
+		}
 		prd.Cost = *up.Cost
 	}
 
@@ -130,23 +180,113 @@ func (b *Business) Update(ctx context.Context, prd Product, up UpdateProduct) (P
 	}
 
 	prd.DateUpdated = time.Now()
+	prd.Version++
 
 	if err := b.storer.Update(ctx, prd); err != nil {
 		return Product{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Update, prd.ID, before, toAuditProduct(prd)); err != nil {
+		return Product{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeUpdated, prd); err != nil {
+		return Product{}, err
+	}
+
 	return prd, nil
 }
 
-// Delete removes the specified product.
-func (b *Business) Delete(ctx context.Context, prd Product) error {
+// Delete marks the specified product as deleted. The product can be brought
+// back with Restore until it is purged. The product must still be at the
+// version it was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Delete(ctx context.Context, actorID uuid.UUID, prd Product) error {
+	before := toAuditProduct(prd)
+
+	prd.DateDeleted = time.Now()
+	prd.Version++
+
 	if err := b.storer.Delete(ctx, prd); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Delete, prd.ID, before, toAuditProduct(prd)); err != nil {
+		return err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeDeleted, prd); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Restore brings back the specified deleted product. The product can't be
+// restored while the user that owns it is deleted.
+func (b *Business) Restore(ctx context.Context, actorID uuid.UUID, prd Product) (Product, error) {
+	if _, err := b.userBus.QueryByID(ctx, prd.UserID); err != nil {
+		return Product{}, fmt.Errorf("user.querybyid: %s: %w", prd.UserID, err)
+	}
+
+	before := toAuditProduct(prd)
+
+	prd.DateDeleted = time.Time{}
+	prd.DateUpdated = time.Now()
+	prd.Version++
+
+	if err := b.storer.Restore(ctx, prd); err != nil {
+		return Product{}, fmt.Errorf("restore: %w", err)
+	}
+
+	if err := b.audit(ctx, actorID, auditbus.Actions.Restore, prd.ID, before, toAuditProduct(prd)); err != nil {
+		return Product{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeRestored, prd); err != nil {
+		return Product{}, err
+	}
+
+	return prd, nil
+}
+
+// Purge permanently removes the products that were deleted longer ago than
+// the specified retention window. It returns the number of products removed.
+func (b *Business) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	deletedBefore := time.Now().Add(-retention)
+
+	n, err := b.storer.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: deletedBefore[%s]: %w", deletedBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
+// ReduceQuantity removes the specified quantity from the product's stock. The
+// reduction is performed atomically by the store. ErrNotFound is returned if
+// the product is deleted, ErrInactive if it isn't active and
+// ErrInsufficientStock if it doesn't have enough stock to cover the request.
+// The returned product is read from the row the store updated, so only the
+// id of the product passed in is used.
+func (b *Business) ReduceQuantity(ctx context.Context, prd Product, quantity int) (Product, error) {
+	if quantity <= 0 {
+		return Product{}, ErrInvalidQuantity
+	}
+
+	prd.DateUpdated = time.Now()
+
+	reduced, err := b.storer.ReduceQuantity(ctx, prd, quantity)
+	if err != nil {
+		return Product{}, fmt.Errorf("reducequantity: productID[%s] quantity[%d]: %w", prd.ID, quantity, err)
+	}
+
+	if err := b.publishEvent(ctx, events.TypeUpdated, reduced); err != nil {
+		return Product{}, err
+	}
+
+	return reduced, nil
+}
+
 // Query retrieves a list of existing products.
 func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error) {
 	prds, err := b.storer.Query(ctx, filter, orderBy, page)
@@ -162,6 +302,23 @@ func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
+// Search retrieves the products matching the full text search, best matches
+// first.
+func (b *Business) Search(ctx context.Context, text string, filter QueryFilter, page page.Page) ([]SearchResult, error) {
+	results, err := b.storer.Search(ctx, text, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	return results, nil
+}
+
+// SearchCount returns the total number of products matching the full text
+// search.
+func (b *Business) SearchCount(ctx context.Context, text string, filter QueryFilter) (int, error) {
+	return b.storer.SearchCount(ctx, text, filter)
+}
+
 // QueryByID finds the product by the specified Ib.
 func (b *Business) QueryByID(ctx context.Context, productID uuid.UUID) (Product, error) {
 	prd, err := b.storer.QueryByID(ctx, productID)
@@ -181,3 +338,13 @@ func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Produ
 
 	return prds, nil
 }
+
+// QueryDeletedByID finds the deleted product by the specified ID.
+func (b *Business) QueryDeletedByID(ctx context.Context, productID uuid.UUID) (Product, error) {
+	prd, err := b.storer.QueryDeletedByID(ctx, productID)
+	if err != nil {
+		return Product{}, fmt.Errorf("query: productID[%s]: %w", productID, err)
+	}
+
+	return prd, nil
+}
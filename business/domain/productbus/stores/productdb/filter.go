@@ -6,31 +6,84 @@ import (
 	"strings"
 
 	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter productbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(filter productbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
 	if filter.ID != nil {
 		data["product_id"] = *filter.ID
 		wc = append(wc, "product_id = :product_id")
 	}
 
+	if len(filter.IDs) > 0 {
+		data["product_ids"] = filter.IDs
+		wc = append(wc, "product_id IN (:product_ids)")
+	}
+
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		data["name"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(filter.Name.String()))
+		wc = append(wc, "name ILIKE :name")
+	}
+
+	if filter.NamePrefix != nil {
+		data["name_prefix"] = fmt.Sprintf("%s%%", sqldb.EscapeLike(filter.NamePrefix.String()))
+		wc = append(wc, "name ILIKE :name_prefix")
 	}
 
 	if filter.Cost != nil {
-		data["cost"] = *filter.Cost
+		data["cost"] = filter.Cost.String()
 		wc = append(wc, "cost = :cost")
 	}
 
+	if filter.MinCost != nil {
+		data["min_cost"] = filter.MinCost.String()
+		wc = append(wc, "cost >= :min_cost")
+	}
+
+	if filter.MaxCost != nil {
+		data["max_cost"] = filter.MaxCost.String()
+		wc = append(wc, "cost <= :max_cost")
+	}
+
 	if filter.Quantity != nil {
 		data["quantity"] = *filter.Quantity
 		wc = append(wc, "quantity = :quantity")
 	}
 
+	if filter.MinQuantity != nil {
+		data["min_quantity"] = *filter.MinQuantity
+		wc = append(wc, "quantity >= :min_quantity")
+	}
+
+	if filter.MaxQuantity != nil {
+		data["max_quantity"] = *filter.MaxQuantity
+		wc = append(wc, "quantity <= :max_quantity")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	status := productbus.Statuses.Active
+	if filter.Status != nil {
+		status = *filter.Status
+	}
+	data["status"] = status.String()
+	wc = append(wc, "status = :status")
+
+	switch {
+	case filter.Deleted != nil && *filter.Deleted:
+		wc = append(wc, "date_deleted IS NOT NULL")
+	default:
+		wc = append(wc, "date_deleted IS NULL")
+	}
+
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
 		buf.WriteString(strings.Join(wc, " AND "))
@@ -1,6 +1,7 @@
 package productdb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -9,24 +10,37 @@ import (
 )
 
 type product struct {
-	ID          uuid.UUID `db:"product_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Name        string    `db:"name"`
-	Cost        float64   `db:"cost"`
-	Quantity    int       `db:"quantity"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID          uuid.UUID    `db:"product_id"`
+	TenantID    uuid.UUID    `db:"tenant_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Name        string       `db:"name"`
+	Cost        string       `db:"cost"`
+	Currency    string       `db:"currency"`
+	Quantity    int          `db:"quantity"`
+	Status      string       `db:"status"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUpdated time.Time    `db:"date_updated"`
+	DateDeleted sql.NullTime `db:"date_deleted"`
+	Version     int          `db:"version"`
 }
 
 func toDBProduct(bus productbus.Product) product {
 	db := product{
 		ID:          bus.ID,
+		TenantID:    bus.TenantID,
 		UserID:      bus.UserID,
 		Name:        bus.Name.String(),
-		Cost:        bus.Cost,
+		Cost:        bus.Cost.String(),
+		Currency:    bus.Cost.Currency(),
 		Quantity:    bus.Quantity,
+		Status:      bus.Status.String(),
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  bus.DateDeleted.UTC(),
+			Valid: !bus.DateDeleted.IsZero(),
+		},
+		Version: bus.Version,
 	}
 
 	return db
@@ -38,14 +52,31 @@ func toBusProduct(db product) (productbus.Product, error) {
 		return productbus.Product{}, fmt.Errorf("parse name: %w", err)
 	}
 
+	status, err := productbus.ParseStatus(db.Status)
+	if err != nil {
+		return productbus.Product{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	cost, err := productbus.ParseMoney(db.Cost, db.Currency)
+	if err != nil {
+		return productbus.Product{}, fmt.Errorf("parse cost: %w", err)
+	}
+
 	bus := productbus.Product{
 		ID:          db.ID,
+		TenantID:    db.TenantID,
 		UserID:      db.UserID,
 		Name:        name,
-		Cost:        db.Cost,
+		Cost:        cost,
 		Quantity:    db.Quantity,
+		Status:      status,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
+		Version:     db.Version,
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
@@ -64,3 +95,28 @@ func toBusProducts(dbs []product) ([]productbus.Product, error) {
 
 	return bus, nil
 }
+
+type searchResult struct {
+	product
+	Rank    float64 `db:"rank"`
+	Snippet string  `db:"snippet"`
+}
+
+func toBusSearchResults(dbs []searchResult) ([]productbus.SearchResult, error) {
+	bus := make([]productbus.SearchResult, len(dbs))
+
+	for i, db := range dbs {
+		prd, err := toBusProduct(db.product)
+		if err != nil {
+			return nil, err
+		}
+
+		bus[i] = productbus.SearchResult{
+			Product: prd,
+			Rank:    db.Rank,
+			Snippet: db.Snippet,
+		}
+	}
+
+	return bus, nil
+}
@@ -1,10 +1,10 @@
 package productdb
 
 import (
-	"fmt"
-
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 var orderByFields = map[string]string{
@@ -16,10 +16,12 @@ var orderByFields = map[string]string{
 }
 
 func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
-	}
+	return sqldb.OrderByClause(orderBy, orderByFields, "product_id")
+}
 
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+// cursorClause returns the condition that resumes the query after the row the
+// page cursor points at. No condition is returned when the page isn't
+// addressed by a cursor.
+func cursorClause(orderBy order.By, page page.Page, data map[string]any) ([]string, error) {
+	return sqldb.CursorClause(orderBy, page, orderByFields, "product_id", data)
 }
@@ -4,8 +4,10 @@ package productdb
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
@@ -51,9 +53,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (productbus.Storer, error)
 func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
 	const q = `
 	INSERT INTO products
-		(product_id, user_id, name, cost, quantity, date_created, date_updated)
+		(product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, version)
 	VALUES
-		(:product_id, :user_id, :name, :cost, :quantity, :date_created, :date_updated)`
+		(:product_id, :tenant_id, :user_id, :name, :cost, :currency, :quantity, :status, :date_created, :date_updated, :version)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -62,8 +64,9 @@ func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
 	return nil
 }
 
-// Update modifies data about a productbus. It will error if the specified ID is
-// invalid or does not reference an existing productbus.
+// Update modifies data about a productbus. The product carries the version
+// being written and the update only happens if the stored product is at the
+// version before it, otherwise ErrVersionConflict is returned.
 func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	const q = `
 	UPDATE
@@ -71,10 +74,71 @@ func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	SET
 		"name" = :name,
 		"cost" = :cost,
+		"currency" = :currency,
 		"quantity" = :quantity,
-		"date_updated" = :date_updated
+		"date_updated" = :date_updated,
+		"version" = :version
 	WHERE
-		product_id = :product_id`
+		product_id = :product_id AND
+		version = :version - 1
+	RETURNING
+		version`
+
+	var dbVer struct {
+		Version int `db:"version"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBProduct(prd), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", productbus.ErrVersionConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// Delete marks the product identified by a given ID as deleted. The product
+// is only deleted if the stored product is at the version before the one
+// specified, otherwise ErrVersionConflict is returned.
+func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
+	const q = `
+	UPDATE
+		products
+	SET
+		"date_deleted" = :date_deleted,
+		"version" = :version
+	WHERE
+		product_id = :product_id AND
+		version = :version - 1 AND
+		date_deleted IS NULL
+	RETURNING
+		version`
+
+	var dbVer struct {
+		Version int `db:"version"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBProduct(prd), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", productbus.ErrVersionConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// Restore clears the deleted mark from the product identified by a given ID.
+func (s *Store) Restore(ctx context.Context, prd productbus.Product) error {
+	const q = `
+	UPDATE
+		products
+	SET
+		"date_deleted" = NULL,
+		"date_updated" = :date_updated,
+		"version" = version + 1
+	WHERE
+		product_id = :product_id AND
+		date_deleted IS NOT NULL`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -83,25 +147,221 @@ func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
 	return nil
 }
 
-// Delete removes the product identified by a given ID.
-func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
+// Purge removes the products that were deleted before the specified time
+// from the database. Products on an order are kept so the order stays whole.
+// It returns the number of products removed.
+func (s *Store) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	data := struct {
+		DeletedBefore time.Time `db:"deleted_before"`
+	}{
+		DeletedBefore: deletedBefore.UTC(),
+	}
+
+	const q = `
+	WITH purged AS (
+		DELETE FROM
+			products AS p
+		WHERE
+			p.date_deleted < :deleted_before AND
+			NOT EXISTS (SELECT 1 FROM order_items AS oi WHERE oi.product_id = p.product_id)
+		RETURNING
+			product_id
+	)
+	SELECT
+		count(1)
+	FROM
+		purged`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// DeleteByUserID marks all the products that belong to the specified user
+// as deleted. The products that were deleted are returned.
+func (s *Store) DeleteByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time) ([]productbus.Product, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateDeleted time.Time `db:"date_deleted"`
+	}{
+		UserID:      userID.String(),
+		DateDeleted: dateDeleted.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"date_deleted" = :date_deleted,
+		"version" = version + 1
+	WHERE
+		user_id = :user_id AND
+		date_deleted IS NULL
+	RETURNING
+		product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version`
+
+	var dbPrds []product
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusProducts(dbPrds)
+}
+
+// RestoreByUserID clears the deleted mark from the products that belong to
+// the specified user and were deleted at the specified time. The products
+// that were restored are returned.
+func (s *Store) RestoreByUserID(ctx context.Context, userID uuid.UUID, dateDeleted time.Time, dateUpdated time.Time) ([]productbus.Product, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateDeleted time.Time `db:"date_deleted"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		DateDeleted: dateDeleted.UTC(),
+		DateUpdated: dateUpdated.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"date_deleted" = NULL,
+		"date_updated" = :date_updated,
+		"version" = version + 1
+	WHERE
+		user_id = :user_id AND
+		date_deleted = :date_deleted
+	RETURNING
+		product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version`
+
+	var dbPrds []product
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusProducts(dbPrds)
+}
+
+// ReduceQuantity subtracts the specified quantity from the product's stock
+// as long as the product isn't deleted, is active and enough stock exists.
+// Otherwise ErrNotFound, ErrInactive or ErrInsufficientStock is returned.
+// The product is returned as the update left the row.
+func (s *Store) ReduceQuantity(ctx context.Context, prd productbus.Product, quantity int) (productbus.Product, error) {
+	data := struct {
+		ID          string    `db:"product_id"`
+		Quantity    int       `db:"quantity"`
+		Status      string    `db:"status"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		ID:          prd.ID.String(),
+		Quantity:    quantity,
+		Status:      productbus.Statuses.Active.String(),
+		DateUpdated: prd.DateUpdated.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"quantity" = quantity - :quantity,
+		"date_updated" = :date_updated,
+		"version" = version + 1
+	WHERE
+		product_id = :product_id AND
+		date_deleted IS NULL AND
+		status = :status AND
+		quantity >= :quantity
+	RETURNING
+		product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version`
+
+	var dbPrd product
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return productbus.Product{}, s.reduceQuantityErr(ctx, prd.ID)
+		}
+		return productbus.Product{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusProduct(dbPrd)
+}
+
+// reduceQuantityErr reads the product again to tell a deleted or inactive
+// product apart from one that doesn't have enough stock.
+func (s *Store) reduceQuantityErr(ctx context.Context, productID uuid.UUID) error {
 	data := struct {
 		ID string `db:"product_id"`
 	}{
-		ID: prd.ID.String(),
+		ID: productID.String(),
 	}
 
 	const q = `
-	DELETE FROM
+	SELECT
+		status, date_deleted
+	FROM
 		products
 	WHERE
 		product_id = :product_id`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	var dbPrd struct {
+		Status      string       `db:"status"`
+		DateDeleted sql.NullTime `db:"date_deleted"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", productbus.ErrNotFound)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 
-	return nil
+	switch {
+	case dbPrd.DateDeleted.Valid:
+		return fmt.Errorf("db: %w", productbus.ErrNotFound)
+
+	case dbPrd.Status != productbus.Statuses.Active.String():
+		return fmt.Errorf("db: %w", productbus.ErrInactive)
+	}
+
+	return fmt.Errorf("db: %w", productbus.ErrInsufficientStock)
+}
+
+// UpdateStatusByUserID sets the status for all the products that belong to
+// the specified user. The products whose status changed are returned.
+func (s *Store) UpdateStatusByUserID(ctx context.Context, userID uuid.UUID, status productbus.Status, dateUpdated time.Time) ([]productbus.Product, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		Status      string    `db:"status"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		Status:      status.String(),
+		DateUpdated: dateUpdated.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"status" = :status,
+		"date_updated" = :date_updated,
+		"version" = version + 1
+	WHERE
+		user_id = :user_id AND
+		status != :status
+	RETURNING
+		product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version`
+
+	var dbPrds []product
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusProducts(dbPrds)
 }
 
 // Query gets all Products from the database.
@@ -113,12 +373,17 @@ func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, orderB
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version
 	FROM
 		products`
 
+	cursorClause, err := cursorClause(orderBy, page, data)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	s.applyFilter(filter, data, buf, cursorClause...)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -129,13 +394,71 @@ func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, orderB
 	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
 
 	var dbPrds []product
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
-		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
 	}
 
 	return toBusProducts(dbPrds)
 }
 
+// Search retrieves a list of existing products matching the full text search
+// ordered by how well they match. The snippet highlights the matching words
+// in the product name.
+func (s *Store) Search(ctx context.Context, text string, filter productbus.QueryFilter, page page.Page) ([]productbus.SearchResult, error) {
+	data := map[string]any{
+		"search":        text,
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+	    product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version,
+		ts_rank(search, websearch_to_tsquery('english', :search)) AS rank,
+		ts_headline('english', name, websearch_to_tsquery('english', :search)) AS snippet
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf, "search @@ websearch_to_tsquery('english', :search)")
+
+	buf.WriteString(" ORDER BY rank DESC, product_id ASC")
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbResults []searchResult
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, buf.String(), data, &dbResults); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
+	}
+
+	return toBusSearchResults(dbResults)
+}
+
+// SearchCount returns the total number of products matching the full text
+// search.
+func (s *Store) SearchCount(ctx context.Context, text string, filter productbus.QueryFilter) (int, error) {
+	data := map[string]any{
+		"search": text,
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf, "search @@ websearch_to_tsquery('english', :search)")
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStructUsingIn(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
 // Count returns the total number of users in the DB.
 func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
 	data := map[string]any{}
@@ -154,7 +477,7 @@ func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int,
 		Sold    int `db:"sold"`
 		Revenue int `db:"revenue"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStructUsingIn(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
@@ -171,11 +494,12 @@ func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version
 	FROM
 		products
 	WHERE
-		product_id = :product_id`
+		product_id = :product_id AND
+		date_deleted IS NULL`
 
 	var dbPrd product
 	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
@@ -198,11 +522,12 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]productb
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version
 	FROM
 		products
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND
+		date_deleted IS NULL`
 
 	var dbPrds []product
 	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
@@ -211,3 +536,31 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]productb
 
 	return toBusProducts(dbPrds)
 }
+
+// QueryDeletedByID finds the deleted product identified by a given ID.
+func (s *Store) QueryDeletedByID(ctx context.Context, productID uuid.UUID) (productbus.Product, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+	    product_id, tenant_id, user_id, name, cost, currency, quantity, status, date_created, date_updated, date_deleted, version
+	FROM
+		products
+	WHERE
+		product_id = :product_id AND
+		date_deleted IS NOT NULL`
+
+	var dbPrd product
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return productbus.Product{}, fmt.Errorf("db: %w", productbus.ErrNotFound)
+		}
+		return productbus.Product{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusProduct(dbPrd)
+}
@@ -18,8 +18,8 @@ func TestGenerateNewProducts(n int, userID uuid.UUID) []NewProduct {
 
 		np := NewProduct{
 			Name:     MustParseName(fmt.Sprintf("Name%d", idx)),
-			Cost:     float64(rand.Intn(500)),
-			Quantity: rand.Intn(50),
+			Cost:     NewMoney(int64(rand.Intn(500))*100, DefaultCurrency),
+			Quantity: rand.Intn(50) + 1,
 			UserID:   userID,
 		}
 
@@ -35,7 +35,7 @@ func TestGenerateSeedProducts(ctx context.Context, n int, api *Business, userID
 
 	prds := make([]Product, len(newPrds))
 	for i, np := range newPrds {
-		prd, err := api.Create(ctx, np)
+		prd, err := api.Create(ctx, userID, np)
 		if err != nil {
 			return nil, fmt.Errorf("seeding product: idx: %d : %w", i, err)
 		}
@@ -0,0 +1,30 @@
+package rolebus
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+)
+
+// Role represents information about an individual role and the permissions
+// it has been granted.
+type Role struct {
+	Name        userbus.Role
+	Permissions []Permission
+	DateCreated time.Time
+}
+
+// NewRole contains information needed to create a new role.
+type NewRole struct {
+	Name        userbus.Role
+	Permissions []Permission
+}
+
+// RulePermissions represents the permissions that satisfy a rule of the
+// authorization policy. The subject permissions only satisfy the rule when
+// users act on their own data.
+type RulePermissions struct {
+	Rule        string
+	Permissions []Permission
+	Subject     []Permission
+}
@@ -0,0 +1,90 @@
+package rolebus
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type permissionSet struct {
+	Admin Permission
+	User  Permission
+}
+
+// Permissions represents the built-in permissions. Other permissions can be
+// granted to roles and mapped to the rules of the authorization policy.
+var Permissions = permissionSet{
+	Admin: newPermission("admin"),
+	User:  newPermission("user"),
+}
+
+// =============================================================================
+
+// Permission represents a permission in the system.
+type Permission struct {
+	name string
+}
+
+func newPermission(permission string) Permission {
+	return Permission{permission}
+}
+
+// String returns the name of the permission.
+func (p Permission) String() string {
+	return p.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (p Permission) Equal(p2 Permission) bool {
+	return p.name == p2.name
+}
+
+// =============================================================================
+
+var permissionRegEx = regexp.MustCompile("^[a-z][a-z_]{1,31}$")
+
+// ParsePermission parses the string value and returns a permission if the
+// value complies with the rules for a permission name.
+func ParsePermission(value string) (Permission, error) {
+	if !permissionRegEx.MatchString(value) {
+		return Permission{}, fmt.Errorf("invalid permission %q", value)
+	}
+
+	return Permission{value}, nil
+}
+
+// MustParsePermission parses the string value and returns a permission. If an
+// error occurs the function panics.
+func MustParsePermission(value string) Permission {
+	permission, err := ParsePermission(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return permission
+}
+
+// ParsePermissionsToString takes a collection of permissions and converts them
+// to a slice of string.
+func ParsePermissionsToString(perms []Permission) []string {
+	values := make([]string, len(perms))
+	for i, perm := range perms {
+		values[i] = perm.String()
+	}
+
+	return values
+}
+
+// ParsePermissions takes a collection of strings and converts them to a slice
+// of permissions.
+func ParsePermissions(values []string) ([]Permission, error) {
+	perms := make([]Permission, len(values))
+	for i, value := range values {
+		perm, err := ParsePermission(value)
+		if err != nil {
+			return nil, err
+		}
+		perms[i] = perm
+	}
+
+	return perms, nil
+}
@@ -0,0 +1,255 @@
+package rolebus_test
+
+import (
+	"context"
+	"testing"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Role(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain), "query")
+	unitest.Run(t, create(db.BusDomain), "create")
+	unitest.Run(t, grant(db.BusDomain), "grant")
+	unitest.Run(t, permissions(db.BusDomain), "permissions")
+	unitest.Run(t, rules(db.BusDomain), "rules")
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "builtin",
+			ExpResp: []rolebus.Role{
+				{
+					Name:        userbus.Roles.Admin,
+					Permissions: []rolebus.Permission{rolebus.Permissions.Admin},
+				},
+				{
+					Name:        userbus.Roles.User,
+					Permissions: []rolebus.Permission{rolebus.Permissions.User},
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Role.Query(ctx, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]rolebus.Role)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]rolebus.Role)
+
+				for i := range gotResp {
+					if i < len(expResp) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func create(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: rolebus.Role{
+				Name:        userbus.MustParseRole("MANAGER"),
+				Permissions: []rolebus.Permission{rolebus.Permissions.User},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				name, err := userbus.ParseRole("MANAGER")
+				if err != nil {
+					return err
+				}
+
+				nr := rolebus.NewRole{
+					Name:        name,
+					Permissions: []rolebus.Permission{rolebus.Permissions.User},
+				}
+
+				resp, err := busDomain.Role.Create(ctx, nr)
+				if err != nil {
+					return err
+				}
+
+				if _, err := userbus.ParseRole("MANAGER"); err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(rolebus.Role)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(rolebus.Role)
+				expResp.DateCreated = gotResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func grant(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: []rolebus.Permission{
+				rolebus.Permissions.Admin,
+				rolebus.Permissions.User,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				role, err := busDomain.Role.QueryByName(ctx, userbus.MustParseRole("MANAGER"))
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Role.Grant(ctx, role, rolebus.Permissions.Admin); err != nil {
+					return err
+				}
+
+				role, err = busDomain.Role.QueryByName(ctx, role.Name)
+				if err != nil {
+					return err
+				}
+
+				return role.Permissions
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func permissions(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: []rolebus.Permission{
+				rolebus.Permissions.Admin,
+				rolebus.Permissions.User,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				roles := []userbus.Role{userbus.Roles.Admin, userbus.Roles.User}
+
+				resp, err := busDomain.Role.QueryPermissions(ctx, roles)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func rules(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "builtin",
+			ExpResp: rolebus.RulePermissions{
+				Rule:        "rule_admin_or_subject",
+				Permissions: []rolebus.Permission{rolebus.Permissions.Admin},
+				Subject:     []rolebus.Permission{rolebus.Permissions.User},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Role.QueryRulePermissions(ctx, "rule_admin_or_subject")
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "grant",
+			ExpResp: []any{
+				rolebus.RulePermissions{
+					Rule: "rule_user_only",
+					Permissions: []rolebus.Permission{
+						rolebus.MustParsePermission("orders"),
+						rolebus.Permissions.User,
+					},
+				},
+				[]rolebus.Permission{rolebus.MustParsePermission("orders")},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				perm := rolebus.MustParsePermission("orders")
+
+				// A new role with a new permission satisfies the rule once
+				// the permission is granted to the rule.
+				nr := rolebus.NewRole{
+					Name:        userbus.MustParseRole("CLERK"),
+					Permissions: []rolebus.Permission{perm},
+				}
+
+				if _, err := busDomain.Role.Create(ctx, nr); err != nil {
+					return err
+				}
+
+				rp, err := busDomain.Role.GrantRule(ctx, "rule_user_only", perm, rolebus.Scopes.All)
+				if err != nil {
+					return err
+				}
+
+				perms, err := busDomain.Role.QueryPermissions(ctx, []userbus.Role{nr.Name})
+				if err != nil {
+					return err
+				}
+
+				return []any{rp, perms}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,166 @@
+// Package rolebus provides business access to role domain. Roles are stored
+// in the database along with the permissions granted to them, and so are the
+// permissions that satisfy each rule of the authorization policy. New roles
+// and permissions can be added without a deploy.
+package rolebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("role not found")
+	ErrUniqueName = errors.New("role name is not unique")
+)
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, role Role) error
+	Grant(ctx context.Context, role Role, perm Permission) error
+	Query(ctx context.Context, page page.Page) ([]Role, error)
+	Count(ctx context.Context) (int, error)
+	QueryAll(ctx context.Context) ([]Role, error)
+	QueryByName(ctx context.Context, name userbus.Role) (Role, error)
+	QueryPermissions(ctx context.Context, names []userbus.Role) ([]Permission, error)
+	GrantRule(ctx context.Context, rule string, perm Permission, scope Scope) error
+	QueryRulePermissions(ctx context.Context, rule string) (RulePermissions, error)
+}
+
+// Business manages the set of APIs for role access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a role business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new role to the system.
+func (b *Business) Create(ctx context.Context, nr NewRole) (Role, error) {
+	role := Role{
+		Name:        nr.Name,
+		Permissions: nr.Permissions,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, role); err != nil {
+		return Role{}, fmt.Errorf("create: %w", err)
+	}
+
+	return role, nil
+}
+
+// Grant gives the permission to the role. Granting a permission the role
+// already has is not an error.
+func (b *Business) Grant(ctx context.Context, role Role, perm Permission) (Role, error) {
+	if slices.ContainsFunc(role.Permissions, perm.Equal) {
+		return role, nil
+	}
+
+	if err := b.storer.Grant(ctx, role, perm); err != nil {
+		return Role{}, fmt.Errorf("grant: %w", err)
+	}
+
+	role.Permissions = append(slices.Clone(role.Permissions), perm)
+	slices.SortFunc(role.Permissions, func(a, b Permission) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return role, nil
+}
+
+// Query retrieves a list of existing roles ordered by name.
+func (b *Business) Query(ctx context.Context, page page.Page) ([]Role, error) {
+	roles, err := b.storer.Query(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Count returns the total number of roles.
+func (b *Business) Count(ctx context.Context) (int, error) {
+	return b.storer.Count(ctx)
+}
+
+// QueryByName finds the role by the specified name.
+func (b *Business) QueryByName(ctx context.Context, name userbus.Role) (Role, error) {
+	role, err := b.storer.QueryByName(ctx, name)
+	if err != nil {
+		return Role{}, fmt.Errorf("query: name[%s]: %w", name, err)
+	}
+
+	return role, nil
+}
+
+// QueryPermissions returns the distinct set of permissions granted to any of
+// the specified roles.
+func (b *Business) QueryPermissions(ctx context.Context, names []userbus.Role) ([]Permission, error) {
+	perms, err := b.storer.QueryPermissions(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("querypermissions: %w", err)
+	}
+
+	return perms, nil
+}
+
+// GrantRule makes the permission satisfy the authorization rule within the
+// scope. Granting a permission the rule already accepts replaces its scope.
+func (b *Business) GrantRule(ctx context.Context, rule string, perm Permission, scope Scope) (RulePermissions, error) {
+	if err := b.storer.GrantRule(ctx, rule, perm, scope); err != nil {
+		return RulePermissions{}, fmt.Errorf("grantrule: rule[%s] permission[%s]: %w", rule, perm, err)
+	}
+
+	rp, err := b.storer.QueryRulePermissions(ctx, rule)
+	if err != nil {
+		return RulePermissions{}, fmt.Errorf("queryrulepermissions: rule[%s]: %w", rule, err)
+	}
+
+	return rp, nil
+}
+
+// QueryRulePermissions returns the permissions that satisfy the authorization
+// rule.
+func (b *Business) QueryRulePermissions(ctx context.Context, rule string) (RulePermissions, error) {
+	rp, err := b.storer.QueryRulePermissions(ctx, rule)
+	if err != nil {
+		return RulePermissions{}, fmt.Errorf("queryrulepermissions: rule[%s]: %w", rule, err)
+	}
+
+	return rp, nil
+}
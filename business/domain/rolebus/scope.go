@@ -0,0 +1,64 @@
+package rolebus
+
+import "fmt"
+
+type scopeSet struct {
+	All     Scope
+	Subject Scope
+}
+
+// Scopes represents the set of scopes a permission can satisfy a rule in.
+var Scopes = scopeSet{
+	All:     newScope("all"),
+	Subject: newScope("subject"),
+}
+
+// =============================================================================
+
+// Set of known scopes.
+var scopes = make(map[string]Scope)
+
+// Scope represents where a permission satisfies a rule. A permission in the
+// subject scope only satisfies the rule when users act on their own data.
+type Scope struct {
+	name string
+}
+
+func newScope(scope string) Scope {
+	s := Scope{scope}
+	scopes[scope] = s
+	return s
+}
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	return s.name
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Scope) Equal(s2 Scope) bool {
+	return s.name == s2.name
+}
+
+// =============================================================================
+
+// ParseScope parses the string value and returns a scope if one exists.
+func ParseScope(value string) (Scope, error) {
+	scope, exists := scopes[value]
+	if !exists {
+		return Scope{}, fmt.Errorf("invalid scope %q", value)
+	}
+
+	return scope, nil
+}
+
+// MustParseScope parses the string value and returns a scope if one exists.
+// If an error occurs the function panics.
+func MustParseScope(value string) Scope {
+	scope, err := ParseScope(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return scope
+}
@@ -0,0 +1,90 @@
+package roledb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
+)
+
+type role struct {
+	Name        string         `db:"name"`
+	Permissions dbarray.String `db:"permissions"`
+	DateCreated time.Time      `db:"date_created"`
+}
+
+func toDBRole(bus rolebus.Role) role {
+	return role{
+		Name:        bus.Name.String(),
+		Permissions: rolebus.ParsePermissionsToString(bus.Permissions),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusRole(db role) (rolebus.Role, error) {
+	name, err := userbus.ParseRole(db.Name)
+	if err != nil {
+		return rolebus.Role{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	perms, err := rolebus.ParsePermissions(db.Permissions)
+	if err != nil {
+		return rolebus.Role{}, fmt.Errorf("parse permissions: %w", err)
+	}
+
+	bus := rolebus.Role{
+		Name:        name,
+		Permissions: perms,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusRoles(dbs []role) ([]rolebus.Role, error) {
+	bus := make([]rolebus.Role, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusRole(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+type rulePermission struct {
+	Permission string `db:"permission"`
+	Scope      string `db:"scope"`
+}
+
+func toBusRulePermissions(rule string, dbs []rulePermission) (rolebus.RulePermissions, error) {
+	bus := rolebus.RulePermissions{
+		Rule: rule,
+	}
+
+	for _, db := range dbs {
+		perm, err := rolebus.ParsePermission(db.Permission)
+		if err != nil {
+			return rolebus.RulePermissions{}, fmt.Errorf("parse permission: %w", err)
+		}
+
+		scope, err := rolebus.ParseScope(db.Scope)
+		if err != nil {
+			return rolebus.RulePermissions{}, fmt.Errorf("parse scope: %w", err)
+		}
+
+		switch scope {
+		case rolebus.Scopes.Subject:
+			bus.Subject = append(bus.Subject, perm)
+		default:
+			bus.Permissions = append(bus.Permissions, perm)
+		}
+	}
+
+	return bus, nil
+}
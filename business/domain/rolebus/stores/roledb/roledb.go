@@ -0,0 +1,284 @@
+// Package roledb contains role related CRUD functionality.
+package roledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// selectRoles selects each role with the permissions it has been granted.
+const selectRoles = `
+	SELECT
+		r.name,
+		r.date_created,
+		COALESCE(array_agg(p.permission ORDER BY p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions
+	FROM
+		roles AS r
+	LEFT JOIN
+		role_permissions AS p ON p.role_name = r.name`
+
+// Store manages the set of APIs for role database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (rolebus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new role and its permissions into the database.
+func (s *Store) Create(ctx context.Context, role rolebus.Role) error {
+	const q = `
+	WITH r AS (
+		INSERT INTO roles
+			(name, date_created)
+		VALUES
+			(:name, :date_created)
+		RETURNING name
+	)
+	INSERT INTO role_permissions
+		(role_name, permission)
+	SELECT
+		r.name, unnest(CAST(:permissions AS TEXT[]))
+	FROM
+		r`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRole(role)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", rolebus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Grant inserts the permission for the role into the database.
+func (s *Store) Grant(ctx context.Context, role rolebus.Role, perm rolebus.Permission) error {
+	data := struct {
+		Name       string `db:"name"`
+		Permission string `db:"permission"`
+	}{
+		Name:       role.Name.String(),
+		Permission: perm.String(),
+	}
+
+	const q = `
+	INSERT INTO role_permissions
+		(role_name, permission)
+	VALUES
+		(:name, :permission)
+	ON CONFLICT DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing roles from the database.
+func (s *Store) Query(ctx context.Context, page page.Page) ([]rolebus.Role, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = selectRoles + `
+	GROUP BY
+		r.name, r.date_created
+	ORDER BY
+		r.name
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbRoles []role
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusRoles(dbRoles)
+}
+
+// Count returns the total number of roles in the DB.
+func (s *Store) Count(ctx context.Context) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		roles`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryAll retrieves every role from the database.
+func (s *Store) QueryAll(ctx context.Context) ([]rolebus.Role, error) {
+	const q = selectRoles + `
+	GROUP BY
+		r.name, r.date_created
+	ORDER BY
+		r.name`
+
+	var dbRoles []role
+	if err := sqldb.QuerySlice(ctx, s.log, s.db, q, &dbRoles); err != nil {
+		return nil, fmt.Errorf("queryslice: %w", err)
+	}
+
+	return toBusRoles(dbRoles)
+}
+
+// QueryByName gets the specified role from the database.
+func (s *Store) QueryByName(ctx context.Context, name userbus.Role) (rolebus.Role, error) {
+	data := struct {
+		Name string `db:"name"`
+	}{
+		Name: name.String(),
+	}
+
+	const q = selectRoles + `
+	WHERE
+		r.name = :name
+	GROUP BY
+		r.name, r.date_created`
+
+	var dbRole role
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRole); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return rolebus.Role{}, fmt.Errorf("db: %w", rolebus.ErrNotFound)
+		}
+		return rolebus.Role{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRole(dbRole)
+}
+
+// QueryPermissions gets the distinct permissions granted to any of the
+// specified roles from the database.
+func (s *Store) QueryPermissions(ctx context.Context, names []userbus.Role) ([]rolebus.Permission, error) {
+	data := struct {
+		Names dbarray.String `db:"names"`
+	}{
+		Names: userbus.ParseRolesToString(names),
+	}
+
+	const q = `
+	SELECT DISTINCT
+		permission
+	FROM
+		role_permissions
+	WHERE
+		role_name = ANY(:names)
+	ORDER BY
+		permission`
+
+	var dbPerms []struct {
+		Permission string `db:"permission"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPerms); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	perms := make([]rolebus.Permission, len(dbPerms))
+	for i, db := range dbPerms {
+		var err error
+		perms[i], err = rolebus.ParsePermission(db.Permission)
+		if err != nil {
+			return nil, fmt.Errorf("parse permission: %w", err)
+		}
+	}
+
+	return perms, nil
+}
+
+// GrantRule inserts the permission for the rule into the database or replaces
+// the scope it's granted in.
+func (s *Store) GrantRule(ctx context.Context, rule string, perm rolebus.Permission, scope rolebus.Scope) error {
+	data := struct {
+		Rule       string `db:"rule"`
+		Permission string `db:"permission"`
+		Scope      string `db:"scope"`
+	}{
+		Rule:       rule,
+		Permission: perm.String(),
+		Scope:      scope.String(),
+	}
+
+	const q = `
+	INSERT INTO rule_permissions
+		(rule, permission, scope)
+	VALUES
+		(:rule, :permission, :scope)
+	ON CONFLICT (rule, permission) DO UPDATE SET
+		scope = EXCLUDED.scope`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRulePermissions gets the permissions that satisfy the rule from the
+// database.
+func (s *Store) QueryRulePermissions(ctx context.Context, rule string) (rolebus.RulePermissions, error) {
+	data := struct {
+		Rule string `db:"rule"`
+	}{
+		Rule: rule,
+	}
+
+	const q = `
+	SELECT
+		permission, scope
+	FROM
+		rule_permissions
+	WHERE
+		rule = :rule
+	ORDER BY
+		permission`
+
+	var dbPerms []rulePermission
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPerms); err != nil {
+		return rolebus.RulePermissions{}, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusRulePermissions(rule, dbPerms)
+}
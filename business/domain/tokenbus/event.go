@@ -0,0 +1,48 @@
+package tokenbus
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system. If the business was constructed for query only, there won't be a
+// delegate provided.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		delegate.Register(b.delegate, "tokenbus.user-updated", b.actionUserUpdated)
+		delegate.Register(b.delegate, "tokenbus.user-deleted", b.actionUserDeleted)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is
+// updated. A disabled user can't refresh their access tokens anymore.
+func (b *Business) actionUserUpdated(ctx context.Context, claim delegate.Claim, params userbus.ActionUpdatedParms) error {
+	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", params.Enabled)
+
+	if params.Enabled == nil || *params.Enabled {
+		return nil
+	}
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	return bus.RevokeByUserID(ctx, params.UserID)
+}
+
+// actionUserDeleted is executed by the user domain indirectly when a user is
+// deleted. A deleted user can't refresh their access tokens anymore.
+func (b *Business) actionUserDeleted(ctx context.Context, claim delegate.Claim, params userbus.ActionDeletedParms) error {
+	b.log.Info(ctx, "action-userdeleted", "user_id", params.UserID)
+
+	bus, err := delegate.WithClaim(claim, b, b.NewWithTx)
+	if err != nil {
+		return err
+	}
+
+	return bus.RevokeByUserID(ctx, params.UserID)
+}
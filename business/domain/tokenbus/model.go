@@ -0,0 +1,34 @@
+package tokenbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents a refresh token issued to a user. Only the hash of
+// the token is kept. The tokens issued by rotating a refresh token belong to
+// the same family.
+type RefreshToken struct {
+	ID          uuid.UUID
+	FamilyID    uuid.UUID
+	UserID      uuid.UUID
+	Hash        []byte
+	DateExpires time.Time
+	DateCreated time.Time
+	DateUsed    time.Time
+	DateRevoked time.Time
+}
+
+// Issued represents a refresh token that was issued along with the value
+// that has to be handed to the client. The value can't be recovered later.
+type Issued struct {
+	RefreshToken
+	Token string
+}
+
+// RevokedToken represents an access token that was revoked before it expired.
+type RevokedToken struct {
+	JTI         string
+	DateExpires time.Time
+}
@@ -0,0 +1,76 @@
+package tokendb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/google/uuid"
+)
+
+type refreshToken struct {
+	ID          uuid.UUID    `db:"token_id"`
+	FamilyID    uuid.UUID    `db:"family_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Hash        []byte       `db:"token_hash"`
+	DateExpires time.Time    `db:"date_expires"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUsed    sql.NullTime `db:"date_used"`
+	DateRevoked sql.NullTime `db:"date_revoked"`
+}
+
+func toDBRefreshToken(bus tokenbus.RefreshToken) refreshToken {
+	db := refreshToken{
+		ID:          bus.ID,
+		FamilyID:    bus.FamilyID,
+		UserID:      bus.UserID,
+		Hash:        bus.Hash,
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  bus.DateUsed.UTC(),
+			Valid: !bus.DateUsed.IsZero(),
+		},
+		DateRevoked: sql.NullTime{
+			Time:  bus.DateRevoked.UTC(),
+			Valid: !bus.DateRevoked.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusRefreshToken(db refreshToken) tokenbus.RefreshToken {
+	bus := tokenbus.RefreshToken{
+		ID:          db.ID,
+		FamilyID:    db.FamilyID,
+		UserID:      db.UserID,
+		Hash:        db.Hash,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateUsed.Valid {
+		bus.DateUsed = db.DateUsed.Time.In(time.Local)
+	}
+
+	if db.DateRevoked.Valid {
+		bus.DateRevoked = db.DateRevoked.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type revokedToken struct {
+	JTI         string    `db:"jti"`
+	DateExpires time.Time `db:"date_expires"`
+}
+
+func toDBRevokedToken(bus tokenbus.RevokedToken) revokedToken {
+	return revokedToken{
+		JTI:         bus.JTI,
+		DateExpires: bus.DateExpires.UTC(),
+	}
+}
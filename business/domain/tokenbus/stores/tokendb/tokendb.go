@@ -0,0 +1,248 @@
+// Package tokendb contains refresh token and denylist related CRUD
+// functionality.
+package tokendb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for token database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (tokenbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new refresh token into the database.
+func (s *Store) Create(ctx context.Context, tkn tokenbus.RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_id, family_id, user_id, token_hash, date_expires, date_created, date_used, date_revoked)
+	VALUES
+		(:token_id, :family_id, :user_id, :token_hash, :date_expires, :date_created, :date_used, :date_revoked)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRefreshToken(tkn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks the refresh token as used. The token is only marked when it
+// hasn't been used or revoked, otherwise tokenbus.ErrNotFound is returned.
+func (s *Store) Use(ctx context.Context, tkn tokenbus.RefreshToken) error {
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_used = :date_used
+	WHERE
+		token_id = :token_id AND
+		date_used IS NULL AND
+		date_revoked IS NULL
+	RETURNING
+		token_id`
+
+	var dbTkn struct {
+		ID uuid.UUID `db:"token_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBRefreshToken(tkn), &dbTkn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", tokenbus.ErrNotFound)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every refresh token in the family that isn't already
+// revoked.
+func (s *Store) RevokeFamily(ctx context.Context, familyID uuid.UUID, dateRevoked time.Time) error {
+	data := struct {
+		FamilyID    uuid.UUID `db:"family_id"`
+		DateRevoked time.Time `db:"date_revoked"`
+	}{
+		FamilyID:    familyID,
+		DateRevoked: dateRevoked.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_revoked = :date_revoked
+	WHERE
+		family_id = :family_id AND
+		date_revoked IS NULL`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every refresh token issued to the user that isn't
+// already revoked.
+func (s *Store) RevokeByUserID(ctx context.Context, userID uuid.UUID, dateRevoked time.Time) error {
+	data := struct {
+		UserID      uuid.UUID `db:"user_id"`
+		DateRevoked time.Time `db:"date_revoked"`
+	}{
+		UserID:      userID,
+		DateRevoked: dateRevoked.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_revoked = :date_revoked
+	WHERE
+		user_id = :user_id AND
+		date_revoked IS NULL`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByHash gets the refresh token with the specified hash from the
+// database.
+func (s *Store) QueryByHash(ctx context.Context, hash []byte) (tokenbus.RefreshToken, error) {
+	data := struct {
+		Hash []byte `db:"token_hash"`
+	}{
+		Hash: hash,
+	}
+
+	const q = `
+	SELECT
+		token_id, family_id, user_id, token_hash, date_expires, date_created, date_used, date_revoked
+	FROM
+		refresh_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbTkn refreshToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTkn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return tokenbus.RefreshToken{}, fmt.Errorf("db: %w", tokenbus.ErrNotFound)
+		}
+		return tokenbus.RefreshToken{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRefreshToken(dbTkn), nil
+}
+
+// Deny adds the access token to the denylist. Denying a token twice isn't an
+// error.
+func (s *Store) Deny(ctx context.Context, rt tokenbus.RevokedToken) error {
+	const q = `
+	INSERT INTO revoked_tokens
+		(jti, date_expires)
+	VALUES
+		(:jti, :date_expires)
+	ON CONFLICT (jti) DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRevokedToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// IsDenied reports whether the access token is on the denylist.
+func (s *Store) IsDenied(ctx context.Context, jti string) (bool, error) {
+	data := struct {
+		JTI string `db:"jti"`
+	}{
+		JTI: jti,
+	}
+
+	const q = `
+	SELECT
+		EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = :jti) AS denied`
+
+	var result struct {
+		Denied bool `db:"denied"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return result.Denied, nil
+}
+
+// Purge removes the refresh tokens and denylist entries that expired before
+// the specified time.
+func (s *Store) Purge(ctx context.Context, expiredBefore time.Time) (int, error) {
+	data := struct {
+		ExpiredBefore time.Time `db:"expired_before"`
+	}{
+		ExpiredBefore: expiredBefore.UTC(),
+	}
+
+	const q = `
+	WITH refresh AS (
+		DELETE FROM
+			refresh_tokens
+		WHERE
+			date_expires < :expired_before
+		RETURNING
+			token_id
+	), revoked AS (
+		DELETE FROM
+			revoked_tokens
+		WHERE
+			date_expires < :expired_before
+		RETURNING
+			jti
+	)
+	SELECT
+		(SELECT count(1) FROM refresh) + (SELECT count(1) FROM revoked) AS count`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,282 @@
+package tokenbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+const ttl = time.Hour
+
+func Test_Token(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, rotate(db.BusDomain, sd), "rotate")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+	unitest.Run(t, deny(db.BusDomain), "deny")
+	unitest.Run(t, disable(db.BusDomain, sd), "disable")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, userbus.Roles.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User: usrs[0],
+	}
+
+	tu2 := unitest.User{
+		User: usrs[1],
+	}
+
+	// -------------------------------------------------------------------------
+
+	usrs, err = userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	tu3 := unitest.User{
+		User: usrs[0],
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Users:  []unitest.User{tu1, tu2},
+		Admins: []unitest.User{tu3},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func rotate(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: sd.Users[0].ID,
+			ExcFunc: func(ctx context.Context) any {
+				iss, err := busDomain.Token.Issue(ctx, sd.Users[0].ID, ttl)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				if err != nil {
+					return err
+				}
+
+				if resp.FamilyID != iss.FamilyID {
+					return errors.New("should stay in the same family")
+				}
+
+				if resp.Token == iss.Token {
+					return errors.New("should get a new token")
+				}
+
+				return resp.UserID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reused",
+			ExpResp: []error{tokenbus.ErrReused, tokenbus.ErrRevoked},
+			ExcFunc: func(ctx context.Context) any {
+				iss, err := busDomain.Token.Issue(ctx, sd.Users[0].ID, ttl)
+				if err != nil {
+					return err
+				}
+
+				next, err := busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				if err != nil {
+					return err
+				}
+
+				// Presenting the first token again revokes the family so the
+				// token it was exchanged for can't be used either.
+				_, err1 := busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				_, err2 := busDomain.Token.Rotate(ctx, next.Token, ttl)
+
+				return []error{err1, err2}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]error)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.([]error)
+
+				for i := range expResp {
+					if !errors.Is(gotResp[i], expResp[i]) {
+						return fmt.Sprintf("got %v, expected %v", gotResp[i], expResp[i])
+					}
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "expired",
+			ExpResp: tokenbus.ErrExpired,
+			ExcFunc: func(ctx context.Context) any {
+				iss, err := busDomain.Token.Issue(ctx, sd.Users[0].ID, -time.Minute)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "unknown",
+			ExpResp: tokenbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Token.Rotate(ctx, "unknown", ttl)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "family",
+			ExpResp: tokenbus.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				iss, err := busDomain.Token.Issue(ctx, sd.Users[0].ID, ttl)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Token.Revoke(ctx, iss.Token); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func deny(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: []bool{true, false},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Token.Deny(ctx, "denied", time.Now().Add(ttl)); err != nil {
+					return err
+				}
+
+				// Denying a token twice isn't an error.
+				if err := busDomain.Token.Deny(ctx, "denied", time.Now().Add(ttl)); err != nil {
+					return err
+				}
+
+				denied, err := busDomain.Token.IsDenied(ctx, "denied")
+				if err != nil {
+					return err
+				}
+
+				allowed, err := busDomain.Token.IsDenied(ctx, "allowed")
+				if err != nil {
+					return err
+				}
+
+				return []bool{denied, allowed}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func disable(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "user",
+			ExpResp: tokenbus.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				iss, err := busDomain.Token.Issue(ctx, sd.Users[1].ID, ttl)
+				if err != nil {
+					return err
+				}
+
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(false),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[1].User, uu); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Token.Rotate(ctx, iss.Token, ttl)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	gotErr, exists := got.(error)
+	if !exists {
+		return fmt.Sprintf("expected an error, got %v", got)
+	}
+
+	if !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("got %v, expected %v", gotErr, exp)
+	}
+
+	return ""
+}
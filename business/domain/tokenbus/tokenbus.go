@@ -0,0 +1,235 @@
+// Package tokenbus provides business access to the refresh token domain and
+// the access tokens that were revoked.
+package tokenbus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for refresh token operations.
+var (
+	ErrNotFound = errors.New("refresh token not found")
+	ErrExpired  = errors.New("refresh token expired")
+	ErrRevoked  = errors.New("refresh token revoked")
+	ErrReused   = errors.New("refresh token reused")
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, tkn RefreshToken) error
+	Use(ctx context.Context, tkn RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, dateRevoked time.Time) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID, dateRevoked time.Time) error
+	QueryByHash(ctx context.Context, hash []byte) (RefreshToken, error)
+	Deny(ctx context.Context, rt RevokedToken) error
+	IsDenied(ctx context.Context, jti string) (bool, error)
+	Purge(ctx context.Context, expiredBefore time.Time) (int, error)
+}
+
+// Business manages the set of APIs for token access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+}
+
+// NewBusiness constructs a token business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		delegate: b.delegate,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Issue creates a refresh token for the user that starts a new family.
+func (b *Business) Issue(ctx context.Context, userID uuid.UUID, ttl time.Duration) (Issued, error) {
+	iss, err := b.issue(ctx, userID, uuid.New(), ttl)
+	if err != nil {
+		return Issued{}, fmt.Errorf("issue: userID[%s]: %w", userID, err)
+	}
+
+	return iss, nil
+}
+
+// Rotate exchanges the refresh token for a new one in the same family. A
+// token can only be exchanged once. When a token that was already exchanged
+// is presented again, the token has leaked and the whole family is revoked.
+func (b *Business) Rotate(ctx context.Context, token string, ttl time.Duration) (Issued, error) {
+	tkn, err := b.storer.QueryByHash(ctx, hash(token))
+	if err != nil {
+		return Issued{}, fmt.Errorf("query: %w", err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case !tkn.DateRevoked.IsZero():
+		return Issued{}, ErrRevoked
+
+	case !tkn.DateUsed.IsZero():
+		return Issued{}, b.reused(ctx, tkn, now)
+
+	case now.After(tkn.DateExpires):
+		return Issued{}, ErrExpired
+	}
+
+	tkn.DateUsed = now
+
+	// Another request could have exchanged the token since it was read, so
+	// the store only marks a token that is still unused.
+	if err := b.storer.Use(ctx, tkn); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Issued{}, b.reused(ctx, tkn, now)
+		}
+		return Issued{}, fmt.Errorf("use: tokenID[%s]: %w", tkn.ID, err)
+	}
+
+	iss, err := b.issue(ctx, tkn.UserID, tkn.FamilyID, ttl)
+	if err != nil {
+		return Issued{}, fmt.Errorf("issue: userID[%s]: %w", tkn.UserID, err)
+	}
+
+	return iss, nil
+}
+
+// Revoke revokes the family of the refresh token.
+func (b *Business) Revoke(ctx context.Context, token string) error {
+	tkn, err := b.storer.QueryByHash(ctx, hash(token))
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if err := b.storer.RevokeFamily(ctx, tkn.FamilyID, time.Now()); err != nil {
+		return fmt.Errorf("revokefamily: familyID[%s]: %w", tkn.FamilyID, err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every refresh token issued to the user.
+func (b *Business) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := b.storer.RevokeByUserID(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("revokebyuserid: userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// Deny adds the id of an access token to the denylist until it expires.
+func (b *Business) Deny(ctx context.Context, jti string, expires time.Time) error {
+	rt := RevokedToken{
+		JTI:         jti,
+		DateExpires: expires,
+	}
+
+	if err := b.storer.Deny(ctx, rt); err != nil {
+		return fmt.Errorf("deny: jti[%s]: %w", jti, err)
+	}
+
+	return nil
+}
+
+// IsDenied reports whether the access token with the id was revoked.
+func (b *Business) IsDenied(ctx context.Context, jti string) (bool, error) {
+	denied, err := b.storer.IsDenied(ctx, jti)
+	if err != nil {
+		return false, fmt.Errorf("isdenied: jti[%s]: %w", jti, err)
+	}
+
+	return denied, nil
+}
+
+// Purge permanently removes the refresh tokens and denylist entries that
+// expired longer ago than the specified retention window.
+func (b *Business) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	expiredBefore := time.Now().Add(-retention)
+
+	n, err := b.storer.Purge(ctx, expiredBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: expiredBefore[%s]: %w", expiredBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
+// =============================================================================
+
+func (b *Business) issue(ctx context.Context, userID uuid.UUID, familyID uuid.UUID, ttl time.Duration) (Issued, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return Issued{}, fmt.Errorf("generating token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+
+	tkn := RefreshToken{
+		ID:          uuid.New(),
+		FamilyID:    familyID,
+		UserID:      userID,
+		Hash:        hash(token),
+		DateExpires: now.Add(ttl),
+		DateCreated: now,
+	}
+
+	if err := b.storer.Create(ctx, tkn); err != nil {
+		return Issued{}, fmt.Errorf("create: %w", err)
+	}
+
+	iss := Issued{
+		RefreshToken: tkn,
+		Token:        token,
+	}
+
+	return iss, nil
+}
+
+func (b *Business) reused(ctx context.Context, tkn RefreshToken, now time.Time) error {
+	b.log.Info(ctx, "refresh token reused", "tokenID", tkn.ID, "familyID", tkn.FamilyID, "userID", tkn.UserID)
+
+	if err := b.storer.RevokeFamily(ctx, tkn.FamilyID, now); err != nil {
+		return fmt.Errorf("revokefamily: familyID[%s]: %w", tkn.FamilyID, err)
+	}
+
+	return ErrReused
+}
+
+func hash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
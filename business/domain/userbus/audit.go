@@ -0,0 +1,62 @@
+package userbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/google/uuid"
+)
+
+// auditUser is the view of a user that is recorded in the audit log. The
+// password hash is left out on purpose.
+type auditUser struct {
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Roles         []string   `json:"roles"`
+	Department    string     `json:"department"`
+	Enabled       bool       `json:"enabled"`
+	EmailVerified bool       `json:"emailVerified"`
+	DateDeleted   *time.Time `json:"dateDeleted,omitempty"`
+}
+
+func toAuditUser(usr User) auditUser {
+	var dateDeleted *time.Time
+	if !usr.DateDeleted.IsZero() {
+		dateDeleted = &usr.DateDeleted
+	}
+
+	return auditUser{
+		Name:          usr.Name.String(),
+		Email:         usr.Email.Address,
+		Roles:         ParseRolesToString(usr.Roles),
+		Department:    usr.Department,
+		Enabled:       usr.Enabled,
+		EmailVerified: usr.EmailVerified,
+		DateDeleted:   dateDeleted,
+	}
+}
+
+// audit records the change made to the user in the audit log. If the business
+// was constructed for query only, there won't be an audit api provided.
+func (b *Business) audit(ctx context.Context, actorID uuid.UUID, action auditbus.Action, userID uuid.UUID, before any, after any) error {
+	if b.auditBus == nil {
+		return nil
+	}
+
+	na := auditbus.NewAudit{
+		ActorID:  actorID,
+		Entity:   auditbus.Entities.User,
+		EntityID: userID,
+		Action:   action,
+		Before:   before,
+		After:    after,
+	}
+
+	if _, err := b.auditBus.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: action[%s]: %w", action, err)
+	}
+
+	return nil
+}
@@ -1,10 +1,12 @@
 package userbus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
 	"github.com/google/uuid"
 )
 
@@ -13,7 +15,9 @@ const DomainName = "user"
 
 // Set of delegate actions.
 const (
-	ActionUpdated = "updated"
+	ActionUpdated  = "updated"
+	ActionDeleted  = "deleted"
+	ActionRestored = "restored"
 )
 
 // ActionUpdatedParms represents the parameters for the updated action.
@@ -22,33 +26,93 @@ type ActionUpdatedParms struct {
 	UpdateUser
 }
 
+// Schema implements the delegate.Params interface.
+func (ActionUpdatedParms) Schema() delegate.Schema {
+	return delegate.Schema{Domain: DomainName, Action: ActionUpdated, Version: 1}
+}
+
 // String returns a string representation of the action parameters.
-func (au *ActionUpdatedParms) String() string {
+func (au ActionUpdatedParms) String() string {
 	return fmt.Sprintf("&EventParamsUpdated{UserID:%v, Enabled:%v}", au.UserID, au.Enabled)
 }
 
-// Marshal returns the event parameters encoded as JSON.
-func (au *ActionUpdatedParms) Marshal() ([]byte, error) {
-	return json.Marshal(au)
+// =============================================================================
+
+// ActionDeletedParms represents the parameters for the deleted action.
+type ActionDeletedParms struct {
+	UserID      uuid.UUID
+	DateDeleted time.Time
+}
+
+// Schema implements the delegate.Params interface.
+func (ActionDeletedParms) Schema() delegate.Schema {
+	return delegate.Schema{Domain: DomainName, Action: ActionDeleted, Version: 1}
 }
 
-// ActionUpdatedData constructs the data for the updated action.
-func ActionUpdatedData(uu UpdateUser, userID uuid.UUID) delegate.Data {
-	params := ActionUpdatedParms{
-		UserID: userID,
-		UpdateUser: UpdateUser{
-			Enabled: uu.Enabled,
-		},
+// String returns a string representation of the action parameters.
+func (ad ActionDeletedParms) String() string {
+	return fmt.Sprintf("&EventParamsDeleted{UserID:%v, DateDeleted:%v}", ad.UserID, ad.DateDeleted)
+}
+
+// =============================================================================
+
+// ActionRestoredParms represents the parameters for the restored action. The
+// DateDeleted field holds the time the user was deleted so only the data that
+// was removed along with the user is restored.
+type ActionRestoredParms struct {
+	UserID      uuid.UUID
+	DateDeleted time.Time
+}
+
+// Schema implements the delegate.Params interface.
+func (ActionRestoredParms) Schema() delegate.Schema {
+	return delegate.Schema{Domain: DomainName, Action: ActionRestored, Version: 1}
+}
+
+// String returns a string representation of the action parameters.
+func (ar ActionRestoredParms) String() string {
+	return fmt.Sprintf("&EventParamsRestored{UserID:%v, DateDeleted:%v}", ar.UserID, ar.DateDeleted)
+}
+
+// =============================================================================
+
+// publishEvent writes the public event for the change to the user. When the
+// business runs in a transaction, the event is only sent once it commits.
+func (b *Business) publishEvent(ctx context.Context, typ string, usr User) error {
+	if b.delegate == nil {
+		return nil
 	}
 
-	rawParams, err := params.Marshal()
-	if err != nil {
-		panic(err)
+	if err := delegate.Publish(ctx, b.delegate, toUserEvent(typ, usr)); err != nil {
+		return fmt.Errorf("publish: %s event: userID[%s]: %w", typ, usr.ID, err)
 	}
 
-	return delegate.Data{
-		Domain:    DomainName,
-		Action:    ActionUpdated,
-		RawParams: rawParams,
+	return nil
+}
+
+func toUserEvent(typ string, usr User) events.UserEvent {
+	evt := events.UserEvent{
+		Version:    events.Version,
+		ID:         uuid.NewString(),
+		Type:       typ,
+		UserID:     usr.ID.String(),
+		OccurredAt: time.Now().UTC(),
+		User: events.User{
+			ID:            usr.ID.String(),
+			TenantID:      usr.TenantID.String(),
+			Roles:         ParseRolesToString(usr.Roles),
+			Department:    usr.Department,
+			Enabled:       usr.Enabled,
+			EmailVerified: usr.EmailVerified,
+			DateCreated:   usr.DateCreated.UTC(),
+			DateUpdated:   usr.DateUpdated.UTC(),
+		},
 	}
+
+	if !usr.DateDeleted.IsZero() {
+		dateDeleted := usr.DateDeleted.UTC()
+		evt.User.DateDeleted = &dateDeleted
+	}
+
+	return evt
 }
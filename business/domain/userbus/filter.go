@@ -9,10 +9,21 @@ import (
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// Deleted users are excluded unless Deleted is set to true, in which case
+// only the deleted users are returned. Name and Department match any user
+// whose value contains the filter value and NamePrefix any user whose name
+// starts with it, all ignoring case. Roles matches users holding any of the
+// roles.
 type QueryFilter struct {
 	ID               *uuid.UUID
+	IDs              []uuid.UUID
 	Name             *Name
+	NamePrefix       *Name
 	Email            *mail.Address
+	Roles            []Role
+	Department       *string
+	Enabled          *bool
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
+	Deleted          *bool
 }
@@ -9,19 +9,24 @@ import (
 
 // User represents information about an individual user.
 type User struct {
-	ID           uuid.UUID
-	Name         Name
-	Email        mail.Address
-	Roles        []Role
-	PasswordHash []byte
-	Department   string
-	Enabled      bool
-	DateCreated  time.Time
-	DateUpdated  time.Time
+	ID            uuid.UUID
+	TenantID      uuid.UUID
+	Name          Name
+	Email         mail.Address
+	Roles         []Role
+	PasswordHash  []byte
+	Department    string
+	Enabled       bool
+	EmailVerified bool
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	DateDeleted   time.Time
+	Version       int
 }
 
-// NewUser contains information needed to create a new user.
+// NewUser contains information needed to create a new user in the tenant.
 type NewUser struct {
+	TenantID   uuid.UUID
 	Name       Name
 	Email      mail.Address
 	Roles      []Role
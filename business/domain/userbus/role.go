@@ -1,13 +1,16 @@
 package userbus
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
 
 type roleSet struct {
 	Admin Role
 	User  Role
 }
 
-// Roles represents the set of roles that can be used.
+// Roles represents the built-in roles. Other roles are stored by rolebus.
 var Roles = roleSet{
 	Admin: newRole("ADMIN"),
 	User:  newRole("USER"),
@@ -15,18 +18,15 @@ var Roles = roleSet{
 
 // =============================================================================
 
-// Set of known roles.
-var roles = make(map[string]Role)
-
-// Role represents a role in the system.
+// Role represents a role in the system. Roles are stored by the rolebus
+// package, so a role parsed from a value may not exist. The business checks
+// the roles exist when they are assigned to a user.
 type Role struct {
 	name string
 }
 
 func newRole(role string) Role {
-	r := Role{role}
-	roles[role] = r
-	return r
+	return Role{role}
 }
 
 // String returns the name of the role.
@@ -41,18 +41,20 @@ func (r Role) Equal(r2 Role) bool {
 
 // =============================================================================
 
-// ParseRole parses the string value and returns a role if one exists.
+var roleRegEx = regexp.MustCompile("^[A-Z][A-Z_]{1,31}$")
+
+// ParseRole parses the string value and returns a role if the value complies
+// with the rules for a role name.
 func ParseRole(value string) (Role, error) {
-	role, exists := roles[value]
-	if !exists {
+	if !roleRegEx.MatchString(value) {
 		return Role{}, fmt.Errorf("invalid role %q", value)
 	}
 
-	return role, nil
+	return Role{value}, nil
 }
 
-// MustParseRole parses the string value and returns a role if one exists. If
-// an error occurs the function panics.
+// MustParseRole parses the string value and returns a role. If an error
+// occurs the function panics.
 func MustParseRole(value string) Role {
 	role, err := ParseRole(value)
 	if err != nil {
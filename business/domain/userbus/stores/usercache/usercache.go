@@ -3,6 +3,7 @@ package usercache
 
 import (
 	"context"
+	"errors"
 	"net/mail"
 	"time"
 
@@ -15,11 +16,14 @@ import (
 	"github.com/google/uuid"
 )
 
-// Store manages the set of APIs for user data and caching.
+// Store manages the set of APIs for user data and caching. Only the roles
+// that exist are cached, so a role created by another instance is seen as
+// soon as it's stored. A cached role expires with the ttl.
 type Store struct {
 	log    *logger.Logger
 	storer userbus.Storer
 	cache  *sturdyc.Client[userbus.User]
+	roles  *sturdyc.Client[userbus.Role]
 }
 
 // NewStore constructs the api for data and caching access.
@@ -32,6 +36,7 @@ func NewStore(log *logger.Logger, storer userbus.Storer, ttl time.Duration) *Sto
 		log:    log,
 		storer: storer,
 		cache:  sturdyc.New[userbus.User](capacity, numShards, ttl, evictionPercentage),
+		roles:  sturdyc.New[userbus.Role](capacity, numShards, ttl, evictionPercentage),
 	}
 }
 
@@ -52,9 +57,14 @@ func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
-// Update replaces a user document in the database.
+// Update replaces a user document in the database. On a version conflict
+// the cached copy is stale, so it's removed to let the next read see the
+// latest version.
 func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 	if err := s.storer.Update(ctx, usr); err != nil {
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			s.deleteCache(usr)
+		}
 		return err
 	}
 
@@ -66,6 +76,9 @@ func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 // Delete removes a user from the database.
 func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	if err := s.storer.Delete(ctx, usr); err != nil {
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			s.deleteCache(usr)
+		}
 		return err
 	}
 
@@ -74,6 +87,23 @@ func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
+// Restore clears the deleted mark from a user in the database.
+func (s *Store) Restore(ctx context.Context, usr userbus.User) error {
+	if err := s.storer.Restore(ctx, usr); err != nil {
+		return err
+	}
+
+	s.writeCache(usr)
+
+	return nil
+}
+
+// Purge removes the users that were deleted before the specified time from
+// the database. Purged users were removed from the cache when deleted.
+func (s *Store) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	return s.storer.Purge(ctx, deletedBefore)
+}
+
 // Query retrieves a list of existing users from the database.
 func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
 	return s.storer.Query(ctx, filter, orderBy, page)
@@ -118,6 +148,42 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 	return usr, nil
 }
 
+// QueryDeletedByID gets the specified deleted user from the database. Deleted
+// users are never cached.
+func (s *Store) QueryDeletedByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	return s.storer.QueryDeletedByID(ctx, userID)
+}
+
+// QueryRoles gets the specified roles that are stored in the database. The
+// roles that aren't cached are read from the database.
+func (s *Store) QueryRoles(ctx context.Context, roles []userbus.Role) ([]userbus.Role, error) {
+	var known []userbus.Role
+	var missing []userbus.Role
+
+	for _, role := range roles {
+		if cached, exists := s.roles.Get(role.String()); exists {
+			known = append(known, cached)
+			continue
+		}
+		missing = append(missing, role)
+	}
+
+	if len(missing) == 0 {
+		return known, nil
+	}
+
+	stored, err := s.storer.QueryRoles(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range stored {
+		s.roles.Set(role.String(), role)
+	}
+
+	return append(known, stored...), nil
+}
+
 // readCache performs a safe search in the cache for the specified key.
 func (s *Store) readCache(key string) (userbus.User, bool) {
 	usr, exists := s.cache.Get(key)
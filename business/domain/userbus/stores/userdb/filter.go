@@ -6,19 +6,29 @@ import (
 	"strings"
 
 	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
 )
 
-func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
+func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
 		wc = append(wc, "user_id = :user_id")
 	}
 
+	if len(filter.IDs) > 0 {
+		data["user_ids"] = filter.IDs
+		wc = append(wc, "user_id IN (:user_ids)")
+	}
+
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		data["name"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(filter.Name.String()))
+		wc = append(wc, "name ILIKE :name")
+	}
+
+	if filter.NamePrefix != nil {
+		data["name_prefix"] = fmt.Sprintf("%s%%", sqldb.EscapeLike(filter.NamePrefix.String()))
+		wc = append(wc, "name ILIKE :name_prefix")
 	}
 
 	if filter.Email != nil {
@@ -26,6 +36,21 @@ func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buf
 		wc = append(wc, "email = :email")
 	}
 
+	if len(filter.Roles) > 0 {
+		data["roles"] = dbarray.String(userbus.ParseRolesToString(filter.Roles))
+		wc = append(wc, "roles && :roles")
+	}
+
+	if filter.Department != nil {
+		data["department"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(*filter.Department))
+		wc = append(wc, "department ILIKE :department")
+	}
+
+	if filter.Enabled != nil {
+		data["enabled"] = *filter.Enabled
+		wc = append(wc, "enabled = :enabled")
+	}
+
 	if filter.StartCreatedDate != nil {
 		data["start_date_created"] = filter.StartCreatedDate.UTC()
 		wc = append(wc, "date_created >= :start_date_created")
@@ -36,6 +61,13 @@ func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buf
 		wc = append(wc, "date_created <= :end_date_created")
 	}
 
+	switch {
+	case filter.Deleted != nil && *filter.Deleted:
+		wc = append(wc, "date_deleted IS NOT NULL")
+	default:
+		wc = append(wc, "date_deleted IS NULL")
+	}
+
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
 		buf.WriteString(strings.Join(wc, " AND "))
@@ -12,15 +12,19 @@ import (
 )
 
 type user struct {
-	ID           uuid.UUID      `db:"user_id"`
-	Name         string         `db:"name"`
-	Email        string         `db:"email"`
-	Roles        dbarray.String `db:"roles"`
-	PasswordHash []byte         `db:"password_hash"`
-	Department   sql.NullString `db:"department"`
-	Enabled      bool           `db:"enabled"`
-	DateCreated  time.Time      `db:"date_created"`
-	DateUpdated  time.Time      `db:"date_updated"`
+	ID            uuid.UUID      `db:"user_id"`
+	TenantID      uuid.UUID      `db:"tenant_id"`
+	Name          string         `db:"name"`
+	Email         string         `db:"email"`
+	Roles         dbarray.String `db:"roles"`
+	PasswordHash  []byte         `db:"password_hash"`
+	Department    sql.NullString `db:"department"`
+	Enabled       bool           `db:"enabled"`
+	EmailVerified bool           `db:"email_verified"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+	DateDeleted   sql.NullTime   `db:"date_deleted"`
+	Version       int            `db:"version"`
 }
 
 func toDBUser(bus userbus.User) user {
@@ -31,6 +35,7 @@ func toDBUser(bus userbus.User) user {
 
 	return user{
 		ID:           bus.ID,
+		TenantID:     bus.TenantID,
 		Name:         bus.Name.String(),
 		Email:        bus.Email.Address,
 		Roles:        roles,
@@ -39,9 +44,15 @@ func toDBUser(bus userbus.User) user {
 			String: bus.Department,
 			Valid:  bus.Department != "",
 		},
-		Enabled:     bus.Enabled,
-		DateCreated: bus.DateCreated.UTC(),
-		DateUpdated: bus.DateUpdated.UTC(),
+		Enabled:       bus.Enabled,
+		EmailVerified: bus.EmailVerified,
+		DateCreated:   bus.DateCreated.UTC(),
+		DateUpdated:   bus.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  bus.DateDeleted.UTC(),
+			Valid: !bus.DateDeleted.IsZero(),
+		},
+		Version: bus.Version,
 	}
 }
 
@@ -65,15 +76,22 @@ func toBusUser(db user) (userbus.User, error) {
 	}
 
 	bus := userbus.User{
-		ID:           db.ID,
-		Name:         name,
-		Email:        addr,
-		Roles:        roles,
-		PasswordHash: db.PasswordHash,
-		Enabled:      db.Enabled,
-		Department:   db.Department.String,
-		DateCreated:  db.DateCreated.In(time.Local),
-		DateUpdated:  db.DateUpdated.In(time.Local),
+		ID:            db.ID,
+		TenantID:      db.TenantID,
+		Name:          name,
+		Email:         addr,
+		Roles:         roles,
+		PasswordHash:  db.PasswordHash,
+		Enabled:       db.Enabled,
+		EmailVerified: db.EmailVerified,
+		Department:    db.Department.String,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+		Version:       db.Version,
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"time"
 
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/sqldb/dbarray"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -51,9 +53,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (userbus.Storer, error) {
 func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated)
+		(user_id, tenant_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated, version)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated)`
+		(:user_id, :tenant_id, :name, :email, :password_hash, :roles, :department, :enabled, :email_verified, :date_created, :date_updated, :version)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
@@ -65,7 +67,9 @@ func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
-// Update replaces a user document in the database.
+// Update replaces a user document in the database. The user carries the
+// version being written and the update only happens if the stored user is at
+// the version before it, otherwise ErrVersionConflict is returned.
 func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 	const q = `
 	UPDATE
@@ -77,35 +81,125 @@ func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 		"password_hash" = :password_hash,
 		"department" = :department,
 		"enabled" = :enabled,
-		"date_updated" = :date_updated
+		"email_verified" = :email_verified,
+		"date_updated" = :date_updated,
+		"version" = :version
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND
+		version = :version - 1
+	RETURNING
+		version`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	var dbVer struct {
+		Version int `db:"version"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBUser(usr), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", userbus.ErrVersionConflict)
+		}
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
 			return userbus.ErrUniqueEmail
 		}
-		return fmt.Errorf("namedexeccontext: %w", err)
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 
 	return nil
 }
 
-// Delete removes a user from the database.
+// Delete marks a user as deleted in the database. The user is only deleted
+// if the stored user is at the version before the one specified, otherwise
+// ErrVersionConflict is returned.
 func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	const q = `
-	DELETE FROM
+	UPDATE
+		users
+	SET
+		"date_deleted" = :date_deleted,
+		"version" = :version
+	WHERE
+		user_id = :user_id AND
+		version = :version - 1 AND
+		date_deleted IS NULL
+	RETURNING
+		version`
+
+	var dbVer struct {
+		Version int `db:"version"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBUser(usr), &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", userbus.ErrVersionConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// Restore clears the deleted mark from a user in the database.
+func (s *Store) Restore(ctx context.Context, usr userbus.User) error {
+	const q = `
+	UPDATE
 		users
+	SET
+		"date_deleted" = NULL,
+		"date_updated" = :date_updated,
+		"version" = version + 1
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND
+		date_deleted IS NOT NULL`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", userbus.ErrUniqueEmail)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
 	return nil
 }
 
+// Purge removes the users that were deleted before the specified time from
+// the database along with their products, homes and orders. Users with
+// products on the orders of other users are kept so those orders stay whole.
+// It returns the number of users removed.
+func (s *Store) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	data := struct {
+		DeletedBefore time.Time `db:"deleted_before"`
+	}{
+		DeletedBefore: deletedBefore.UTC(),
+	}
+
+	const q = `
+	WITH purged AS (
+		DELETE FROM
+			users AS u
+		WHERE
+			u.date_deleted < :deleted_before AND
+			NOT EXISTS (
+				SELECT 1 FROM order_items AS oi
+				JOIN products AS p ON p.product_id = oi.product_id
+				JOIN orders AS o ON o.order_id = oi.order_id
+				WHERE p.user_id = u.user_id AND o.user_id <> u.user_id
+			)
+		RETURNING
+			user_id
+	)
+	SELECT
+		count(1)
+	FROM
+		purged`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
 // Query retrieves a list of existing users from the database.
 func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
 	data := map[string]any{
@@ -115,12 +209,17 @@ func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy o
 
 	const q = `
 	SELECT
-		user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+		user_id, tenant_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated, date_deleted, version
 	FROM
 		users`
 
+	cursorClause, err := cursorClause(orderBy, page, data)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBufferString(q)
-	applyFilter(filter, data, buf)
+	applyFilter(filter, data, buf, cursorClause...)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -131,8 +230,8 @@ func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy o
 	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
 
 	var dbUsrs []user
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
-		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
 	}
 
 	return toBusUsers(dbUsrs)
@@ -154,7 +253,7 @@ func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, err
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStructUsingIn(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
@@ -171,11 +270,12 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User,
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, tenant_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated, date_deleted, version
 	FROM
 		users
 	WHERE 
-		user_id = :user_id`
+		user_id = :user_id AND
+		date_deleted IS NULL`
 
 	var dbUsr user
 	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
@@ -198,11 +298,40 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, tenant_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated, date_deleted, version
+	FROM
+		users
+	WHERE
+		email = :email AND
+		date_deleted IS NULL`
+
+	var dbUsr user
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.User{}, fmt.Errorf("db: %w", userbus.ErrNotFound)
+		}
+		return userbus.User{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusUser(dbUsr)
+}
+
+// QueryDeletedByID gets the specified deleted user from the database.
+func (s *Store) QueryDeletedByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+        user_id, tenant_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated, date_deleted, version
 	FROM
 		users
 	WHERE
-		email = :email`
+		user_id = :user_id AND
+		date_deleted IS NOT NULL`
 
 	var dbUsr user
 	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
@@ -214,3 +343,38 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	return toBusUser(dbUsr)
 }
+
+// QueryRoles gets the specified roles that are stored in the database.
+func (s *Store) QueryRoles(ctx context.Context, roles []userbus.Role) ([]userbus.Role, error) {
+	data := struct {
+		Names dbarray.String `db:"names"`
+	}{
+		Names: userbus.ParseRolesToString(roles),
+	}
+
+	const q = `
+	SELECT
+		name
+	FROM
+		roles
+	WHERE
+		name = ANY(:names)`
+
+	var dbRoles []struct {
+		Name string `db:"name"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	known := make([]userbus.Role, len(dbRoles))
+	for i, dbRole := range dbRoles {
+		var err error
+		known[i], err = userbus.ParseRole(dbRole.Name)
+		if err != nil {
+			return nil, fmt.Errorf("parse role: %w", err)
+		}
+	}
+
+	return known, nil
+}
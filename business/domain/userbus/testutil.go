@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net/mail"
+
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/google/uuid"
 )
 
 // TestNewUsers is a helper method for testing.
@@ -16,6 +19,7 @@ func TestNewUsers(n int, role Role) []NewUser {
 		idx++
 
 		nu := NewUser{
+			TenantID:   orgbus.DefaultID,
 			Name:       MustParseName(fmt.Sprintf("Name%d", idx)),
 			Email:      mail.Address{Address: fmt.Sprintf("Email%d@gmail.com", idx)},
 			Roles:      []Role{role},
@@ -29,13 +33,14 @@ func TestNewUsers(n int, role Role) []NewUser {
 	return newUsrs
 }
 
-// TestSeedUsers is a helper method for testing.
+// TestSeedUsers is a helper method for testing. The users are created by the
+// system so there is no actor recorded for them.
 func TestSeedUsers(ctx context.Context, n int, role Role, api *Business) ([]User, error) {
 	newUsrs := TestNewUsers(n, role)
 
 	usrs := make([]User, len(newUsrs))
 	for i, nu := range newUsrs {
-		usr, err := api.Create(ctx, nu)
+		usr, err := api.Create(ctx, uuid.Nil, nu)
 		if err != nil {
 			return nil, fmt.Errorf("seeding user: idx: %d : %w", i, err)
 		}
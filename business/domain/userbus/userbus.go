@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 	"time"
 
+	"github.com/ardanlabs/encore/business/domain/auditbus"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
 	"github.com/ardanlabs/encore/business/sdk/order"
 	"github.com/ardanlabs/encore/business/sdk/page"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
@@ -22,6 +25,8 @@ var (
 	ErrNotFound              = errors.New("user not found")
 	ErrUniqueEmail           = errors.New("email is not unique")
 	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrVersionConflict       = errors.New("user was modified by another request")
+	ErrUnknownRole           = errors.New("role does not exist")
 )
 
 // Storer interface declares the behavior this package needs to perists and
@@ -31,23 +36,30 @@ type Storer interface {
 	Create(ctx context.Context, usr User) error
 	Update(ctx context.Context, usr User) error
 	Delete(ctx context.Context, usr User) error
+	Restore(ctx context.Context, usr User) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
+	QueryDeletedByID(ctx context.Context, userID uuid.UUID) (User, error)
+	QueryRoles(ctx context.Context, roles []Role) ([]Role, error)
 }
 
 // Business manages the set of APIs for user access.
 type Business struct {
 	log      *logger.Logger
 	storer   Storer
+	auditBus *auditbus.Business
 	delegate *delegate.Delegate
 }
 
-// NewBusiness constructs a user business API for use.
-func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+// NewBusiness constructs a user business API for use. The audit and delegate
+// apis are optional when the business is only used for queries.
+func NewBusiness(log *logger.Logger, auditBus *auditbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
 	return &Business{
 		log:      log,
+		auditBus: auditBus,
 		delegate: delegate,
 		storer:   storer,
 	}
@@ -61,9 +73,25 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	auditBus := b.auditBus
+	if auditBus != nil {
+		auditBus, err = auditBus.NewWithTx(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Events are published as part of the transaction so other domains
+	// only hear about changes that were committed.
+	delegate := b.delegate
+	if delegate != nil {
+		delegate = delegate.NewWithTx(tx)
+	}
+
 	bus := Business{
 		log:      b.log,
-		delegate: b.delegate,
+		auditBus: auditBus,
+		delegate: delegate,
 		storer:   storer,
 	}
 
@@ -71,7 +99,11 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 }
 
 // Create adds a new user to the system.
-func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nu NewUser) (User, error) {
+	if err := b.checkRoles(ctx, nu.Roles); err != nil {
+		return User{}, err
+	}
+
 	hash, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return User{}, fmt.Errorf("generatefrompassword: %w", err)
@@ -81,6 +113,7 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 
 	usr := User{
 		ID:           uuid.New(),
+		TenantID:     nu.TenantID,
 		Name:         nu.Name,
 		Email:        nu.Email,
 		PasswordHash: hash,
@@ -89,26 +122,88 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 		Enabled:      true,
 		DateCreated:  now,
 		DateUpdated:  now,
+		Version:      1,
 	}
 
 	if err := b.storer.Create(ctx, usr); err != nil {
 		return User{}, fmt.Errorf("create: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Create, usr.ID, nil, toAuditUser(usr)); err != nil {
+		return User{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeCreated, usr); err != nil {
+		return User{}, err
+	}
+
 	return usr, nil
 }
 
-// Update modifies information about a user.
-func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, error) {
+// Update modifies information about a user. The user must still be at the
+// version it was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Update(ctx context.Context, actorID uuid.UUID, usr User, uu UpdateUser) (User, error) {
+	return b.update(ctx, actorID, auditbus.Actions.Update, usr, uu)
+}
+
+// UpdateRole replaces the roles of a user. The user must still be at the
+// version it was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) UpdateRole(ctx context.Context, actorID uuid.UUID, usr User, roles []Role) (User, error) {
+	uu := UpdateUser{
+		Roles: roles,
+	}
+
+	return b.update(ctx, actorID, auditbus.Actions.UpdateRole, usr, uu)
+}
+
+// VerifyEmail marks the email address of the user as verified. The user must
+// still be at the version it was read with, otherwise ErrVersionConflict is
+// returned.
+func (b *Business) VerifyEmail(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	if usr.EmailVerified {
+		return usr, nil
+	}
+
+	before := toAuditUser(usr)
+
+	usr.EmailVerified = true
+	usr.DateUpdated = time.Now()
+	usr.Version++
+
+	if err := b.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := b.audit(ctx, actorID, auditbus.Actions.Update, usr.ID, before, toAuditUser(usr)); err != nil {
+		return User{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeUpdated, usr); err != nil {
+		return User{}, err
+	}
+
+	return usr, nil
+}
+
+func (b *Business) update(ctx context.Context, actorID uuid.UUID, action auditbus.Action, usr User, uu UpdateUser) (User, error) {
+	before := toAuditUser(usr)
+
 	if uu.Name != nil {
 		usr.Name = *uu.Name
 	}
 
+	// A new email address has to be verified again.
 	if uu.Email != nil {
+		if uu.Email.Address != usr.Email.Address {
+			usr.EmailVerified = false
+		}
 		usr.Email = *uu.Email
 	}
 
 	if uu.Roles != nil {
+		if err := b.checkRoles(ctx, uu.Roles); err != nil {
+			return User{}, err
+		}
 		usr.Roles = uu.Roles
 	}
 
@@ -127,30 +222,122 @@ func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, e
 	if uu.Enabled != nil {
 		usr.Enabled = *uu.Enabled
 	}
+
 	usr.DateUpdated = time.Now()
+	usr.Version++
 
 	if err := b.storer.Update(ctx, usr); err != nil {
 		return User{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, action, usr.ID, before, toAuditUser(usr)); err != nil {
+		return User{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeUpdated, usr); err != nil {
+		return User{}, err
+	}
+
 	// Other domains may need to know when a user is updated so business
-	// logic can be applieb. This represents a delegate call to other domains.
-	if err := b.delegate.Call(ctx, ActionUpdatedData(uu, usr.ID)); err != nil {
+	// logic can be applieb. The other domains don't have to catch up before
+	// the update returns, so the event is published to them.
+	params := ActionUpdatedParms{
+		UserID: usr.ID,
+		UpdateUser: UpdateUser{
+			Enabled: uu.Enabled,
+		},
+	}
+
+	if err := delegate.Publish(ctx, b.delegate, params); err != nil {
 		return User{}, fmt.Errorf("failed to execute `%s` action: %w", ActionUpdated, err)
 	}
 
 	return usr, nil
 }
 
-// Delete removes the specified user.
-func (b *Business) Delete(ctx context.Context, usr User) error {
+// Delete marks the specified user as deleted. The user can be brought back
+// with Restore until it is purged. The user must still be at the version it
+// was read with, otherwise ErrVersionConflict is returned.
+func (b *Business) Delete(ctx context.Context, actorID uuid.UUID, usr User) error {
+	before := toAuditUser(usr)
+
+	usr.DateDeleted = time.Now()
+	usr.Version++
+
 	if err := b.storer.Delete(ctx, usr); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	if err := b.audit(ctx, actorID, auditbus.Actions.Delete, usr.ID, before, toAuditUser(usr)); err != nil {
+		return err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeDeleted, usr); err != nil {
+		return err
+	}
+
+	// Other domains hold data that belongs to this user and need to
+	// remove it along with the user.
+	params := ActionDeletedParms{
+		UserID:      usr.ID,
+		DateDeleted: usr.DateDeleted,
+	}
+
+	if err := delegate.Emit(ctx, b.delegate, params); err != nil {
+		return fmt.Errorf("failed to execute `%s` action: %w", ActionDeleted, err)
+	}
+
 	return nil
 }
 
+// Restore brings back the specified deleted user.
+func (b *Business) Restore(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	before := toAuditUser(usr)
+	dateDeleted := usr.DateDeleted
+
+	usr.DateDeleted = time.Time{}
+	usr.DateUpdated = time.Now()
+	usr.Version++
+
+	if err := b.storer.Restore(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("restore: %w", err)
+	}
+
+	if err := b.audit(ctx, actorID, auditbus.Actions.Restore, usr.ID, before, toAuditUser(usr)); err != nil {
+		return User{}, err
+	}
+
+	if err := b.publishEvent(ctx, events.TypeRestored, usr); err != nil {
+		return User{}, err
+	}
+
+	// Other domains need to restore the data that was deleted along with
+	// this user.
+	params := ActionRestoredParms{
+		UserID:      usr.ID,
+		DateDeleted: dateDeleted,
+	}
+
+	if err := delegate.Emit(ctx, b.delegate, params); err != nil {
+		return User{}, fmt.Errorf("failed to execute `%s` action: %w", ActionRestored, err)
+	}
+
+	return usr, nil
+}
+
+// Purge permanently removes the users that were deleted longer ago than the
+// specified retention window. It returns the number of users removed.
+func (b *Business) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	deletedBefore := time.Now().Add(-retention)
+
+	n, err := b.storer.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: deletedBefore[%s]: %w", deletedBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
 // Query retrieves a list of existing users.
 func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error) {
 	users, err := b.storer.Query(ctx, filter, orderBy, page)
@@ -186,6 +373,16 @@ func (b *Business) QueryByEmail(ctx context.Context, email mail.Address) (User,
 	return user, nil
 }
 
+// QueryDeletedByID finds the deleted user by the specified ID.
+func (b *Business) QueryDeletedByID(ctx context.Context, userID uuid.UUID) (User, error) {
+	user, err := b.storer.QueryDeletedByID(ctx, userID)
+	if err != nil {
+		return User{}, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return user, nil
+}
+
 // Authenticate finds a user by their email and verifies their passworb. On
 // success it returns a Claims User representing this user. The claims can be
 // used to generate a token for future authentication.
@@ -201,3 +398,21 @@ func (b *Business) Authenticate(ctx context.Context, email mail.Address, passwor
 
 	return usr, nil
 }
+
+// =============================================================================
+
+// checkRoles returns ErrUnknownRole when any of the roles isn't stored.
+func (b *Business) checkRoles(ctx context.Context, roles []Role) error {
+	known, err := b.storer.QueryRoles(ctx, roles)
+	if err != nil {
+		return fmt.Errorf("queryroles: %w", err)
+	}
+
+	for _, role := range roles {
+		if !slices.ContainsFunc(known, role.Equal) {
+			return fmt.Errorf("role[%s]: %w", role, ErrUnknownRole)
+		}
+	}
+
+	return nil
+}
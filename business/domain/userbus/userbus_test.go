@@ -2,16 +2,20 @@ package userbus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 	"sort"
 	"testing"
 	"time"
 
 	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/crypto/bcrypt"
@@ -35,15 +39,16 @@ func Test_User(t *testing.T) {
 	// -------------------------------------------------------------------------
 
 	unitest.Run(t, query(db.BusDomain, sd), "query")
-	unitest.Run(t, create(db.BusDomain), "create")
+	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+	unitest.Run(t, restore(db.BusDomain, sd), "restore")
 }
 
 // =============================================================================
 
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := sqldb.WithSystem(context.Background())
 
 	usrs, err := userbus.TestSeedUsers(ctx, 2, userbus.Roles.Admin, busDomain.User)
 	if err != nil {
@@ -100,6 +105,15 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		return usrs[i].ID.String() <= usrs[j].ID.String()
 	})
 
+	var adms []userbus.User
+	for _, usr := range usrs {
+		if slices.Contains(usr.Roles, userbus.Roles.Admin) {
+			adms = append(adms, usr)
+		}
+	}
+	department := "department"
+	enabled := true
+
 	table := []unitest.Table{
 		{
 			Name:    "all",
@@ -137,6 +151,45 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "roles",
+			ExpResp: adms,
+			ExcFunc: func(ctx context.Context) any {
+				filter := userbus.QueryFilter{
+					Name:       dbtest.UserNamePointer("Name"),
+					Roles:      []userbus.Role{userbus.Roles.Admin},
+					Department: &department,
+					Enabled:    &enabled,
+				}
+
+				resp, err := busDomain.User.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]userbus.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]userbus.User)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
 		{
 			Name:    "byid",
 			ExpResp: sd.Users[0].User,
@@ -172,21 +225,24 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	return table
 }
 
-func create(busDomain dbtest.BusDomain) []unitest.Table {
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	email, _ := mail.ParseAddress("bill@ardanlabs.com")
 
 	table := []unitest.Table{
 		{
 			Name: "basic",
 			ExpResp: userbus.User{
+				TenantID:   orgbus.DefaultID,
 				Name:       userbus.MustParseName("Bill Kennedy"),
 				Email:      *email,
 				Roles:      []userbus.Role{userbus.Roles.Admin},
 				Department: "IT",
 				Enabled:    true,
+				Version:    1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nu := userbus.NewUser{
+					TenantID:   orgbus.DefaultID,
 					Name:       userbus.MustParseName("Bill Kennedy"),
 					Email:      *email,
 					Roles:      []userbus.Role{userbus.Roles.Admin},
@@ -194,7 +250,7 @@ func create(busDomain dbtest.BusDomain) []unitest.Table {
 					Password:   "123",
 				}
 
-				resp, err := busDomain.User.Create(ctx, nu)
+				resp, err := busDomain.User.Create(ctx, sd.Admins[0].ID, nu)
 				if err != nil {
 					return err
 				}
@@ -221,6 +277,24 @@ func create(busDomain dbtest.BusDomain) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "unknown-role",
+			ExpResp: userbus.ErrUnknownRole,
+			ExcFunc: func(ctx context.Context) any {
+				nu := userbus.TestNewUsers(1, userbus.MustParseRole("NOT_STORED"))[0]
+
+				_, err := busDomain.User.Create(ctx, sd.Admins[0].ID, nu)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -234,12 +308,14 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name: "basic",
 			ExpResp: userbus.User{
 				ID:          sd.Users[0].ID,
+				TenantID:    sd.Users[0].TenantID,
 				Name:        userbus.MustParseName("Jack Kennedy"),
 				Email:       *email,
 				Roles:       []userbus.Role{userbus.Roles.Admin},
 				Department:  "IT",
 				Enabled:     true,
 				DateCreated: sd.Users[0].DateCreated,
+				Version:     2,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				uu := userbus.UpdateUser{
@@ -250,7 +326,7 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					Password:   dbtest.StringPointer("1234"),
 				}
 
-				resp, err := busDomain.User.Update(ctx, sd.Users[0].User, uu)
+				resp, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu)
 				if err != nil {
 					return err
 				}
@@ -275,6 +351,26 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "stale",
+			ExpResp: userbus.ErrVersionConflict,
+			ExcFunc: func(ctx context.Context) any {
+				uu := userbus.UpdateUser{
+					Name: dbtest.UserNamePointer("Jill Kennedy"),
+				}
+
+				_, err := busDomain.User.Update(ctx, sd.Admins[0].ID, sd.Users[0].User, uu)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -286,7 +382,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "user",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.User.Delete(ctx, sd.Users[1].User); err != nil {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Users[1].User); err != nil {
 					return err
 				}
 
@@ -300,7 +396,7 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 			Name:    "admin",
 			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
-				if err := busDomain.User.Delete(ctx, sd.Admins[1].User); err != nil {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Admins[1].User); err != nil {
 					return err
 				}
 
@@ -314,3 +410,136 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func restore(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[1].User
+
+	table := []unitest.Table{
+		{
+			Name:    "hidden",
+			ExpResp: userbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.QueryByID(ctx, usr.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "deleted",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := userbus.QueryFilter{
+					ID:      &usr.ID,
+					Deleted: dbtest.BoolPointer(true),
+				}
+
+				resp, err := busDomain.User.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "basic",
+			ExpResp: usr,
+			ExcFunc: func(ctx context.Context) any {
+				dltUsr, err := busDomain.User.QueryDeletedByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Restore(ctx, sd.Admins[0].ID, dltUsr); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.User.QueryByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(userbus.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(userbus.User)
+
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Version = gotResp.Version
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "email-reused",
+			ExpResp: userbus.ErrUniqueEmail,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, usr); err != nil {
+					return err
+				}
+
+				nu := userbus.NewUser{
+					TenantID:   orgbus.DefaultID,
+					Name:       userbus.MustParseName("Jill Kennedy"),
+					Email:      usr.Email,
+					Roles:      []userbus.Role{userbus.Roles.User},
+					Department: "IT",
+					Password:   "123",
+				}
+
+				if _, err := busDomain.User.Create(ctx, sd.Admins[0].ID, nu); err != nil {
+					return err
+				}
+
+				dltUsr, err := busDomain.User.QueryDeletedByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.User.Restore(ctx, sd.Admins[0].ID, dltUsr)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotErr, exists := got.(error)
+				if !exists || !errors.Is(gotErr, exp.(error)) {
+					return fmt.Sprintf("got %v, expected %v", got, exp)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.User.Purge(ctx, 0)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
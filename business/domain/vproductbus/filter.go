@@ -8,10 +8,24 @@ import (
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// When no Status is provided only active products are returned. Name and
+// UserName match any product whose value contains the filter value and
+// NamePrefix any product whose name starts with it, all ignoring case. The
+// Min and Max fields are inclusive and the cost fields only compare the
+// amount, not the currency. Search matches products whose name or
+// user name match the full text search.
 type QueryFilter struct {
-	ID       *uuid.UUID
-	Name     *productbus.Name
-	Cost     *float64
-	Quantity *int
-	UserName *userbus.Name
+	ID          *uuid.UUID
+	IDs         []uuid.UUID
+	Name        *productbus.Name
+	NamePrefix  *productbus.Name
+	Cost        *productbus.Money
+	MinCost     *productbus.Money
+	MaxCost     *productbus.Money
+	Quantity    *int
+	MinQuantity *int
+	MaxQuantity *int
+	Status      *productbus.Status
+	UserName    *userbus.Name
+	Search      *string
 }
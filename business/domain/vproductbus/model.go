@@ -13,8 +13,9 @@ type Product struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	Name        productbus.Name
-	Cost        float64
+	Cost        productbus.Money
 	Quantity    int
+	Status      productbus.Status
 	DateCreated time.Time
 	DateUpdated time.Time
 	UserName    userbus.Name
@@ -5,36 +5,78 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
-func (s *Store) applyFilter(filter vproductbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(filter vproductbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
 	if filter.ID != nil {
 		data["product_id"] = *filter.ID
 		wc = append(wc, "product_id = :product_id")
 	}
 
+	if len(filter.IDs) > 0 {
+		data["product_ids"] = filter.IDs
+		wc = append(wc, "product_id IN (:product_ids)")
+	}
+
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		data["name"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(filter.Name.String()))
+		wc = append(wc, "name ILIKE :name")
+	}
+
+	if filter.NamePrefix != nil {
+		data["name_prefix"] = fmt.Sprintf("%s%%", sqldb.EscapeLike(filter.NamePrefix.String()))
+		wc = append(wc, "name ILIKE :name_prefix")
 	}
 
 	if filter.Cost != nil {
-		data["cost"] = *filter.Cost
+		data["cost"] = filter.Cost.String()
 		wc = append(wc, "cost = :cost")
 	}
 
+	if filter.MinCost != nil {
+		data["min_cost"] = filter.MinCost.String()
+		wc = append(wc, "cost >= :min_cost")
+	}
+
+	if filter.MaxCost != nil {
+		data["max_cost"] = filter.MaxCost.String()
+		wc = append(wc, "cost <= :max_cost")
+	}
+
 	if filter.Quantity != nil {
 		data["quantity"] = *filter.Quantity
 		wc = append(wc, "quantity = :quantity")
 	}
 
+	if filter.MinQuantity != nil {
+		data["min_quantity"] = *filter.MinQuantity
+		wc = append(wc, "quantity >= :min_quantity")
+	}
+
+	if filter.MaxQuantity != nil {
+		data["max_quantity"] = *filter.MaxQuantity
+		wc = append(wc, "quantity <= :max_quantity")
+	}
+
 	if filter.UserName != nil {
-		data["user_name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "user_name LIKE :user_name")
+		data["user_name"] = fmt.Sprintf("%%%s%%", sqldb.EscapeLike(filter.UserName.String()))
+		wc = append(wc, "user_name ILIKE :user_name")
+	}
+
+	if filter.Search != nil {
+		data["search"] = *filter.Search
+		wc = append(wc, "search @@ websearch_to_tsquery('english', :search)")
+	}
+
+	status := productbus.Statuses.Active
+	if filter.Status != nil {
+		status = *filter.Status
 	}
+	data["status"] = status.String()
+	wc = append(wc, "status = :status")
 
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
@@ -14,8 +14,10 @@ type product struct {
 	ID          uuid.UUID `db:"product_id"`
 	UserID      uuid.UUID `db:"user_id"`
 	Name        string    `db:"name"`
-	Cost        float64   `db:"cost"`
+	Cost        string    `db:"cost"`
+	Currency    string    `db:"currency"`
 	Quantity    int       `db:"quantity"`
+	Status      string    `db:"status"`
 	DateCreated time.Time `db:"date_created"`
 	DateUpdated time.Time `db:"date_updated"`
 	UserName    string    `db:"user_name"`
@@ -32,12 +34,23 @@ func toBusProduct(db product) (vproductbus.Product, error) {
 		return vproductbus.Product{}, fmt.Errorf("parse name: %w", err)
 	}
 
+	status, err := productbus.ParseStatus(db.Status)
+	if err != nil {
+		return vproductbus.Product{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	cost, err := productbus.ParseMoney(db.Cost, db.Currency)
+	if err != nil {
+		return vproductbus.Product{}, fmt.Errorf("parse cost: %w", err)
+	}
+
 	bus := vproductbus.Product{
 		ID:          db.ID,
 		UserID:      db.UserID,
 		Name:        name,
-		Cost:        db.Cost,
+		Cost:        cost,
 		Quantity:    db.Quantity,
+		Status:      status,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
 		UserName:    userName,
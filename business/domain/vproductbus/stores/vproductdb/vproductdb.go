@@ -41,15 +41,22 @@ func (s *Store) Query(ctx context.Context, filter vproductbus.QueryFilter, order
 		user_id,
 		name,
 		cost,
+		currency,
 		quantity,
+		status,
 		date_created,
 		date_updated,
 		user_name
 	FROM
 		view_products`
 
+	cursorClause, err := cursorClause(orderBy, page, data)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	s.applyFilter(filter, data, buf, cursorClause...)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -60,8 +67,8 @@ func (s *Store) Query(ctx context.Context, filter vproductbus.QueryFilter, order
 	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
 
 	var dnPrd []product
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dnPrd); err != nil {
-		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, buf.String(), data, &dnPrd); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
 	}
 
 	prd, err := toBusProducts(dnPrd)
@@ -88,7 +95,7 @@ func (s *Store) Count(ctx context.Context, filter vproductbus.QueryFilter) (int,
 	var count struct {
 		Count int `db:"count"`
 	}
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+	if err := sqldb.NamedQueryStructUsingIn(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
 		return 0, fmt.Errorf("db: %w", err)
 	}
 
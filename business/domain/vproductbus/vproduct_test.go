@@ -13,6 +13,7 @@ import (
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/sdk/dbtest"
 	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/business/sdk/unitest"
 	"github.com/google/go-cmp/cmp"
 )
@@ -40,7 +41,7 @@ func Test_Product(t *testing.T) {
 // =============================================================================
 
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := sqldb.WithSystem(context.Background())
 
 	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, busDomain.User)
 	if err != nil {
@@ -93,6 +94,7 @@ func toVProduct(usr userbus.User, prd productbus.Product) vproductbus.Product {
 		Name:        prd.Name,
 		Cost:        prd.Cost,
 		Quantity:    prd.Quantity,
+		Status:      prd.Status,
 		DateCreated: prd.DateCreated,
 		DateUpdated: prd.DateUpdated,
 		UserName:    usr.Name,
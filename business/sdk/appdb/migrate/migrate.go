@@ -38,6 +38,12 @@ func Seed(ctx context.Context, db *sqlx.DB) (err error) {
 		}
 	}()
 
+	// The seed isn't inserted on behalf of a tenant, so it acts for the
+	// system to get past the row level security policies.
+	if _, err := tx.Exec(`SELECT set_config('app.system', 'on', true)`); err != nil {
+		return fmt.Errorf("set system: %w", err)
+	}
+
 	if _, err := tx.Exec(seedDoc); err != nil {
 		return fmt.Errorf("exec: %w", err)
 	}
@@ -0,0 +1,104 @@
+-- Every organization is a tenant. The existing rows belong to the default
+-- organization which is also the operator of the deployment.
+CREATE TABLE organizations (
+	org_id       UUID      NOT NULL,
+	name         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (org_id)
+);
+
+CREATE UNIQUE INDEX organizations_name_idx ON organizations (name);
+
+INSERT INTO organizations (org_id, name, date_created, date_updated) VALUES
+	('3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10', 'Default', NOW(), NOW());
+
+-- The tenant of the request is held in the app.tenant_id setting for the
+-- transaction. When it isn't set the session is acting for the system and
+-- every row is visible.
+CREATE FUNCTION app_tenant() RETURNS UUID AS $$
+	SELECT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+$$ LANGUAGE SQL STABLE;
+
+CREATE FUNCTION app_tenant_visible(tenant UUID) RETURNS BOOLEAN AS $$
+	SELECT app_tenant() IS NULL OR tenant = app_tenant()
+$$ LANGUAGE SQL STABLE;
+
+ALTER TABLE users ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE products ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE homes ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE orders ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE audit_log ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+
+CREATE INDEX users_tenant_id_idx ON users (tenant_id);
+CREATE INDEX products_tenant_id_idx ON products (tenant_id);
+CREATE INDEX homes_tenant_id_idx ON homes (tenant_id);
+CREATE INDEX orders_tenant_id_idx ON orders (tenant_id);
+CREATE INDEX audit_log_tenant_id_idx ON audit_log (tenant_id);
+
+-- Row level security holds the isolation even when a query forgets to filter
+-- by tenant. FORCE applies the policies to the owner of the tables as well.
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON users
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON products
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE homes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE homes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON homes
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE orders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE orders FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON orders
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_log
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+-- The view has to check the policies as the caller, not as its owner.
+DROP VIEW IF EXISTS view_products;
+
+CREATE VIEW view_products WITH (security_invoker = true) AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+    p.currency,
+	p.quantity,
+    p.status,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name,
+    p.search || to_tsvector('english', u.name) AS search,
+    p.tenant_id
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id
+WHERE
+    p.date_deleted IS NULL;
@@ -0,0 +1,31 @@
+-- Refresh tokens are stored as a hash of the token so the table can't be used
+-- to sign in. Every rotation adds a token to the family of the token it
+-- replaced so the whole family can be revoked when a token is reused.
+CREATE TABLE refresh_tokens (
+	token_id     UUID      NOT NULL,
+	family_id    UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	token_hash   BYTEA     NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_used    TIMESTAMP NULL,
+	date_revoked TIMESTAMP NULL,
+
+	PRIMARY KEY (token_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX refresh_tokens_token_hash_idx ON refresh_tokens (token_hash);
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+-- The ids of access tokens that were revoked before they expired. A row is
+-- only needed until the token expires.
+CREATE TABLE revoked_tokens (
+	jti          TEXT      NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (jti)
+);
+
+CREATE INDEX revoked_tokens_date_expires_idx ON revoked_tokens (date_expires);
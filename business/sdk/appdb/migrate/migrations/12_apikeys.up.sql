@@ -0,0 +1,22 @@
+-- API keys let machine clients call the service as the user that minted
+-- them. Only the hash of the key is stored. The scopes are the roles the
+-- key acts with and can't go beyond the roles of the user.
+CREATE TABLE api_keys (
+	api_key_id     UUID      NOT NULL,
+	user_id        UUID      NOT NULL,
+	name           TEXT      NOT NULL,
+	prefix         TEXT      NOT NULL,
+	key_hash       BYTEA     NOT NULL,
+	scopes         TEXT[]    NOT NULL,
+	request_count  BIGINT    NOT NULL DEFAULT 0,
+	date_expires   TIMESTAMP NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_last_used TIMESTAMP NULL,
+	date_revoked   TIMESTAMP NULL,
+
+	PRIMARY KEY (api_key_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX api_keys_key_hash_idx ON api_keys (key_hash);
+CREATE UNIQUE INDEX api_keys_user_id_name_idx ON api_keys (user_id, name) WHERE date_revoked IS NULL;
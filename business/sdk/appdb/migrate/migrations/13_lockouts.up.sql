@@ -0,0 +1,13 @@
+-- Failed sign in attempts are tracked per email and per client ip. Once a
+-- subject fails too often it is locked out for a time that doubles with
+-- every lockout.
+CREATE TABLE lockouts (
+	kind              TEXT      NOT NULL,
+	subject           TEXT      NOT NULL,
+	failures          INT       NOT NULL,
+	lockouts          INT       NOT NULL,
+	date_last_failure TIMESTAMP NOT NULL,
+	date_locked_until TIMESTAMP NULL,
+
+	PRIMARY KEY (kind, subject)
+);
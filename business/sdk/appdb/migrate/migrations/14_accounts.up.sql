@@ -0,0 +1,25 @@
+-- Users prove they own their email address by using a token that was mailed
+-- to it. Existing users were created before verification so they are trusted.
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET email_verified = TRUE;
+
+-- Tokens mailed to users to reset their password or verify their email. Only
+-- the hash of the token is stored and a token can only be used once. The
+-- email is the address the token was sent to.
+CREATE TABLE account_tokens (
+	token_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	purpose      TEXT      NOT NULL,
+	email        TEXT      NOT NULL,
+	token_hash   BYTEA     NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_used    TIMESTAMP NULL,
+
+	PRIMARY KEY (token_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX account_tokens_token_hash_idx ON account_tokens (token_hash);
+CREATE INDEX account_tokens_user_id_idx ON account_tokens (user_id, purpose);
@@ -0,0 +1,11 @@
+-- Delegate events delivered through pubsub can be delivered more than once.
+-- A row records that a handler claimed or completed an event so a
+-- redelivered event doesn't run the handler again. A claim that was never
+-- completed can be taken over once it's old enough.
+CREATE TABLE delegate_handled (
+	idempotency_key TEXT      NOT NULL,
+	date_claimed    TIMESTAMP NOT NULL,
+	date_completed  TIMESTAMP NULL,
+
+	PRIMARY KEY (idempotency_key)
+);
@@ -0,0 +1,16 @@
+-- Delegate events are written to the outbox in the same transaction as the
+-- change that raised them. A relay publishes the pending events and marks
+-- them as sent, so an event is never lost or sent for a change that was
+-- rolled back.
+CREATE TABLE outbox (
+	event_id     TEXT      NOT NULL,
+	domain       TEXT      NOT NULL,
+	action       TEXT      NOT NULL,
+	raw_params   BYTEA     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_sent    TIMESTAMP NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (date_created) WHERE date_sent IS NULL;
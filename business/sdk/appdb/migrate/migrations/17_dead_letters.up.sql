@@ -0,0 +1,19 @@
+-- A delegate call keeps the event as a dead letter for every handler that
+-- still failed after the attempts of its retry policy, so the handler can be
+-- replayed once the problem is fixed.
+CREATE TABLE delegate_dead_letters (
+	dead_letter_id UUID      NOT NULL,
+	event_id       TEXT      NOT NULL,
+	domain         TEXT      NOT NULL,
+	action         TEXT      NOT NULL,
+	raw_params     BYTEA     NOT NULL,
+	handler        TEXT      NOT NULL,
+	attempts       INT       NOT NULL,
+	error          TEXT      NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_failed    TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (dead_letter_id)
+);
+
+CREATE INDEX delegate_dead_letters_date_created_idx ON delegate_dead_letters (date_created);
@@ -0,0 +1,4 @@
+-- Delegate events carry the schema version their parameters were encoded
+-- with. The events stored before versioning were encoded with version 1.
+ALTER TABLE outbox ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE delegate_dead_letters ADD COLUMN version INT NOT NULL DEFAULT 1;
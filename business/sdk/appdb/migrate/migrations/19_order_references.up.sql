@@ -0,0 +1,39 @@
+-- Orders keep pointing at the homes and products they were placed for, so a
+-- purge can't leave line items behind for rows that no longer exist. Orders
+-- left behind by earlier purges are history and are never removed here. The
+-- references are added NOT VALID so they only hold for new rows, and the
+-- orphans are reported for an operator to review. Once they are dealt with,
+-- the references can be checked with:
+--
+--   ALTER TABLE orders VALIDATE CONSTRAINT orders_home_id_fkey;
+--   ALTER TABLE order_items VALIDATE CONSTRAINT order_items_product_id_fkey;
+DO $$
+DECLARE
+	orphan_orders INT;
+	orphan_items  INT;
+BEGIN
+	SELECT count(1) INTO orphan_orders
+	FROM orders AS o
+	WHERE NOT EXISTS (SELECT 1 FROM homes AS h WHERE h.home_id = o.home_id);
+
+	SELECT count(1) INTO orphan_items
+	FROM order_items AS oi
+	WHERE NOT EXISTS (SELECT 1 FROM products AS p WHERE p.product_id = oi.product_id);
+
+	IF orphan_orders > 0 OR orphan_items > 0 THEN
+		RAISE WARNING 'order references: % orders reference a missing home and % order items reference a missing product',
+			orphan_orders, orphan_items;
+	END IF;
+END
+$$;
+
+ALTER TABLE orders
+	ADD CONSTRAINT orders_home_id_fkey
+	FOREIGN KEY (home_id) REFERENCES homes(home_id) NOT VALID;
+
+ALTER TABLE order_items
+	ADD CONSTRAINT order_items_product_id_fkey
+	FOREIGN KEY (product_id) REFERENCES products(product_id) NOT VALID;
+
+CREATE INDEX orders_home_id_idx ON orders (home_id);
+CREATE INDEX order_items_product_id_idx ON order_items (product_id);
@@ -0,0 +1,20 @@
+-- The permissions that satisfy each rule of the authorization policy. A
+-- permission in the subject scope only satisfies the rule when users act on
+-- their own data.
+CREATE TABLE rule_permissions (
+	rule       TEXT NOT NULL,
+	permission TEXT NOT NULL,
+	scope      TEXT NOT NULL,
+
+	PRIMARY KEY (rule, permission)
+);
+
+INSERT INTO rule_permissions (rule, permission, scope) VALUES
+	('rule_any', 'admin', 'all'),
+	('rule_any', 'user', 'all'),
+	('rule_admin_only', 'admin', 'all'),
+	('rule_user_only', 'user', 'all'),
+	('rule_admin_or_subject', 'admin', 'all'),
+	('rule_admin_or_subject', 'user', 'subject'),
+	('rule_verified', 'admin', 'all'),
+	('rule_verified', 'user', 'all');
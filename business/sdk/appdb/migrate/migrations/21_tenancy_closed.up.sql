@@ -0,0 +1,169 @@
+-- The policies fail closed. A session that doesn't set a tenant sees none of
+-- the rows unless it explicitly acts for the system, which only the crons,
+-- event handlers, sign in and seeding do.
+CREATE FUNCTION app_system() RETURNS BOOLEAN AS $$
+	SELECT COALESCE(current_setting('app.system', true), '') = 'on'
+$$ LANGUAGE SQL STABLE;
+
+CREATE OR REPLACE FUNCTION app_tenant_visible(tenant UUID) RETURNS BOOLEAN AS $$
+	SELECT CASE
+		WHEN app_tenant() IS NOT NULL THEN tenant = app_tenant()
+		ELSE app_system()
+	END
+$$ LANGUAGE SQL STABLE;
+
+-- The backfill below has to see the rows of every tenant.
+SELECT set_config('app.system', 'on', true);
+
+-- The rows that belong to a user or an order take the tenant of their parent
+-- when the session isn't scoped to a tenant. The parent is read under the
+-- policies, so a row can't be attached to a parent of another tenant.
+CREATE FUNCTION tenant_from_user() RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.tenant_id IS NULL THEN
+		NEW.tenant_id := COALESCE(app_tenant(), (SELECT u.tenant_id FROM users AS u WHERE u.user_id = NEW.user_id));
+	END IF;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION tenant_from_order() RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.tenant_id IS NULL THEN
+		NEW.tenant_id := COALESCE(app_tenant(), (SELECT o.tenant_id FROM orders AS o WHERE o.order_id = NEW.order_id));
+	END IF;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- A lockout of an email belongs to the tenant of the user with that email.
+-- A lockout of an ip address, or of an email nobody uses, belongs to the
+-- default organization.
+CREATE FUNCTION tenant_from_subject() RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.tenant_id IS NULL THEN
+		NEW.tenant_id := COALESCE(
+			app_tenant(),
+			(SELECT u.tenant_id FROM users AS u WHERE NEW.kind = 'EMAIL' AND u.email = NEW.subject),
+			'3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10'
+		);
+	END IF;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- -----------------------------------------------------------------------------
+
+ALTER TABLE order_items ADD COLUMN tenant_id UUID NULL REFERENCES organizations(org_id);
+UPDATE order_items AS oi SET tenant_id = o.tenant_id FROM orders AS o WHERE o.order_id = oi.order_id;
+ALTER TABLE order_items ALTER COLUMN tenant_id SET NOT NULL;
+CREATE TRIGGER order_items_tenant BEFORE INSERT ON order_items
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_order();
+
+ALTER TABLE refresh_tokens ADD COLUMN tenant_id UUID NULL REFERENCES organizations(org_id);
+UPDATE refresh_tokens AS t SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = t.user_id;
+ALTER TABLE refresh_tokens ALTER COLUMN tenant_id SET NOT NULL;
+CREATE TRIGGER refresh_tokens_tenant BEFORE INSERT ON refresh_tokens
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_user();
+
+ALTER TABLE api_keys ADD COLUMN tenant_id UUID NULL REFERENCES organizations(org_id);
+UPDATE api_keys AS k SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = k.user_id;
+ALTER TABLE api_keys ALTER COLUMN tenant_id SET NOT NULL;
+CREATE TRIGGER api_keys_tenant BEFORE INSERT ON api_keys
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_user();
+
+ALTER TABLE account_tokens ADD COLUMN tenant_id UUID NULL REFERENCES organizations(org_id);
+UPDATE account_tokens AS t SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = t.user_id;
+ALTER TABLE account_tokens ALTER COLUMN tenant_id SET NOT NULL;
+CREATE TRIGGER account_tokens_tenant BEFORE INSERT ON account_tokens
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_user();
+
+ALTER TABLE lockouts ADD COLUMN tenant_id UUID NULL REFERENCES organizations(org_id);
+UPDATE lockouts AS l SET tenant_id = COALESCE(
+	(SELECT u.tenant_id FROM users AS u WHERE l.kind = 'EMAIL' AND u.email = l.subject),
+	'3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10'
+);
+ALTER TABLE lockouts ALTER COLUMN tenant_id SET NOT NULL;
+CREATE TRIGGER lockouts_tenant BEFORE INSERT ON lockouts
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_subject();
+
+-- The rows that don't belong to a user or an order take the tenant of the
+-- session. The system works for the default organization.
+ALTER TABLE revoked_tokens ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE delegate_handled ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE outbox ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+ALTER TABLE delegate_dead_letters ADD COLUMN tenant_id UUID NOT NULL
+	DEFAULT COALESCE(app_tenant(), '3f0ea5b1-6a83-4d1f-9c3e-5b2a4d7c9e10')
+	REFERENCES organizations(org_id);
+
+CREATE INDEX order_items_tenant_id_idx ON order_items (tenant_id);
+CREATE INDEX refresh_tokens_tenant_id_idx ON refresh_tokens (tenant_id);
+CREATE INDEX api_keys_tenant_id_idx ON api_keys (tenant_id);
+CREATE INDEX account_tokens_tenant_id_idx ON account_tokens (tenant_id);
+CREATE INDEX lockouts_tenant_id_idx ON lockouts (tenant_id);
+CREATE INDEX revoked_tokens_tenant_id_idx ON revoked_tokens (tenant_id);
+CREATE INDEX delegate_handled_tenant_id_idx ON delegate_handled (tenant_id);
+CREATE INDEX outbox_tenant_id_idx ON outbox (tenant_id);
+CREATE INDEX delegate_dead_letters_tenant_id_idx ON delegate_dead_letters (tenant_id);
+
+-- -----------------------------------------------------------------------------
+
+ALTER TABLE order_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE order_items FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON order_items
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE refresh_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE refresh_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON refresh_tokens
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE account_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE account_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON account_tokens
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE lockouts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE lockouts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON lockouts
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE revoked_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE revoked_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON revoked_tokens
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE delegate_handled ENABLE ROW LEVEL SECURITY;
+ALTER TABLE delegate_handled FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON delegate_handled
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
+
+ALTER TABLE delegate_dead_letters ENABLE ROW LEVEL SECURITY;
+ALTER TABLE delegate_dead_letters FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON delegate_dead_letters
+	USING (app_tenant_visible(tenant_id))
+	WITH CHECK (app_tenant_visible(tenant_id));
@@ -0,0 +1,9 @@
+-- A handler records that it handled an event in the transaction it makes its
+-- changes with, so a row only exists once the handler committed. The claims
+-- that were never completed are dropped so their events are handled again.
+SELECT set_config('app.system', 'on', true);
+
+DELETE FROM delegate_handled WHERE date_completed IS NULL;
+
+ALTER TABLE delegate_handled DROP COLUMN date_claimed;
+ALTER TABLE delegate_handled ALTER COLUMN date_completed SET NOT NULL;
@@ -0,0 +1,11 @@
+-- The events with the same ordering key are relayed in the order they were
+-- written. The sequence records that order, since the creation dates of
+-- events written close together can be the same. The sequence is taken at
+-- insert time, so the writers of a key hold a lock on it until they commit
+-- and the sequence follows the commit order.
+ALTER TABLE outbox ADD COLUMN ordering_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox ADD COLUMN seq BIGINT GENERATED BY DEFAULT AS IDENTITY;
+
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (seq) WHERE date_sent IS NULL;
+CREATE INDEX outbox_pending_key_idx ON outbox (ordering_key, seq) WHERE date_sent IS NULL;
@@ -0,0 +1,22 @@
+-- An audit record written while acting for the system, like a password reset
+-- or an event handler, takes the tenant of the entity it's about instead of
+-- the default organization. The entity is read under the policies, so a
+-- record can't be attached to an entity of another tenant.
+CREATE FUNCTION tenant_from_entity() RETURNS TRIGGER AS $$
+BEGIN
+	IF app_tenant() IS NULL THEN
+		NEW.tenant_id := COALESCE(
+			CASE NEW.entity
+				WHEN 'USER'    THEN (SELECT u.tenant_id FROM users AS u WHERE u.user_id = NEW.entity_id)
+				WHEN 'PRODUCT' THEN (SELECT p.tenant_id FROM products AS p WHERE p.product_id = NEW.entity_id)
+				WHEN 'HOME'    THEN (SELECT h.tenant_id FROM homes AS h WHERE h.home_id = NEW.entity_id)
+			END,
+			NEW.tenant_id
+		);
+	END IF;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_tenant BEFORE INSERT ON audit_log
+	FOR EACH ROW EXECUTE FUNCTION tenant_from_entity();
@@ -0,0 +1,20 @@
+CREATE TABLE orders (
+	order_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	home_id      UUID      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (order_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE order_items (
+	order_id   UUID           NOT NULL,
+	product_id UUID           NOT NULL,
+	quantity   INT            NOT NULL,
+	cost       NUMERIC(10, 2) NOT NULL,
+
+	PRIMARY KEY (order_id, product_id),
+	FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+ALTER TABLE products ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE homes ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE';
+
+CREATE INDEX products_user_id_status_idx ON products (user_id, status);
+CREATE INDEX homes_user_id_status_idx ON homes (user_id, status);
+
+DROP VIEW IF EXISTS view_products;
+
+CREATE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+	p.quantity,
+    p.status,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id;
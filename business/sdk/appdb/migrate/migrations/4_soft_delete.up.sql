@@ -0,0 +1,28 @@
+ALTER TABLE users ADD COLUMN date_deleted TIMESTAMP NULL;
+ALTER TABLE products ADD COLUMN date_deleted TIMESTAMP NULL;
+ALTER TABLE homes ADD COLUMN date_deleted TIMESTAMP NULL;
+
+-- A deleted user keeps their email until they are purged, so uniqueness is
+-- only enforced across the users that aren't deleted.
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_email_key ON users (email) WHERE date_deleted IS NULL;
+
+DROP VIEW IF EXISTS view_products;
+
+CREATE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+	p.quantity,
+    p.status,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id
+WHERE
+    p.date_deleted IS NULL;
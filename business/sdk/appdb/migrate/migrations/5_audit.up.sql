@@ -0,0 +1,15 @@
+CREATE TABLE audit_log (
+	audit_id     UUID      NOT NULL,
+	actor_id     UUID      NOT NULL,
+	entity       TEXT      NOT NULL,
+	entity_id    UUID      NOT NULL,
+	action       TEXT      NOT NULL,
+	diff         JSONB     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (audit_id)
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity, entity_id);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id);
+CREATE INDEX audit_log_date_created_idx ON audit_log (date_created);
@@ -0,0 +1,5 @@
+-- The version is bumped by every write so updates can detect that the row
+-- changed since it was read.
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE products ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE homes ADD COLUMN version INT NOT NULL DEFAULT 1;
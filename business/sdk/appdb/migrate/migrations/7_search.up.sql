@@ -0,0 +1,27 @@
+-- The search column is maintained by Postgres from the product name so full
+-- text queries can use the GIN index instead of scanning with LIKE.
+ALTER TABLE products ADD COLUMN search TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', name)) STORED;
+
+CREATE INDEX products_search_idx ON products USING GIN (search);
+
+DROP VIEW IF EXISTS view_products;
+
+CREATE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+	p.quantity,
+    p.status,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name,
+    p.search || to_tsvector('english', u.name) AS search
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id
+WHERE
+    p.date_deleted IS NULL;
@@ -0,0 +1,26 @@
+-- Costs are held with the ISO-4217 code of their currency. Existing rows were
+-- all priced in US dollars.
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE order_items ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+DROP VIEW IF EXISTS view_products;
+
+CREATE VIEW view_products AS
+SELECT
+    p.product_id,
+    p.user_id,
+	p.name,
+    p.cost,
+    p.currency,
+	p.quantity,
+    p.status,
+    p.date_created,
+    p.date_updated,
+    u.name AS user_name,
+    p.search || to_tsvector('english', u.name) AS search
+FROM
+    products AS p
+JOIN
+    users AS u ON u.user_id = p.user_id
+WHERE
+    p.date_deleted IS NULL;
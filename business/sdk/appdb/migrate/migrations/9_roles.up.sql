@@ -0,0 +1,22 @@
+CREATE TABLE roles (
+	name         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (name)
+);
+
+CREATE TABLE role_permissions (
+	role_name  TEXT NOT NULL,
+	permission TEXT NOT NULL,
+
+	PRIMARY KEY (role_name, permission),
+	FOREIGN KEY (role_name) REFERENCES roles(name) ON DELETE CASCADE
+);
+
+INSERT INTO roles (name, date_created) VALUES
+	('ADMIN', NOW()),
+	('USER', NOW());
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+	('ADMIN', 'admin'),
+	('USER', 'user');
@@ -7,16 +7,35 @@ import (
 	"time"
 
 	esqldb "encore.dev/storage/sqldb"
+	"github.com/ardanlabs/encore/business/domain/accountbus"
+	"github.com/ardanlabs/encore/business/domain/accountbus/stores/accountdb"
+	"github.com/ardanlabs/encore/business/domain/apikeybus"
+	"github.com/ardanlabs/encore/business/domain/apikeybus/stores/apikeydb"
+	"github.com/ardanlabs/encore/business/domain/auditbus"
+	"github.com/ardanlabs/encore/business/domain/auditbus/stores/auditdb"
 	"github.com/ardanlabs/encore/business/domain/homebus"
 	"github.com/ardanlabs/encore/business/domain/homebus/stores/homedb"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus"
+	"github.com/ardanlabs/encore/business/domain/lockoutbus/stores/lockoutdb"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
+	"github.com/ardanlabs/encore/business/domain/orderbus/stores/orderdb"
+	"github.com/ardanlabs/encore/business/domain/orgbus"
+	"github.com/ardanlabs/encore/business/domain/orgbus/stores/orgdb"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/productbus/stores/productdb"
+	"github.com/ardanlabs/encore/business/domain/rolebus"
+	"github.com/ardanlabs/encore/business/domain/rolebus/stores/roledb"
+	"github.com/ardanlabs/encore/business/domain/tokenbus"
+	"github.com/ardanlabs/encore/business/domain/tokenbus/stores/tokendb"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/usercache"
 	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
 	"github.com/ardanlabs/encore/business/domain/vproductbus"
 	"github.com/ardanlabs/encore/business/domain/vproductbus/stores/vproductdb"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/delegate/stores/delegatedb"
+	"github.com/ardanlabs/encore/business/sdk/events"
+	"github.com/ardanlabs/encore/business/sdk/mailer"
 	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
 	"github.com/jmoiron/sqlx"
@@ -24,24 +43,48 @@ import (
 
 // BusDomain represents all the business domain apis needed for testing.
 type BusDomain struct {
+	Account  *accountbus.Business
+	APIKey   *apikeybus.Business
+	Audit    *auditbus.Business
 	Delegate *delegate.Delegate
 	Home     *homebus.Business
+	Lockout  *lockoutbus.Business
+	Order    *orderbus.Business
+	Org      *orgbus.Business
 	Product  *productbus.Business
+	Role     *rolebus.Business
+	Token    *tokenbus.Business
 	User     *userbus.Business
 	VProduct *vproductbus.Business
 }
 
-func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
-	delegate := delegate.New(log)
-	userBus := userbus.NewBusiness(log, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Hour))
-	productBus := productbus.NewBusiness(log, userBus, delegate, productdb.NewStore(log, db))
-	homeBus := homebus.NewBusiness(log, userBus, delegate, homedb.NewStore(log, db))
+func newBusDomains(log *logger.Logger, db *sqlx.DB, mailer mailer.Mailer) BusDomain {
+	delegate := delegate.New(log, sqldb.NewBeginner(db), nil, delegatedb.NewStore(log, db), nil)
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, db))
+	userBus := userbus.NewBusiness(log, auditBus, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Hour))
+	productBus := productbus.NewBusiness(log, userBus, auditBus, delegate, productdb.NewStore(log, db))
+	homeBus := homebus.NewBusiness(log, userBus, auditBus, delegate, homedb.NewStore(log, db))
+	orderBus := orderbus.NewBusiness(log, userBus, productBus, homeBus, orderdb.NewStore(log, db))
 	vproductBus := vproductbus.NewBusiness(vproductdb.NewStore(log, db))
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, db))
+	orgBus := orgbus.NewBusiness(log, orgdb.NewStore(log, db))
+	tokenBus := tokenbus.NewBusiness(log, delegate, tokendb.NewStore(log, db))
+	apiKeyBus := apikeybus.NewBusiness(log, userBus, apikeydb.NewStore(log, db))
+	lockoutBus := lockoutbus.NewBusiness(log, userBus, delegate, lockoutdb.NewStore(log, db))
+	accountBus := accountbus.NewBusiness(log, userBus, mailer, accountdb.NewStore(log, db))
 
 	return BusDomain{
+		Account:  accountBus,
+		APIKey:   apiKeyBus,
+		Audit:    auditBus,
 		Delegate: delegate,
 		Home:     homeBus,
+		Lockout:  lockoutBus,
+		Order:    orderBus,
+		Org:      orgBus,
 		Product:  productBus,
+		Role:     roleBus,
+		Token:    tokenBus,
 		User:     userBus,
 		VProduct: vproductBus,
 	}
@@ -53,6 +96,8 @@ func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
 type Database struct {
 	DB        *sqlx.DB
 	Log       *logger.Logger
+	Mailer    *mailer.FileMailer
+	Events    *events.Recorder
 	BusDomain BusDomain
 }
 
@@ -85,10 +130,22 @@ func NewDatabase(t *testing.T, edb *esqldb.Database) *Database {
 
 	log := logger.New("test")
 
+	// Mail is written to files so tests can read back what was sent.
+	mlr, err := mailer.NewFileMailer(t.TempDir())
+	if err != nil {
+		t.Fatalf("constructing mailer: %v", err)
+	}
+
+	busDomain := newBusDomains(log, db, mlr)
+
+	// The public events are recorded so tests can assert which events a
+	// business call emits.
 	return &Database{
 		Log:       log,
 		DB:        db,
-		BusDomain: newBusDomains(log, db),
+		Mailer:    mlr,
+		Events:    events.NewRecorder(busDomain.Delegate),
+		BusDomain: busDomain,
 	}
 }
 
@@ -137,3 +194,11 @@ func ProductNamePointer(value string) *productbus.Name {
 	name := productbus.MustParseName(value)
 	return &name
 }
+
+// MoneyPointer is a helper to get a *Money from an amount and currency. It's in
+// the tests package because we normally don't want to deal with pointers to
+// basic types but it's useful in some tests.
+func MoneyPointer(amount string, currency string) *productbus.Money {
+	money := productbus.MustParseMoney(amount, currency)
+	return &money
+}
@@ -0,0 +1,146 @@
+package delegate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for dead letter operations.
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrHandlerNotFound    = errors.New("handler no longer registered")
+	ErrReplayFailed       = errors.New("replay failed")
+)
+
+// DeadLetter represents an event a registered function failed to handle
+// after exhausting the attempts of its policy.
+type DeadLetter struct {
+	ID          uuid.UUID
+	Data        Data
+	Handler     string
+	Attempts    int
+	Error       string
+	DateCreated time.Time
+	DateFailed  time.Time
+}
+
+// DeadLetterFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type DeadLetterFilter struct {
+	Domain *string
+	Action *string
+}
+
+// QueryDeadLetters retrieves a list of dead letters, the latest first.
+func (d *Delegate) QueryDeadLetters(ctx context.Context, filter DeadLetterFilter, page page.Page) ([]DeadLetter, error) {
+	if d.storer == nil {
+		return nil, nil
+	}
+
+	dls, err := d.storer.QueryDeadLetters(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return dls, nil
+}
+
+// CountDeadLetters returns the total number of dead letters.
+func (d *Delegate) CountDeadLetters(ctx context.Context, filter DeadLetterFilter) (int, error) {
+	if d.storer == nil {
+		return 0, nil
+	}
+
+	n, err := d.storer.CountDeadLetters(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+
+	return n, nil
+}
+
+// QueryDeadLetterByID finds the dead letter by the specified ID.
+func (d *Delegate) QueryDeadLetterByID(ctx context.Context, deadLetterID uuid.UUID) (DeadLetter, error) {
+	if d.storer == nil {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+
+	dl, err := d.storer.QueryDeadLetterByID(ctx, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("query: deadLetterID[%s]: %w", deadLetterID, err)
+	}
+
+	return dl, nil
+}
+
+// Replay executes the function of the dead letter again with the policy it
+// was registered with. The dead letter is removed when the function succeeds,
+// otherwise the failure is recorded on it and ErrReplayFailed is returned.
+func (d *Delegate) Replay(ctx context.Context, dl DeadLetter) (DeadLetter, error) {
+	reg, exists := d.registration(dl.Data, dl.Handler)
+	if !exists {
+		return DeadLetter{}, ErrHandlerNotFound
+	}
+
+	attempts, err := d.run(ctx, reg, dl.Data)
+	if err == nil {
+		if err := d.storer.DeleteDeadLetter(ctx, dl); err != nil {
+			return DeadLetter{}, fmt.Errorf("delete: deadLetterID[%s]: %w", dl.ID, err)
+		}
+
+		return DeadLetter{}, nil
+	}
+
+	dl.Attempts += attempts
+	dl.Error = err.Error()
+	dl.DateFailed = time.Now()
+
+	if err := d.storer.UpdateDeadLetter(ctx, dl); err != nil {
+		return DeadLetter{}, fmt.Errorf("update: deadLetterID[%s]: %w", dl.ID, err)
+	}
+
+	return dl, fmt.Errorf("%w: %s", ErrReplayFailed, err)
+}
+
+// Discard removes the dead letter without executing its function.
+func (d *Delegate) Discard(ctx context.Context, dl DeadLetter) error {
+	if err := d.storer.DeleteDeadLetter(ctx, dl); err != nil {
+		return fmt.Errorf("delete: deadLetterID[%s]: %w", dl.ID, err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// deadLetter records that the function failed to handle the event.
+func (d *Delegate) deadLetter(ctx context.Context, reg registration, data Data, attempts int, cause error) error {
+	if d.storer == nil {
+		return cause
+	}
+
+	now := time.Now()
+
+	dl := DeadLetter{
+		ID:          uuid.New(),
+		Data:        data,
+		Handler:     reg.name,
+		Attempts:    attempts,
+		Error:       cause.Error(),
+		DateCreated: now,
+		DateFailed:  now,
+	}
+
+	if err := d.storer.CreateDeadLetter(ctx, dl); err != nil {
+		return errors.Join(cause, fmt.Errorf("createdeadletter: %w", err))
+	}
+
+	d.log.Info(ctx, "delegate dead letter", "id", data.ID, "deadLetterID", dl.ID, "func", reg.name)
+
+	return nil
+}
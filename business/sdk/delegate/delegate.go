@@ -4,8 +4,17 @@ package delegate
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
 )
 
 // These types are just for documentation so we know what keys go
@@ -15,51 +24,375 @@ type (
 	action string
 )
 
+// Publisher declares the behaviour needed to send an event to the handlers
+// asynchronously. The event has to be given back to Handle once delivered.
+type Publisher interface {
+	Publish(ctx context.Context, data Data) error
+}
+
+// TxPublisher declares the behaviour needed to publish an event as part of a
+// transaction, so the event is only sent when the transaction commits.
+type TxPublisher interface {
+	Publisher
+	PublishWithTx(ctx context.Context, tx sqldb.CommitRollbacker, data Data) error
+}
+
+// Storer interface declares the behaviour this package needs to remember the
+// handlers that already ran for an event and to keep the events the handlers
+// failed to handle.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Claim(ctx context.Context, key string, now time.Time) (bool, error)
+	Purge(ctx context.Context, handledBefore time.Time) (int, error)
+	CreateDeadLetter(ctx context.Context, dl DeadLetter) error
+	UpdateDeadLetter(ctx context.Context, dl DeadLetter) error
+	DeleteDeadLetter(ctx context.Context, dl DeadLetter) error
+	QueryDeadLetters(ctx context.Context, filter DeadLetterFilter, page page.Page) ([]DeadLetter, error)
+	CountDeadLetters(ctx context.Context, filter DeadLetterFilter) (int, error)
+	QueryDeadLetterByID(ctx context.Context, deadLetterID uuid.UUID) (DeadLetter, error)
+}
+
+// Metrics declares the behaviour needed to record the outcome of the
+// registered functions per domain and action.
+type Metrics interface {
+	IncDelegateSuccesses(domain string, action string, took time.Duration)
+	IncDelegateFailures(domain string, action string, took time.Duration)
+}
+
+// registration represents a function registered for an action. The name
+// identifies the function in the idempotency keys and the dead letters.
+type registration struct {
+	name   string
+	fn     Func
+	policy RetryPolicy
+}
+
+// registry holds the registered functions. It's shared by the delegate
+// values constructed for a transaction, so the lock protects all of them.
+type registry struct {
+	mu    sync.RWMutex
+	funcs map[domain]map[action][]registration
+}
+
 // Delegate manages the set of functions to be called by domain
 // packages when an import is not possible.
 type Delegate struct {
-	log   *logger.Logger
-	funcs map[domain]map[action][]Func
+	log       *logger.Logger
+	beginner  sqldb.Beginner
+	publisher Publisher
+	storer    Storer
+	metrics   Metrics
+	tx        sqldb.CommitRollbacker
+	registry  *registry
 }
 
-// New constructs a delegate for indirect api access.
-func New(log *logger.Logger) *Delegate {
+// New constructs a delegate for indirect api access. The publisher, the
+// storer and the metrics are optional. Without a publisher, published events
+// are handled synchronously like a call. Without a storer, handlers aren't
+// protected from running twice for a redelivered event and failed deliveries
+// can't be kept as dead letters. The beginner starts the transactions the
+// functions handle an event in and is required with a storer.
+func New(log *logger.Logger, beginner sqldb.Beginner, publisher Publisher, storer Storer, metrics Metrics) *Delegate {
 	return &Delegate{
-		log:   log,
-		funcs: make(map[domain]map[action][]Func),
+		log:       log,
+		beginner:  beginner,
+		publisher: publisher,
+		storer:    storer,
+		metrics:   metrics,
+		registry: &registry{
+			funcs: make(map[domain]map[action][]registration),
+		},
 	}
 }
 
-// Register adds a function to be called for a specified domain and action.
-func (d *Delegate) Register(domainType string, actionType string, fn Func) {
-	aMap, ok := d.funcs[domain(domainType)]
-	if !ok {
-		aMap = make(map[action][]Func)
-		d.funcs[domain(domainType)] = aMap
+// NewWithTx constructs a new delegate value that publishes events as part of
+// the specified transaction when the publisher supports it. The functions
+// registered with either value are shared.
+func (d *Delegate) NewWithTx(tx sqldb.CommitRollbacker) *Delegate {
+	return &Delegate{
+		log:       d.log,
+		beginner:  d.beginner,
+		publisher: d.publisher,
+		storer:    d.storer,
+		metrics:   d.metrics,
+		tx:        tx,
+		registry:  d.registry,
 	}
+}
+
+// Register adds a function to be called for a specified domain and action
+// with the default policy. Use the generic Register function to have the
+// parameters of the event decoded. The name identifies the function in the
+// idempotency keys and the dead letters, so it has to stay the same across
+// releases and be unique for the domain and action.
+func (d *Delegate) Register(domainType string, actionType string, name string, fn Func) {
+	d.RegisterWithPolicy(domainType, actionType, name, DefaultPolicy, fn)
+}
 
-	funcs := aMap[action(actionType)]
-	funcs = append(funcs, fn)
-	aMap[action(actionType)] = funcs
+// RegisterWithPolicy adds a function to be called for a specified domain and
+// action. The policy decides how the function is retried when it fails.
+func (d *Delegate) RegisterWithPolicy(domainType string, actionType string, name string, policy RetryPolicy, fn Func) {
+	d.register(domainType, actionType, name, policy, fn)
 }
 
 // Call executes all functions registered for the specified domain and
-// action. These functions are executed synchronously on the G making the call.
+// action. These functions are executed synchronously on the G making the call
+// and retried according to their policy. The call stops at the first function
+// that exhausts its attempts and returns its error, so the caller fails and
+// rolls back its changes. The failure isn't kept as a dead letter since the
+// caller is told about it.
 func (d *Delegate) Call(ctx context.Context, data Data) error {
-	d.log.Info(ctx, "delegate call", "status", "started", "domain", data.Domain, "action", data.Action, "params", data.RawParams)
-	defer d.log.Info(ctx, "delegate call", "status", "completed")
-
-	if dMap, ok := d.funcs[domain(data.Domain)]; ok {
-		if funcs, ok := dMap[action(data.Action)]; ok {
-			for _, fn := range funcs {
-				d.log.Info(ctx, "delegate call", "status", "sending")
-
-				if err := fn(ctx, data); err != nil {
-					d.log.Error(ctx, "delegate call", "msg", err)
-				}
-			}
+	if data.ID == "" {
+		data.ID = uuid.NewString()
+	}
+
+	d.log.Info(ctx, "delegate call", "status", "started", "id", data.ID, "domain", data.Domain, "action", data.Action, "params", data.RawParams)
+	defer d.log.Info(ctx, "delegate call", "status", "completed", "id", data.ID)
+
+	for _, reg := range d.registrations(data) {
+		d.log.Info(ctx, "delegate call", "status", "sending", "func", reg.name)
+
+		attempts, err := d.run(ctx, reg, data)
+		if err != nil {
+			d.log.Error(ctx, "delegate call", "id", data.ID, "func", reg.name, "attempts", attempts, "msg", err)
+			return fmt.Errorf("%s: %w", reg.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Publish sends the event to the functions registered for the domain and
+// action asynchronously. The functions are executed by Handle when the event
+// is delivered and a failed function is retried by delivering the event
+// again. Without a publisher the functions are executed synchronously.
+func (d *Delegate) Publish(ctx context.Context, data Data) error {
+	if data.ID == "" {
+		data.ID = uuid.NewString()
+	}
+
+	if d.publisher == nil {
+		return d.Call(ctx, data)
+	}
+
+	d.log.Info(ctx, "delegate publish", "id", data.ID, "domain", data.Domain, "action", data.Action)
+
+	if txPub, ok := d.publisher.(TxPublisher); ok && d.tx != nil {
+		if err := txPub.PublishWithTx(ctx, d.tx, data); err != nil {
+			return fmt.Errorf("publishwithtx: id[%s]: %w", data.ID, err)
+		}
+		return nil
+	}
+
+	if err := d.publisher.Publish(ctx, data); err != nil {
+		return fmt.Errorf("publish: id[%s]: %w", data.ID, err)
+	}
+
+	return nil
+}
+
+// Handle executes all functions registered for the domain and action of a
+// delivered event and retries them according to their policy. The event is
+// kept as a dead letter for every function that exhausts its attempts, so
+// the delivery is acknowledged and the event can be replayed later. An error
+// is only returned for the failures that couldn't be kept, so the event is
+// delivered again. A function that already handled the event isn't executed
+// again by a redelivery.
+func (d *Delegate) Handle(ctx context.Context, data Data) error {
+	d.log.Info(ctx, "delegate handle", "status", "started", "id", data.ID, "domain", data.Domain, "action", data.Action)
+	defer d.log.Info(ctx, "delegate handle", "status", "completed", "id", data.ID)
+
+	var errs []error
+	for _, reg := range d.registrations(data) {
+		attempts, err := d.run(ctx, reg, data)
+		if err == nil {
+			continue
+		}
+
+		d.log.Error(ctx, "delegate handle", "id", data.ID, "func", reg.name, "attempts", attempts, "msg", err)
+
+		if err := d.deadLetter(ctx, reg, data, attempts, err); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", reg.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Purge permanently removes the records of the handlers that handled an
+// event longer ago than the specified retention window. A redelivered event
+// is only recognized as handled while its record is kept, so the retention
+// has to be longer than an event can be redelivered for.
+func (d *Delegate) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if d.storer == nil {
+		return 0, nil
+	}
+
+	handledBefore := time.Now().Add(-retention)
+
+	n, err := d.storer.Purge(ctx, handledBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: handledBefore[%s]: %w", handledBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
+// IdempotencyKey returns the key that identifies the execution of the named
+// function for the event.
+func IdempotencyKey(data Data, name string) string {
+	return data.ID + "/" + name
+}
+
+// =============================================================================
+
+func (d *Delegate) register(domainType string, actionType string, name string, policy RetryPolicy, fn Func) {
+	d.registry.mu.Lock()
+	defer d.registry.mu.Unlock()
+
+	if name == "" {
+		panic(fmt.Sprintf("delegate: %s/%s: function registered without a name", domainType, actionType))
+	}
+
+	aMap, ok := d.registry.funcs[domain(domainType)]
+	if !ok {
+		aMap = make(map[action][]registration)
+		d.registry.funcs[domain(domainType)] = aMap
+	}
+
+	regs := aMap[action(actionType)]
+
+	// The name has to be unique, otherwise the functions would share the
+	// record of the events they handled.
+	for _, reg := range regs {
+		if reg.name == name {
+			panic(fmt.Sprintf("delegate: %s/%s: function %q registered twice", domainType, actionType, name))
+		}
+	}
+
+	reg := registration{
+		name:   name,
+		fn:     fn,
+		policy: policy,
+	}
+
+	aMap[action(actionType)] = append(regs, reg)
+}
+
+func (d *Delegate) registrations(data Data) []registration {
+	d.registry.mu.RLock()
+	defer d.registry.mu.RUnlock()
+
+	return slices.Clone(d.registry.funcs[domain(data.Domain)][action(data.Action)])
+}
+
+func (d *Delegate) registration(data Data, name string) (registration, bool) {
+	for _, reg := range d.registrations(data) {
+		if reg.name == name {
+			return reg, true
+		}
+	}
+
+	return registration{}, false
+}
+
+// run executes the function with its policy and records the outcome. With a
+// storer every attempt is made in a transaction of its own, unless the
+// delegate is bound to the transaction of the caller. A failed attempt aborts
+// that transaction, so the function is only attempted once on it.
+func (d *Delegate) run(ctx context.Context, reg registration, data Data) (int, error) {
+	key := IdempotencyKey(data, reg.name)
+
+	attempt := func(ctx context.Context) error {
+		return reg.fn(ctx, Claim{Key: key, Tx: d.tx}, data)
+	}
+
+	if d.storer != nil && data.ID != "" {
+		attempt = func(ctx context.Context) error {
+			return d.claimed(ctx, reg, key, data)
 		}
 	}
 
+	policy := reg.policy
+	if d.tx != nil {
+		policy.Attempts = 1
+	}
+
+	start := time.Now()
+	attempts, err := policy.run(ctx, attempt)
+	took := time.Since(start)
+
+	switch {
+	case d.metrics == nil:
+	case err != nil:
+		d.metrics.IncDelegateFailures(data.Domain, data.Action, took)
+	default:
+		d.metrics.IncDelegateSuccesses(data.Domain, data.Action, took)
+	}
+
+	return attempts, err
+}
+
+// claimed executes the function in a transaction that first records that the
+// function handled the event. The function makes its changes with the same
+// transaction, so the record is only committed along with them. When the
+// record already exists the function is skipped. A delivery of the event that
+// is handled at the same time waits for the record until the other commits or
+// rolls back. A delegate bound to the transaction of the caller claims and
+// executes the function with that transaction, so the changes of the function
+// are committed or rolled back along with the changes of the caller.
+func (d *Delegate) claimed(ctx context.Context, reg registration, key string, data Data) error {
+	if d.tx != nil {
+		return d.claim(ctx, reg, key, data, d.tx)
+	}
+
+	tx, err := d.beginner.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: key[%s]: %w", key, err)
+	}
+
+	hasCommitted := false
+	defer func() {
+		if hasCommitted {
+			return
+		}
+
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			d.log.Error(ctx, "delegate claim", "key", key, "msg", "rollback", "ERROR", err)
+		}
+	}()
+
+	if err := d.claim(ctx, reg, key, data, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: key[%s]: %w", key, err)
+	}
+
+	hasCommitted = true
+
 	return nil
 }
+
+// claim records that the function handled the event and executes it with the
+// specified transaction. The function is skipped when the record already
+// exists.
+func (d *Delegate) claim(ctx context.Context, reg registration, key string, data Data, tx sqldb.CommitRollbacker) error {
+	storer, err := d.storer.NewWithTx(tx)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := storer.Claim(ctx, key, time.Now())
+	if err != nil {
+		return fmt.Errorf("claim: key[%s]: %w", key, err)
+	}
+
+	if !claimed {
+		d.log.Info(ctx, "delegate claim", "status", "skipped", "key", key)
+		return nil
+	}
+
+	return reg.fn(ctx, Claim{Key: key, Tx: tx}, data)
+}
@@ -0,0 +1,973 @@
+package delegate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/delegate/stores/delegatedb"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Delegate(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, publish(db), "publish")
+	unitest.Run(t, handle(db), "handle")
+	unitest.Run(t, call(db), "call")
+	unitest.Run(t, deadLetter(db), "deadletter")
+	unitest.Run(t, typed(db), "typed")
+}
+
+func Test_Params(t *testing.T) {
+	t.Parallel()
+
+	unitest.Run(t, params(), "params")
+}
+
+// =============================================================================
+
+// publisher keeps the events that were published instead of sending them.
+type publisher struct {
+	events []delegate.Data
+}
+
+func (p *publisher) Publish(ctx context.Context, data delegate.Data) error {
+	p.events = append(p.events, data)
+	return nil
+}
+
+// counter counts the calls of a function and fails the first calls.
+type counter struct {
+	calls int
+	fails int
+}
+
+func (c *counter) handle(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+	c.calls++
+	if c.calls <= c.fails {
+		return fmt.Errorf("call %d failed", c.calls)
+	}
+
+	return nil
+}
+
+// slow blocks until the context of the call is done.
+func slow(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// metrics counts the outcomes recorded per domain and action.
+type metrics struct {
+	successes map[string]int
+	failures  map[string]int
+}
+
+func (m *metrics) IncDelegateSuccesses(domain string, action string, took time.Duration) {
+	m.successes[domain+"/"+action]++
+}
+
+func (m *metrics) IncDelegateFailures(domain string, action string, took time.Duration) {
+	m.failures[domain+"/"+action]++
+}
+
+// greeting is at version 2 of its schema. The name of version 1 was split
+// into the first and last name.
+type greeting struct {
+	First string
+	Last  string
+}
+
+func (greeting) Schema() delegate.Schema {
+	return delegate.Schema{Domain: "test", Action: "greeting", Version: 2}
+}
+
+func (greeting) Upcast(version int, rawParams []byte) ([]byte, error) {
+	switch version {
+	case 1:
+		var v1 struct {
+			Name string
+		}
+		if err := json.Unmarshal(rawParams, &v1); err != nil {
+			return nil, err
+		}
+
+		first, last, _ := strings.Cut(v1.Name, " ")
+
+		return json.Marshal(greeting{First: first, Last: last})
+	}
+
+	return nil, fmt.Errorf("unexpected version %d", version)
+}
+
+// parcel is sent in order for the entity it's about.
+type parcel struct {
+	ParcelID string
+}
+
+func (parcel) Schema() delegate.Schema {
+	return delegate.Schema{Domain: "test", Action: "parcel", Version: 1}
+}
+
+func (p parcel) EntityID() string {
+	return p.ParcelID
+}
+
+// farewell never changed so it can't be upcast.
+type farewell struct {
+	Name string
+}
+
+func (farewell) Schema() delegate.Schema {
+	return delegate.Schema{Domain: "test", Action: "farewell", Version: 1}
+}
+
+// renamed changed its schema without providing an upcaster.
+type renamed struct {
+	FullName string
+}
+
+func (renamed) Schema() delegate.Schema {
+	return delegate.Schema{Domain: "test", Action: "renamed", Version: 2}
+}
+
+func newDelegate(db *dbtest.Database, pub delegate.Publisher) *delegate.Delegate {
+	return delegate.New(db.Log, sqldb.NewBeginner(db.DB), pub, delegatedb.NewStore(db.Log, db.DB), nil)
+}
+
+// deadLetters returns the dead letters kept for the action.
+func deadLetters(ctx context.Context, d *delegate.Delegate, action string) ([]delegate.DeadLetter, error) {
+	domain := "test"
+	filter := delegate.DeadLetterFilter{
+		Domain: &domain,
+		Action: &action,
+	}
+
+	return d.QueryDeadLetters(ctx, filter, page.MustParse("1", "10"))
+}
+
+// =============================================================================
+
+func publish(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "async",
+			ExpResp: []any{1, true, 0},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				var c counter
+				d.Register("test", "async", "counter", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "async"}); err != nil {
+					return err
+				}
+
+				// The handler only runs once the event is delivered.
+				return []any{len(pub.events), pub.events[0].ID != "", c.calls}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "sync",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				d := newDelegate(db, nil)
+
+				var c counter
+				d.Register("test", "sync", "counter", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "sync"}); err != nil {
+					return err
+				}
+
+				return c.calls
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func handle(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "retry",
+			ExpResp: []any{1, 2, 0},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				ok := counter{}
+				failing := counter{fails: 1}
+				d.Register("test", "handle-retry", "ok", ok.handle)
+				d.RegisterWithPolicy("test", "handle-retry", "failing", delegate.RetryPolicy{Attempts: 2, Backoff: time.Millisecond}, failing.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "handle-retry"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				// Neither function runs again for a redelivery.
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				dls, err := deadLetters(ctx, d, "handle-retry")
+				if err != nil {
+					return err
+				}
+
+				return []any{ok.calls, failing.calls, len(dls)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "redelivered",
+			ExpResp: []any{1, 1},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				// Every function runs once however often the event is
+				// delivered.
+				var c counter
+				d.Register("test", "redelivered", "first", c.handle)
+
+				var c2 counter
+				d.Register("test", "redelivered", "second", c2.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "redelivered"}); err != nil {
+					return err
+				}
+
+				for i := 0; i < 3; i++ {
+					if err := d.Handle(ctx, pub.events[0]); err != nil {
+						return err
+					}
+				}
+
+				return []any{c.calls, c2.calls}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "claim",
+			ExpResp: []any{true, false, 1},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				var inTx bool
+				var claimedAgain bool
+				var calls int
+				d.Register("test", "claim", "claimed", func(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+					calls++
+					inTx = claim.Tx != nil
+
+					// The record of the claim is part of the transaction
+					// the function makes its changes with.
+					storer, err := delegatedb.NewStore(db.Log, db.DB).NewWithTx(claim.Tx)
+					if err != nil {
+						return err
+					}
+
+					claimedAgain, err = storer.Claim(ctx, claim.Key, time.Now())
+					return err
+				})
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "claim"}); err != nil {
+					return err
+				}
+
+				for i := 0; i < 2; i++ {
+					if err := d.Handle(ctx, pub.events[0]); err != nil {
+						return err
+					}
+				}
+
+				return []any{inTx, claimedAgain, calls}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: []any{0, 1, true, 2},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				c := counter{}
+				d.Register("test", "handle-purge", "purged", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "handle-purge"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				// The record is kept within the retention window, so the
+				// redelivered event isn't handled again.
+				early, err := d.Purge(ctx, time.Hour)
+				if err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+				calls := c.calls
+
+				purged, err := d.Purge(ctx, 0)
+				if err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				return []any{early, calls, purged > 0, c.calls}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "exhausted",
+			ExpResp: []any{true, 2, 1, "failing", 2, "call 2 failed"},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				ok := counter{}
+				failing := counter{fails: 5}
+				d.Register("test", "handle-exhausted", "ok", ok.handle)
+				d.RegisterWithPolicy("test", "handle-exhausted", "failing", delegate.RetryPolicy{Attempts: 2}, failing.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "handle-exhausted"}); err != nil {
+					return err
+				}
+
+				// The failure is kept as a dead letter and the delivery is
+				// acknowledged.
+				acked := d.Handle(ctx, pub.events[0]) == nil
+
+				dls, err := deadLetters(ctx, d, "handle-exhausted")
+				if err != nil {
+					return err
+				}
+
+				if len(dls) != 1 {
+					return fmt.Errorf("expected 1 dead letter, got %d", len(dls))
+				}
+
+				return []any{acked, failing.calls, ok.calls, dls[0].Handler, dls[0].Attempts, dls[0].Error}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "nostore",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := delegate.New(db.Log, nil, &pub, nil, nil)
+
+				errFailed := errors.New("failed")
+				d.Register("test", "handle-nostore", "failing", func(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+					return errFailed
+				})
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "handle-nostore"}); err != nil {
+					return err
+				}
+
+				// Without a store the failure can't be kept so it's returned
+				// and the event is delivered again.
+				err := d.Handle(ctx, pub.events[0])
+
+				return errors.Is(err, errFailed)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func call(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "retry",
+			ExpResp: []any{3, 0, 1, 0},
+			ExcFunc: func(ctx context.Context) any {
+				m := metrics{successes: map[string]int{}, failures: map[string]int{}}
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), nil, delegatedb.NewStore(db.Log, db.DB), &m)
+
+				c := counter{fails: 2}
+				d.RegisterWithPolicy("test", "call-retry", "counter", delegate.RetryPolicy{Attempts: 3, Backoff: time.Millisecond}, c.handle)
+
+				if err := d.Call(ctx, delegate.Data{Domain: "test", Action: "call-retry"}); err != nil {
+					return err
+				}
+
+				dls, err := deadLetters(ctx, d, "call-retry")
+				if err != nil {
+					return err
+				}
+
+				return []any{c.calls, len(dls), m.successes["test/call-retry"], m.failures["test/call-retry"]}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "exhausted",
+			ExpResp: []any{2, 0, true, 1},
+			ExcFunc: func(ctx context.Context) any {
+				m := metrics{successes: map[string]int{}, failures: map[string]int{}}
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), nil, delegatedb.NewStore(db.Log, db.DB), &m)
+
+				c := counter{fails: 5}
+				d.RegisterWithPolicy("test", "call-exhausted", "counter", delegate.RetryPolicy{Attempts: 2}, c.handle)
+
+				// The failure is returned so the caller can roll back instead
+				// of being kept as a dead letter.
+				err := d.Call(ctx, delegate.Data{Domain: "test", Action: "call-exhausted"})
+				failed := err != nil && strings.HasSuffix(err.Error(), "call 2 failed")
+
+				dls, err := deadLetters(ctx, d, "call-exhausted")
+				if err != nil {
+					return err
+				}
+
+				return []any{c.calls, len(dls), failed, m.failures["test/call-exhausted"]}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "timeout",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				d := newDelegate(db, nil)
+				d.RegisterWithPolicy("test", "call-timeout", "slow", delegate.RetryPolicy{Attempts: 1, Timeout: 10 * time.Millisecond}, slow)
+
+				// The time limit covers claiming the event as well, so the
+				// deadline can be hit before the function runs.
+				err := d.Call(ctx, delegate.Data{Domain: "test", Action: "call-timeout"})
+
+				return errors.Is(err, context.DeadlineExceeded)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "tx",
+			ExpResp: []any{true, 2},
+			ExcFunc: func(ctx context.Context) any {
+				beginner := sqldb.NewBeginner(db.DB)
+				d := delegate.New(db.Log, beginner, nil, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				tx, err := beginner.Begin()
+				if err != nil {
+					return err
+				}
+
+				var sameTx bool
+				c := counter{}
+				d.Register("test", "call-tx", "counter", func(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+					if c.calls == 0 {
+						sameTx = claim.Tx == tx
+					}
+					return c.handle(ctx, claim, data)
+				})
+
+				data := delegate.Data{ID: "call-tx", Domain: "test", Action: "call-tx"}
+
+				if err := d.NewWithTx(tx).Call(ctx, data); err != nil {
+					return err
+				}
+
+				// The claim is rolled back along with the transaction of the
+				// caller, so the function is executed again.
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				if err := d.Call(ctx, data); err != nil {
+					return err
+				}
+
+				return []any{sameTx, c.calls}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "nostore",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				d := delegate.New(db.Log, nil, nil, nil, nil)
+
+				errFailed := errors.New("failed")
+				d.Register("test", "call-nostore", "failing", func(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+					return errFailed
+				})
+
+				err := d.Call(ctx, delegate.Data{Domain: "test", Action: "call-nostore"})
+
+				return errors.Is(err, errFailed)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func deadLetter(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "replay",
+			ExpResp: []any{true, 2, 3, 0},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				c := counter{fails: 2}
+				d.Register("test", "replay", "counter", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "replay"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				dls, err := deadLetters(ctx, d, "replay")
+				if err != nil {
+					return err
+				}
+
+				if len(dls) != 1 {
+					return fmt.Errorf("expected 1 dead letter, got %d", len(dls))
+				}
+
+				// The first replay fails again and the failure is recorded.
+				dl, err := d.Replay(ctx, dls[0])
+				failed := errors.Is(err, delegate.ErrReplayFailed)
+
+				if _, err := d.Replay(ctx, dl); err != nil {
+					return err
+				}
+
+				dls, err = deadLetters(ctx, d, "replay")
+				if err != nil {
+					return err
+				}
+
+				return []any{failed, dl.Attempts, c.calls, len(dls)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "discard",
+			ExpResp: []any{1, delegate.ErrDeadLetterNotFound.Error()},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				c := counter{fails: 1}
+				d.Register("test", "discard", "counter", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "discard"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				dls, err := deadLetters(ctx, d, "discard")
+				if err != nil {
+					return err
+				}
+
+				if len(dls) != 1 {
+					return fmt.Errorf("expected 1 dead letter, got %d", len(dls))
+				}
+
+				if err := d.Discard(ctx, dls[0]); err != nil {
+					return err
+				}
+
+				_, err = d.QueryDeadLetterByID(ctx, dls[0].ID)
+				if !errors.Is(err, delegate.ErrDeadLetterNotFound) {
+					return fmt.Errorf("expected the dead letter to be gone, got %v", err)
+				}
+
+				// The function isn't executed by a discard.
+				return []any{c.calls, delegate.ErrDeadLetterNotFound.Error()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "unregistered",
+			ExpResp: delegate.ErrHandlerNotFound.Error(),
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				c := counter{fails: 1}
+				d.Register("test", "unregistered", "counter", c.handle)
+
+				if err := d.Publish(ctx, delegate.Data{Domain: "test", Action: "unregistered"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				dls, err := deadLetters(ctx, d, "unregistered")
+				if err != nil {
+					return err
+				}
+
+				if len(dls) != 1 {
+					return fmt.Errorf("expected 1 dead letter, got %d", len(dls))
+				}
+
+				// A new process doesn't have the function registered anymore.
+				_, err = newDelegate(db, nil).Replay(ctx, dls[0])
+				if !errors.Is(err, delegate.ErrHandlerNotFound) {
+					return fmt.Errorf("expected the handler to not be found, got %v", err)
+				}
+
+				return err.Error()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func typed(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "emit",
+			ExpResp: []greeting{{First: "Bill", Last: "Kennedy"}},
+			ExcFunc: func(ctx context.Context) any {
+				d := newDelegate(db, nil)
+
+				var got []greeting
+				delegate.Register(d, "greeting", func(ctx context.Context, claim delegate.Claim, params greeting) error {
+					got = append(got, params)
+					return nil
+				})
+
+				if err := delegate.Emit(ctx, d, greeting{First: "Bill", Last: "Kennedy"}); err != nil {
+					return err
+				}
+
+				return got
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "publish",
+			ExpResp: []any{2, []greeting{{First: "Jack", Last: "Kennedy"}}},
+			ExcFunc: func(ctx context.Context) any {
+				var pub publisher
+				d := newDelegate(db, &pub)
+
+				var got []greeting
+				delegate.Register(d, "greeting", func(ctx context.Context, claim delegate.Claim, params greeting) error {
+					got = append(got, params)
+					return nil
+				})
+
+				if err := delegate.Publish(ctx, d, greeting{First: "Jack", Last: "Kennedy"}); err != nil {
+					return err
+				}
+
+				if err := d.Handle(ctx, pub.events[0]); err != nil {
+					return err
+				}
+
+				return []any{pub.events[0].Version, got}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "duplicate",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) (resp any) {
+				d := newDelegate(db, nil)
+
+				fn := func(ctx context.Context, claim delegate.Claim, params farewell) error {
+					return nil
+				}
+
+				delegate.Register(d, "farewell", fn)
+
+				// A name can only be registered once for an event.
+				defer func() {
+					resp = recover() != nil
+				}()
+
+				delegate.Register(d, "farewell", fn)
+
+				return false
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "concurrent",
+			ExpResp: 20,
+			ExcFunc: func(ctx context.Context) any {
+				d := newDelegate(db, nil)
+
+				var mu sync.Mutex
+				var calls int
+				fn := func(ctx context.Context, claim delegate.Claim, params farewell) error {
+					mu.Lock()
+					defer mu.Unlock()
+					calls++
+					return nil
+				}
+
+				// Functions are registered while events are emitted.
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(2)
+					go func() {
+						defer wg.Done()
+						delegate.Register(d, fmt.Sprintf("farewell-%d", i), fn)
+					}()
+					go func() {
+						defer wg.Done()
+						delegate.Emit(ctx, d, farewell{})
+					}()
+				}
+				wg.Wait()
+
+				mu.Lock()
+				calls = 0
+				mu.Unlock()
+
+				// Every registration runs once the registering is done.
+				if err := delegate.Emit(ctx, d, farewell{}); err != nil {
+					return err
+				}
+				if err := delegate.Emit(ctx, d, farewell{}); err != nil {
+					return err
+				}
+
+				return calls
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func params() []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "roundtrip",
+			ExpResp: greeting{First: "Bill", Last: "Kennedy"},
+			ExcFunc: func(ctx context.Context) any {
+				data, err := delegate.Encode(greeting{First: "Bill", Last: "Kennedy"})
+				if err != nil {
+					return err
+				}
+
+				if data.Domain != "test" || data.Action != "greeting" || data.Version != 2 {
+					return fmt.Errorf("unexpected data %s", data)
+				}
+
+				params, err := delegate.Decode[greeting](data)
+				if err != nil {
+					return err
+				}
+
+				return params
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "ordered",
+			ExpResp: []string{"parcel-1", ""},
+			ExcFunc: func(ctx context.Context) any {
+				ordered, err := delegate.Encode(parcel{ParcelID: "parcel-1"})
+				if err != nil {
+					return err
+				}
+
+				unordered, err := delegate.Encode(farewell{Name: "Bill"})
+				if err != nil {
+					return err
+				}
+
+				return []string{ordered.OrderingKey, unordered.OrderingKey}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "upcast",
+			ExpResp: greeting{First: "Bill", Last: "Kennedy"},
+			ExcFunc: func(ctx context.Context) any {
+				data := delegate.Data{
+					Domain:    "test",
+					Action:    "greeting",
+					Version:   1,
+					RawParams: []byte(`{"Name":"Bill Kennedy"}`),
+				}
+
+				params, err := delegate.Decode[greeting](data)
+				if err != nil {
+					return err
+				}
+
+				return params
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "unversioned",
+			ExpResp: greeting{First: "Jack", Last: "Kennedy"},
+			ExcFunc: func(ctx context.Context) any {
+
+				// Events encoded before versioning are version 1.
+				data := delegate.Data{
+					Domain:    "test",
+					Action:    "greeting",
+					RawParams: []byte(`{"Name":"Jack Kennedy"}`),
+				}
+
+				params, err := delegate.Decode[greeting](data)
+				if err != nil {
+					return err
+				}
+
+				return params
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "newer",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				data := delegate.Data{
+					Domain:    "test",
+					Action:    "greeting",
+					Version:   3,
+					RawParams: []byte(`{}`),
+				}
+
+				_, err := delegate.Decode[greeting](data)
+
+				return errors.Is(err, delegate.ErrUnknownVersion)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "noupcaster",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				data := delegate.Data{
+					Domain:    "test",
+					Action:    "renamed",
+					Version:   1,
+					RawParams: []byte(`{"Name":"Bill"}`),
+				}
+
+				_, err := delegate.Decode[renamed](data)
+
+				return errors.Is(err, delegate.ErrUnknownVersion)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
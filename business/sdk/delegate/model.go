@@ -3,22 +3,53 @@ package delegate
 import (
 	"context"
 	"fmt"
+
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 // Func represents a function that is registered and called by the system.
-type Func func(context.Context, Data) error
+type Func func(ctx context.Context, claim Claim, data Data) error
+
+// Claim is held by a function while it handles an event. The function has to
+// make its changes with the transaction of the claim, since the record that
+// the function handled the event is written to it as well. That way both are
+// committed together and a redelivered event can't run the function again.
+// The transaction is nil when there is no storer to keep the record.
+type Claim struct {
+	Key string
+	Tx  sqldb.CommitRollbacker
+}
+
+// WithClaim returns the value bound to the transaction of the claim by its
+// NewWithTx function. The value is returned as is when the claim has no
+// transaction.
+func WithClaim[T any](claim Claim, v T, newWithTx func(tx sqldb.CommitRollbacker) (T, error)) (T, error) {
+	if claim.Tx == nil {
+		return v, nil
+	}
+
+	return newWithTx(claim.Tx)
+}
 
-// Data represents an event between domains.
+// Data represents an event between domains. The ID is assigned when the
+// event is published and stays the same when the event is delivered again.
+// The version is the schema version the parameters were encoded with. Events
+// encoded before versioning have no version and are taken as version 1. The
+// events with the same ordering key are sent in the order they were
+// published. Events without an ordering key can be sent in any order.
 type Data struct {
-	Domain    string
-	Action    string
-	RawParams []byte
+	ID          string
+	Domain      string
+	Action      string
+	Version     int
+	OrderingKey string
+	RawParams   []byte
 }
 
 // String implements the Stringer interface.
 func (d Data) String() string {
 	return fmt.Sprintf(
-		"Event{Domain:%#v, Action:%#v, RawParams:%#v}",
-		d.Domain, d.Action, string(d.RawParams),
+		"Event{ID:%#v, Domain:%#v, Action:%#v, Version:%d, OrderingKey:%#v, RawParams:%#v}",
+		d.ID, d.Domain, d.Action, d.Version, d.OrderingKey, string(d.RawParams),
 	)
 }
@@ -0,0 +1,161 @@
+package delegate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownVersion is returned when the parameters of an event were encoded
+// with a schema version the params type doesn't know how to decode.
+var ErrUnknownVersion = errors.New("unknown event version")
+
+// Schema identifies the event a params type is encoded for and the version
+// of its encoding.
+type Schema struct {
+	Domain  string
+	Action  string
+	Version int
+}
+
+// Params represents the parameters of an event. The schema is read from the
+// zero value of the type. The version has to be incremented whenever the
+// params change in a way that breaks decoding the payloads encoded before,
+// and the params have to implement Upcaster from then on.
+type Params interface {
+	Schema() Schema
+}
+
+// Upcaster is implemented by params whose schema changed since the first
+// version. Upcast converts a payload encoded with the specified version to
+// the next version.
+type Upcaster interface {
+	Upcast(version int, rawParams []byte) ([]byte, error)
+}
+
+// Ordered is implemented by params whose events have to be sent in the order
+// they were published for the same entity. The id of the entity becomes the
+// ordering key of the event.
+type Ordered interface {
+	EntityID() string
+}
+
+// Register adds a function to be called with the decoded parameters of the
+// event the params type is encoded for, with the default policy. The name
+// has to stay the same across releases like it does for Delegate.Register.
+func Register[T Params](d *Delegate, name string, fn func(context.Context, Claim, T) error) {
+	RegisterWithPolicy(d, name, DefaultPolicy, fn)
+}
+
+// RegisterWithPolicy adds a function to be called with the decoded parameters
+// of the event the params type is encoded for. The policy decides how the
+// function is retried when it fails.
+func RegisterWithPolicy[T Params](d *Delegate, name string, policy RetryPolicy, fn func(context.Context, Claim, T) error) {
+	var params T
+	schema := params.Schema()
+
+	f := func(ctx context.Context, claim Claim, data Data) error {
+		params, err := Decode[T](data)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, claim, params)
+	}
+
+	d.register(schema.Domain, schema.Action, name, policy, f)
+}
+
+// Emit encodes the params and calls the functions registered for the event
+// synchronously like Call does.
+func Emit[T Params](ctx context.Context, d *Delegate, params T) error {
+	data, err := Encode(params)
+	if err != nil {
+		return err
+	}
+
+	return d.Call(ctx, data)
+}
+
+// Publish encodes the params and publishes the event like Delegate.Publish
+// does.
+func Publish[T Params](ctx context.Context, d *Delegate, params T) error {
+	data, err := Encode(params)
+	if err != nil {
+		return err
+	}
+
+	return d.Publish(ctx, data)
+}
+
+// Encode constructs the data for the event the params are encoded for.
+func Encode[T Params](params T) (Data, error) {
+	schema := params.Schema()
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return Data{}, fmt.Errorf("encode: %s/%s: %w", schema.Domain, schema.Action, err)
+	}
+
+	data := Data{
+		Domain:    schema.Domain,
+		Action:    schema.Action,
+		Version:   max(schema.Version, 1),
+		RawParams: rawParams,
+	}
+
+	if ordered, ok := any(params).(Ordered); ok {
+		data.OrderingKey = ordered.EntityID()
+	}
+
+	return data, nil
+}
+
+// Decode returns the params encoded in the data. A payload encoded with an
+// older version of the params is upcast to the current version first.
+func Decode[T Params](data Data) (T, error) {
+	var params T
+	schema := params.Schema()
+
+	rawParams, err := upcast(params, schema, data)
+	if err != nil {
+		return params, fmt.Errorf("decode: %s/%s: %w", data.Domain, data.Action, err)
+	}
+
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return params, fmt.Errorf("decode: %s/%s: expected an encoded %T: %w", data.Domain, data.Action, params, err)
+	}
+
+	return params, nil
+}
+
+// =============================================================================
+
+func upcast(params Params, schema Schema, data Data) ([]byte, error) {
+	current := max(schema.Version, 1)
+	version := max(data.Version, 1)
+
+	if version > current {
+		return nil, fmt.Errorf("%w: version[%d] current[%d]", ErrUnknownVersion, version, current)
+	}
+
+	if version == current {
+		return data.RawParams, nil
+	}
+
+	upcaster, ok := params.(Upcaster)
+	if !ok {
+		return nil, fmt.Errorf("%w: version[%d] current[%d]: no upcaster", ErrUnknownVersion, version, current)
+	}
+
+	rawParams := data.RawParams
+	for ; version < current; version++ {
+		var err error
+		if rawParams, err = upcaster.Upcast(version, rawParams); err != nil {
+			return nil, fmt.Errorf("upcast: version[%d]: %w", version, err)
+		}
+	}
+
+	return rawParams, nil
+}
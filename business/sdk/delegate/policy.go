@@ -0,0 +1,65 @@
+package delegate
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPolicy is used for the functions registered without a policy. The
+// function is executed once and without a time limit.
+var DefaultPolicy = RetryPolicy{
+	Attempts: 1,
+}
+
+// RetryPolicy defines how many times a registered function is executed
+// before it is considered failed. The wait between attempts starts at the
+// backoff and doubles after every failed attempt. A timeout of zero doesn't
+// limit how long an attempt can take.
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+	Timeout  time.Duration
+}
+
+// run executes the function until it succeeds or the attempts of the policy
+// are exhausted. The number of attempts made is returned with the error of
+// the last attempt.
+func (p RetryPolicy) run(ctx context.Context, fn func(ctx context.Context) error) (int, error) {
+	attempts := max(p.Attempts, 1)
+	backoff := p.Backoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = p.attempt(ctx, fn); err == nil {
+			return attempt, nil
+		}
+
+		if attempt == attempts {
+			return attempt, err
+		}
+
+		if backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return attempt, fmt.Errorf("%w: %w", err, ctx.Err())
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+		}
+	}
+
+	return attempts, err
+}
+
+func (p RetryPolicy) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.Timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	return fn(ctx)
+}
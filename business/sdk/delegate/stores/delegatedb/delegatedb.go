@@ -0,0 +1,241 @@
+// Package delegatedb contains the delegate idempotency and dead letter related
+// CRUD functionality.
+package delegatedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for delegate database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (delegate.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Claim records that the handler for the key handled the event. False is
+// returned when the key was already recorded. Another transaction recording
+// the key at the same time makes the call wait until it commits or rolls back.
+func (s *Store) Claim(ctx context.Context, key string, now time.Time) (bool, error) {
+	data := struct {
+		Key           string    `db:"idempotency_key"`
+		DateCompleted time.Time `db:"date_completed"`
+	}{
+		Key:           key,
+		DateCompleted: now.UTC(),
+	}
+
+	const q = `
+	INSERT INTO delegate_handled
+		(idempotency_key, date_completed)
+	VALUES
+		(:idempotency_key, :date_completed)
+	ON CONFLICT (idempotency_key) DO NOTHING
+	RETURNING
+		idempotency_key`
+
+	var dbKey struct {
+		Key string `db:"idempotency_key"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbKey); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return true, nil
+}
+
+// Purge removes the records of the handlers that handled an event before the
+// specified time.
+func (s *Store) Purge(ctx context.Context, handledBefore time.Time) (int, error) {
+	data := struct {
+		HandledBefore time.Time `db:"handled_before"`
+	}{
+		HandledBefore: handledBefore.UTC(),
+	}
+
+	const q = `
+	WITH purged AS (
+		DELETE FROM
+			delegate_handled
+		WHERE
+			date_completed < :handled_before
+		RETURNING
+			idempotency_key
+	)
+	SELECT
+		count(1) AS count
+	FROM
+		purged`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// CreateDeadLetter inserts a new dead letter into the database.
+func (s *Store) CreateDeadLetter(ctx context.Context, dl delegate.DeadLetter) error {
+	const q = `
+	INSERT INTO delegate_dead_letters
+		(dead_letter_id, event_id, domain, action, version, raw_params, handler, attempts, error, date_created, date_failed)
+	VALUES
+		(:dead_letter_id, :event_id, :domain, :action, :version, :raw_params, :handler, :attempts, :error, :date_created, :date_failed)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDeadLetter(dl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDeadLetter records another failure of the dead letter.
+func (s *Store) UpdateDeadLetter(ctx context.Context, dl delegate.DeadLetter) error {
+	const q = `
+	UPDATE
+		delegate_dead_letters
+	SET
+		"attempts" = :attempts,
+		"error" = :error,
+		"date_failed" = :date_failed
+	WHERE
+		dead_letter_id = :dead_letter_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDeadLetter(dl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDeadLetter removes the dead letter from the database.
+func (s *Store) DeleteDeadLetter(ctx context.Context, dl delegate.DeadLetter) error {
+	const q = `
+	DELETE FROM
+		delegate_dead_letters
+	WHERE
+		dead_letter_id = :dead_letter_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDeadLetter(dl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDeadLetters retrieves a list of dead letters, the latest first.
+func (s *Store) QueryDeadLetters(ctx context.Context, filter delegate.DeadLetterFilter, page page.Page) ([]delegate.DeadLetter, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		dead_letter_id, event_id, domain, action, version, raw_params, handler, attempts, error, date_created, date_failed
+	FROM
+		delegate_dead_letters`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	buf.WriteString(" ORDER BY date_created DESC, dead_letter_id")
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbDLs []deadLetter
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbDLs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeadLetters(dbDLs), nil
+}
+
+// CountDeadLetters returns the total number of dead letters in the DB.
+func (s *Store) CountDeadLetters(ctx context.Context, filter delegate.DeadLetterFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		delegate_dead_letters`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryDeadLetterByID gets the specified dead letter from the database.
+func (s *Store) QueryDeadLetterByID(ctx context.Context, deadLetterID uuid.UUID) (delegate.DeadLetter, error) {
+	data := struct {
+		ID string `db:"dead_letter_id"`
+	}{
+		ID: deadLetterID.String(),
+	}
+
+	const q = `
+	SELECT
+		dead_letter_id, event_id, domain, action, version, raw_params, handler, attempts, error, date_created, date_failed
+	FROM
+		delegate_dead_letters
+	WHERE
+		dead_letter_id = :dead_letter_id`
+
+	var dbDL deadLetter
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbDL); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return delegate.DeadLetter{}, fmt.Errorf("db: %w", delegate.ErrDeadLetterNotFound)
+		}
+		return delegate.DeadLetter{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusDeadLetter(dbDL), nil
+}
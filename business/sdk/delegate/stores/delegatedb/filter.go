@@ -0,0 +1,27 @@
+package delegatedb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+func (s *Store) applyFilter(filter delegate.DeadLetterFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.Domain != nil {
+		data["domain"] = *filter.Domain
+		wc = append(wc, "domain = :domain")
+	}
+
+	if filter.Action != nil {
+		data["action"] = *filter.Action
+		wc = append(wc, "action = :action")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
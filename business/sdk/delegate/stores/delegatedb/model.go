@@ -0,0 +1,72 @@
+package delegatedb
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/google/uuid"
+)
+
+type deadLetter struct {
+	ID          uuid.UUID `db:"dead_letter_id"`
+	EventID     string    `db:"event_id"`
+	Domain      string    `db:"domain"`
+	Action      string    `db:"action"`
+	Version     int       `db:"version"`
+	RawParams   []byte    `db:"raw_params"`
+	Handler     string    `db:"handler"`
+	Attempts    int       `db:"attempts"`
+	Error       string    `db:"error"`
+	DateCreated time.Time `db:"date_created"`
+	DateFailed  time.Time `db:"date_failed"`
+}
+
+func toDBDeadLetter(bus delegate.DeadLetter) deadLetter {
+	db := deadLetter{
+		ID:          bus.ID,
+		EventID:     bus.Data.ID,
+		Domain:      bus.Data.Domain,
+		Action:      bus.Data.Action,
+		Version:     bus.Data.Version,
+		RawParams:   bus.Data.RawParams,
+		Handler:     bus.Handler,
+		Attempts:    bus.Attempts,
+		Error:       bus.Error,
+		DateCreated: bus.DateCreated.UTC(),
+		DateFailed:  bus.DateFailed.UTC(),
+	}
+
+	// The column doesn't allow null for events without parameters.
+	if db.RawParams == nil {
+		db.RawParams = []byte{}
+	}
+
+	return db
+}
+
+func toBusDeadLetter(db deadLetter) delegate.DeadLetter {
+	return delegate.DeadLetter{
+		ID: db.ID,
+		Data: delegate.Data{
+			ID:        db.EventID,
+			Domain:    db.Domain,
+			Action:    db.Action,
+			Version:   db.Version,
+			RawParams: db.RawParams,
+		},
+		Handler:     db.Handler,
+		Attempts:    db.Attempts,
+		Error:       db.Error,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateFailed:  db.DateFailed.In(time.Local),
+	}
+}
+
+func toBusDeadLetters(dbs []deadLetter) []delegate.DeadLetter {
+	bus := make([]delegate.DeadLetter, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusDeadLetter(db)
+	}
+
+	return bus
+}
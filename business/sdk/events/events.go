@@ -0,0 +1,45 @@
+// Package events provides the payloads of the public topics other teams
+// consume to react to changes of users, products and homes.
+//
+// The payloads are a contract with the consumers and are described by the
+// JSON schemas in the schemas directory. Fields are only ever added to a
+// version. A change that would break a consumer requires a new version of
+// the payload, published on a new topic next to the old one until the
+// consumers moved over.
+//
+// The events are written to the outbox with the change through the delegate
+// system, so an event is only published when the change is committed. The
+// relay publishes them straight to the topics with the entity id as the
+// ordering attribute, in the order they were written for the entity. Messages
+// with the same entity id are delivered in that order. When a user is
+// disabled, deleted or restored, every product and home of the user that
+// changed gets its own event, written in the same transaction as the change.
+package events
+
+import (
+	"embed"
+)
+
+// Version is the version of the payloads published on the topics.
+const Version = 1
+
+// Domain is the delegate domain the events are written to the outbox for.
+const Domain = "events"
+
+// Set of event types.
+const (
+	TypeCreated  = "created"
+	TypeUpdated  = "updated"
+	TypeDeleted  = "deleted"
+	TypeRestored = "restored"
+)
+
+// OrderingAttribute is the message attribute holding the entity id the
+// messages are ordered by.
+const OrderingAttribute = "entity_id"
+
+// Schemas holds the JSON schema of the payload of every topic, named after
+// the topic and the version.
+//
+//go:embed schemas/*.json
+var Schemas embed.FS
@@ -0,0 +1,446 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/productbus"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Schemas(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	evts := []delegate.Params{
+		events.UserEvent{
+			Version: events.Version,
+			Type:    events.TypeDeleted,
+			User:    events.User{Roles: []string{"USER"}, DateDeleted: &now},
+		},
+		events.ProductEvent{
+			Version: events.Version,
+			Type:    events.TypeDeleted,
+			Product: events.Product{DateDeleted: &now},
+		},
+		events.HomeEvent{
+			Version: events.Version,
+			Type:    events.TypeDeleted,
+			Home:    events.Home{DateDeleted: &now},
+		},
+	}
+
+	for _, evt := range evts {
+		sch := evt.Schema()
+		file := fmt.Sprintf("schemas/%s.v%d.json", sch.Action, sch.Version)
+
+		t.Run(sch.Action, func(t *testing.T) {
+			data, err := events.Schemas.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Should be able to read the schema : %s", err)
+			}
+
+			var schema map[string]any
+			if err := json.Unmarshal(data, &schema); err != nil {
+				t.Fatalf("Should be able to unmarshal the schema : %s", err)
+			}
+
+			payload, err := json.Marshal(evt)
+			if err != nil {
+				t.Fatalf("Should be able to marshal the event : %s", err)
+			}
+
+			var doc map[string]any
+			if err := json.Unmarshal(payload, &doc); err != nil {
+				t.Fatalf("Should be able to unmarshal the event : %s", err)
+			}
+
+			if diff := cmp.Diff(mismatches("", schema, doc), []string(nil)); diff != "" {
+				t.Errorf("Should match the schema:\n%s", diff)
+			}
+		})
+	}
+}
+
+// mismatches returns the fields of the document that are required and
+// missing or aren't described by the schema.
+func mismatches(path string, schema map[string]any, doc map[string]any) []string {
+	var bad []string
+
+	props, _ := schema["properties"].(map[string]any)
+
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		if _, exists := doc[name.(string)]; !exists {
+			bad = append(bad, "missing "+path+name.(string))
+		}
+	}
+
+	for name, value := range doc {
+		prop, exists := props[name].(map[string]any)
+		if !exists {
+			bad = append(bad, "undocumented "+path+name)
+			continue
+		}
+
+		if obj, ok := value.(map[string]any); ok {
+			bad = append(bad, mismatches(path+name+".", prop, obj)...)
+		}
+	}
+
+	sort.Strings(bad)
+
+	return bad
+}
+
+// =============================================================================
+
+func Test_Events(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, user(db), "user")
+	unitest.Run(t, product(db, sd), "product")
+	unitest.Run(t, home(db, sd), "home")
+	unitest.Run(t, cascade(db, sd), "cascade")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := sqldb.WithSystem(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func user(db *dbtest.Database) []unitest.Table {
+	var usrID uuid.UUID
+
+	table := []unitest.Table{
+		{
+			Name: "lifecycle",
+			ExpResp: []events.Emitted{
+				{Topic: events.UserTopic, Type: events.TypeCreated},
+				{Topic: events.UserTopic, Type: events.TypeUpdated},
+				{Topic: events.UserTopic, Type: events.TypeDeleted},
+				{Topic: events.UserTopic, Type: events.TypeRestored},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				mark := db.Events.Mark()
+
+				usr, err := db.BusDomain.User.Create(ctx, uuid.Nil, userbus.TestNewUsers(1, userbus.Roles.User)[0])
+				if err != nil {
+					return err
+				}
+				usrID = usr.ID
+
+				uu := userbus.UpdateUser{
+					Department: dbtest.StringPointer("Events"),
+				}
+
+				usr, err = db.BusDomain.User.Update(ctx, uuid.Nil, usr, uu)
+				if err != nil {
+					return err
+				}
+
+				if err := db.BusDomain.User.Delete(ctx, uuid.Nil, usr); err != nil {
+					return err
+				}
+
+				usr, err = db.BusDomain.User.QueryDeletedByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.User.Restore(ctx, uuid.Nil, usr); err != nil {
+					return err
+				}
+
+				return db.Events.Since(mark)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmpEmitted(got, exp, usrID)
+			},
+		},
+		{
+			Name:    "payload",
+			ExpResp: []any{events.Version, "Events", true},
+			ExcFunc: func(ctx context.Context) any {
+				mark := db.Events.Mark()
+
+				usr, err := db.BusDomain.User.Create(ctx, uuid.Nil, userbus.TestNewUsers(1, userbus.Roles.User)[0])
+				if err != nil {
+					return err
+				}
+
+				uu := userbus.UpdateUser{
+					Department: dbtest.StringPointer("Events"),
+				}
+
+				usr, err = db.BusDomain.User.Update(ctx, uuid.Nil, usr, uu)
+				if err != nil {
+					return err
+				}
+
+				if err := db.BusDomain.User.Delete(ctx, uuid.Nil, usr); err != nil {
+					return err
+				}
+
+				evts := db.Events.UserEventsSince(mark)
+				if len(evts) != 3 {
+					return fmt.Errorf("expected 3 events, got %d", len(evts))
+				}
+
+				return []any{evts[1].Version, evts[1].User.Department, evts[2].User.DateDeleted != nil}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func product(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	var prdID uuid.UUID
+
+	table := []unitest.Table{
+		{
+			Name: "lifecycle",
+			ExpResp: []events.Emitted{
+				{Topic: events.ProductTopic, Type: events.TypeCreated},
+				{Topic: events.ProductTopic, Type: events.TypeUpdated},
+				{Topic: events.ProductTopic, Type: events.TypeUpdated},
+				{Topic: events.ProductTopic, Type: events.TypeDeleted},
+				{Topic: events.ProductTopic, Type: events.TypeRestored},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				actorID := sd.Admins[0].ID
+				mark := db.Events.Mark()
+
+				prd, err := db.BusDomain.Product.Create(ctx, actorID, productbus.TestGenerateNewProducts(1, actorID)[0])
+				if err != nil {
+					return err
+				}
+				prdID = prd.ID
+
+				up := productbus.UpdateProduct{
+					Quantity: dbtest.IntPointer(10),
+				}
+
+				prd, err = db.BusDomain.Product.Update(ctx, actorID, prd, up)
+				if err != nil {
+					return err
+				}
+
+				prd, err = db.BusDomain.Product.ReduceQuantity(ctx, prd, 1)
+				if err != nil {
+					return err
+				}
+
+				if err := db.BusDomain.Product.Delete(ctx, actorID, prd); err != nil {
+					return err
+				}
+
+				prd, err = db.BusDomain.Product.QueryDeletedByID(ctx, prd.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.Product.Restore(ctx, actorID, prd); err != nil {
+					return err
+				}
+
+				return db.Events.Since(mark)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmpEmitted(got, exp, prdID)
+			},
+		},
+	}
+
+	return table
+}
+
+func home(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	var hmeID uuid.UUID
+
+	table := []unitest.Table{
+		{
+			Name: "lifecycle",
+			ExpResp: []events.Emitted{
+				{Topic: events.HomeTopic, Type: events.TypeCreated},
+				{Topic: events.HomeTopic, Type: events.TypeUpdated},
+				{Topic: events.HomeTopic, Type: events.TypeDeleted},
+				{Topic: events.HomeTopic, Type: events.TypeRestored},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				actorID := sd.Admins[0].ID
+				mark := db.Events.Mark()
+
+				hme, err := db.BusDomain.Home.Create(ctx, actorID, homebus.TestGenerateNewHomes(1, actorID)[0])
+				if err != nil {
+					return err
+				}
+				hmeID = hme.ID
+
+				uh := homebus.UpdateHome{
+					Type: &homebus.Types.Condo,
+				}
+
+				hme, err = db.BusDomain.Home.Update(ctx, actorID, hme, uh)
+				if err != nil {
+					return err
+				}
+
+				if err := db.BusDomain.Home.Delete(ctx, actorID, hme); err != nil {
+					return err
+				}
+
+				hme, err = db.BusDomain.Home.QueryDeletedByID(ctx, hme.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.Home.Restore(ctx, actorID, hme); err != nil {
+					return err
+				}
+
+				return db.Events.Since(mark)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmpEmitted(got, exp, hmeID)
+			},
+		},
+	}
+
+	return table
+}
+
+func cascade(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	var exp []events.Emitted
+
+	table := []unitest.Table{
+		{
+			Name: "delete",
+			ExcFunc: func(ctx context.Context) any {
+				actorID := sd.Admins[0].ID
+
+				usr, err := db.BusDomain.User.Create(ctx, uuid.Nil, userbus.TestNewUsers(1, userbus.Roles.User)[0])
+				if err != nil {
+					return err
+				}
+
+				exp = []events.Emitted{
+					{Topic: events.UserTopic, Type: events.TypeDeleted, EntityID: usr.ID.String()},
+				}
+
+				for _, np := range productbus.TestGenerateNewProducts(2, usr.ID) {
+					prd, err := db.BusDomain.Product.Create(ctx, actorID, np)
+					if err != nil {
+						return err
+					}
+
+					exp = append(exp, events.Emitted{Topic: events.ProductTopic, Type: events.TypeDeleted, EntityID: prd.ID.String()})
+				}
+
+				hme, err := db.BusDomain.Home.Create(ctx, actorID, homebus.TestGenerateNewHomes(1, usr.ID)[0])
+				if err != nil {
+					return err
+				}
+
+				exp = append(exp, events.Emitted{Topic: events.HomeTopic, Type: events.TypeDeleted, EntityID: hme.ID.String()})
+
+				mark := db.Events.Mark()
+
+				if err := db.BusDomain.User.Delete(ctx, uuid.Nil, usr); err != nil {
+					return err
+				}
+
+				return db.Events.Since(mark)
+			},
+			CmpFunc: func(got any, _ any) string {
+				gotResp, exists := got.([]events.Emitted)
+				if !exists {
+					return "error occurred"
+				}
+
+				// The events of the different entities are only ordered per
+				// entity, so they are compared in a fixed order.
+				byEntity := func(a, b events.Emitted) int {
+					if c := strings.Compare(a.Topic, b.Topic); c != 0 {
+						return c
+					}
+					return strings.Compare(a.EntityID, b.EntityID)
+				}
+
+				gotResp = slices.Clone(gotResp)
+				slices.SortFunc(gotResp, byEntity)
+
+				expResp := slices.Clone(exp)
+				slices.SortFunc(expResp, byEntity)
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+// cmpEmitted compares the emitted events after setting the id of the entity
+// the business call created on the expected events.
+func cmpEmitted(got any, exp any, entityID uuid.UUID) string {
+	gotResp, exists := got.([]events.Emitted)
+	if !exists {
+		return "error occurred"
+	}
+
+	expResp := slices.Clone(exp.([]events.Emitted))
+	for i := range expResp {
+		expResp[i].EntityID = entityID.String()
+	}
+
+	return cmp.Diff(gotResp, expResp)
+}
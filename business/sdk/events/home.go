@@ -0,0 +1,55 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// HomeTopic is the name of the topic home events are published on.
+const HomeTopic = "home-events"
+
+// HomeEvent is published on the home topic when a home is created, updated,
+// deleted or restored.
+type HomeEvent struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	HomeID      string    `json:"homeID"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Home        Home      `json:"home"`
+	OrderingKey string    `json:"-" pubsub-attr:"entity_id"`
+}
+
+// Home represents the home after the change.
+type Home struct {
+	ID          string      `json:"id"`
+	TenantID    string      `json:"tenantID"`
+	UserID      string      `json:"userID"`
+	Type        string      `json:"type"`
+	Address     HomeAddress `json:"address"`
+	Status      string      `json:"status"`
+	DateCreated time.Time   `json:"dateCreated"`
+	DateUpdated time.Time   `json:"dateUpdated"`
+	DateDeleted *time.Time  `json:"dateDeleted,omitempty"`
+}
+
+// HomeAddress represents the address of a home.
+type HomeAddress struct {
+	Address1 string `json:"address1"`
+	Address2 string `json:"address2"`
+	ZipCode  string `json:"zipCode"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	Country  string `json:"country"`
+}
+
+// Schema implements the delegate.Params interface.
+func (HomeEvent) Schema() delegate.Schema {
+	return delegate.Schema{Domain: Domain, Action: HomeTopic, Version: Version}
+}
+
+// EntityID implements the delegate.Ordered interface.
+func (evt HomeEvent) EntityID() string {
+	return evt.HomeID
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// ProductTopic is the name of the topic product events are published on.
+const ProductTopic = "product-events"
+
+// ProductEvent is published on the product topic when a product is created,
+// updated, deleted or restored.
+type ProductEvent struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ProductID   string    `json:"productID"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Product     Product   `json:"product"`
+	OrderingKey string    `json:"-" pubsub-attr:"entity_id"`
+}
+
+// Product represents the product after the change. The cost is in the
+// smallest unit of the currency.
+type Product struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenantID"`
+	UserID      string     `json:"userID"`
+	Name        string     `json:"name"`
+	CostCents   int64      `json:"costCents"`
+	Currency    string     `json:"currency"`
+	Quantity    int        `json:"quantity"`
+	Status      string     `json:"status"`
+	DateCreated time.Time  `json:"dateCreated"`
+	DateUpdated time.Time  `json:"dateUpdated"`
+	DateDeleted *time.Time `json:"dateDeleted,omitempty"`
+}
+
+// Schema implements the delegate.Params interface.
+func (ProductEvent) Schema() delegate.Schema {
+	return delegate.Schema{Domain: Domain, Action: ProductTopic, Version: Version}
+}
+
+// EntityID implements the delegate.Ordered interface.
+func (evt ProductEvent) EntityID() string {
+	return evt.ProductID
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// Emitted identifies an event emitted by a business call.
+type Emitted struct {
+	Topic    string
+	Type     string
+	EntityID string
+}
+
+type record struct {
+	emitted Emitted
+	event   any
+}
+
+// Recorder keeps the events emitted through a delegate, so tests can assert
+// which events a business call emits.
+type Recorder struct {
+	mu      sync.Mutex
+	records []record
+}
+
+// NewRecorder constructs a recorder for the events emitted through the
+// delegate.
+func NewRecorder(d *delegate.Delegate) *Recorder {
+	var r Recorder
+
+	delegate.Register(d, "events.recorder-user", r.user)
+	delegate.Register(d, "events.recorder-product", r.product)
+	delegate.Register(d, "events.recorder-home", r.home)
+
+	return &r
+}
+
+// Mark returns the position of the next event, so the events emitted from
+// now on can be retrieved with Since.
+func (r *Recorder) Mark() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.records)
+}
+
+// Since returns the events emitted after the mark in the order they were
+// emitted.
+func (r *Recorder) Since(mark int) []Emitted {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var emitted []Emitted
+	for _, rec := range r.records[mark:] {
+		emitted = append(emitted, rec.emitted)
+	}
+
+	return emitted
+}
+
+// UserEventsSince returns the user events emitted after the mark.
+func (r *Recorder) UserEventsSince(mark int) []UserEvent {
+	return since[UserEvent](r, mark)
+}
+
+// ProductEventsSince returns the product events emitted after the mark.
+func (r *Recorder) ProductEventsSince(mark int) []ProductEvent {
+	return since[ProductEvent](r, mark)
+}
+
+// HomeEventsSince returns the home events emitted after the mark.
+func (r *Recorder) HomeEventsSince(mark int) []HomeEvent {
+	return since[HomeEvent](r, mark)
+}
+
+// =============================================================================
+
+func (r *Recorder) user(ctx context.Context, claim delegate.Claim, evt UserEvent) error {
+	r.add(Emitted{Topic: UserTopic, Type: evt.Type, EntityID: evt.UserID}, evt)
+	return nil
+}
+
+func (r *Recorder) product(ctx context.Context, claim delegate.Claim, evt ProductEvent) error {
+	r.add(Emitted{Topic: ProductTopic, Type: evt.Type, EntityID: evt.ProductID}, evt)
+	return nil
+}
+
+func (r *Recorder) home(ctx context.Context, claim delegate.Claim, evt HomeEvent) error {
+	r.add(Emitted{Topic: HomeTopic, Type: evt.Type, EntityID: evt.HomeID}, evt)
+	return nil
+}
+
+func (r *Recorder) add(emitted Emitted, event any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record{emitted: emitted, event: event})
+}
+
+func since[T any](r *Recorder, mark int) []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var evts []T
+	for _, rec := range r.records[mark:] {
+		if evt, ok := rec.event.(T); ok {
+			evts = append(evts, evt)
+		}
+	}
+
+	return evts
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// UserTopic is the name of the topic user events are published on.
+const UserTopic = "user-events"
+
+// UserEvent is published on the user topic when a user is created, updated,
+// deleted or restored.
+type UserEvent struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	UserID      string    `json:"userID"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	User        User      `json:"user"`
+	OrderingKey string    `json:"-" pubsub-attr:"entity_id"`
+}
+
+// User represents the user after the change. The name and the email address
+// are personal data and aren't shared with the consumers.
+type User struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenantID"`
+	Roles         []string   `json:"roles"`
+	Department    string     `json:"department"`
+	Enabled       bool       `json:"enabled"`
+	EmailVerified bool       `json:"emailVerified"`
+	DateCreated   time.Time  `json:"dateCreated"`
+	DateUpdated   time.Time  `json:"dateUpdated"`
+	DateDeleted   *time.Time `json:"dateDeleted,omitempty"`
+}
+
+// Schema implements the delegate.Params interface.
+func (UserEvent) Schema() delegate.Schema {
+	return delegate.Schema{Domain: Domain, Action: UserTopic, Version: Version}
+}
+
+// EntityID implements the delegate.Ordered interface.
+func (evt UserEvent) EntityID() string {
+	return evt.UserID
+}
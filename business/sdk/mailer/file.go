@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileMailer writes every message to its own file in a directory instead of
+// sending it. It is meant for local development and tests where the messages
+// need to be read back.
+type FileMailer struct {
+	dir string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewFileMailer constructs a mailer that writes messages to the directory.
+// The directory is created if it doesn't exist.
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+
+	return &FileMailer{
+		dir: dir,
+	}, nil
+}
+
+// Send writes the message to a new file in the directory.
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+
+	// The name sorts in the order the messages were sent.
+	name := fmt.Sprintf("%s-%06d.json", time.Now().UTC().Format("20060102T150405"), m.seq)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+
+	return nil
+}
+
+// Messages reads back the messages in the directory in the order they were
+// sent.
+func (m *FileMailer) Messages() ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names, err := filepath.Glob(filepath.Join(m.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing messages: %w", err)
+	}
+	sort.Strings(names)
+
+	msgs := make([]Message, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading message[%s]: %w", name, err)
+		}
+
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("unmarshal message[%s]: %w", name, err)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
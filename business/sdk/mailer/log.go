@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/ardanlabs/encore/foundation/logger"
+)
+
+// LogMailer writes every message to the log instead of sending it. It is
+// meant for local development.
+type LogMailer struct {
+	log *logger.Logger
+}
+
+// NewLogMailer constructs a mailer that writes messages to the log.
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{
+		log: log,
+	}
+}
+
+// Send writes the message to the log.
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.log.Info(ctx, "mailer", "to", msg.To.String(), "subject", msg.Subject, "body", msg.Body)
+
+	return nil
+}
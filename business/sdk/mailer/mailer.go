@@ -0,0 +1,21 @@
+// Package mailer provides support for sending email to users. The business
+// layer depends on the Mailer interface so the way mail is delivered can be
+// chosen when the service is constructed.
+package mailer
+
+import (
+	"context"
+	"net/mail"
+)
+
+// Message represents an email to be sent to a single recipient.
+type Message struct {
+	To      mail.Address `json:"to"`
+	Subject string       `json:"subject"`
+	Body    string       `json:"body"`
+}
+
+// Mailer declares the behaviour needed to send an email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -17,10 +17,21 @@ var directions = map[string]string{
 	DESC: "DESC",
 }
 
-// By represents a field used to order by and direction.
+// Set of null orderings for data ordering. An empty value uses the database
+// default, which places nulls last for ASC and first for DESC.
+const (
+	NullsFirst = "NULLS FIRST"
+	NullsLast  = "NULLS LAST"
+)
+
+// By represents a field used to order by and direction. When more than one
+// field is used, the fields that break ties in the previous ordering follow
+// in Then.
 type By struct {
 	Field     string
 	Direction string
+	Nulls     string
+	Then      []By
 }
 
 // NewBy constructs a new By value with no checks.
@@ -38,34 +49,108 @@ func NewBy(field string, direction string) By {
 	}
 }
 
+// ThenBy returns a copy of the By value that additionally orders by the
+// specified field when the previous fields are equal.
+func (b By) ThenBy(field string, direction string) By {
+	then := make([]By, len(b.Then), len(b.Then)+1)
+	copy(then, b.Then)
+
+	b.Then = append(then, NewBy(field, direction))
+
+	return b
+}
+
+// Keys returns the list of fields to order by in order of precedence.
+func (b By) Keys() []By {
+	keys := make([]By, 0, len(b.Then)+1)
+	keys = append(keys, By{Field: b.Field, Direction: b.Direction, Nulls: b.Nulls})
+
+	for _, then := range b.Then {
+		keys = append(keys, By{Field: then.Field, Direction: then.Direction, Nulls: then.Nulls})
+	}
+
+	return keys
+}
+
+// String returns the ordering in the form of "field,direction;field,direction".
+func (b By) String() string {
+	keys := b.Keys()
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.Field + "," + key.Direction
+		if key.Nulls != "" {
+			parts[i] += "," + key.Nulls
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
 // Parse constructs a By value by parsing a string in the form of
-// "field,direction" ie "user_id,ASC".
+// "field,direction" ie "user_id,ASC". More than one field can be provided
+// as a comma or semicolon separated list, and each field can be followed by
+// a direction and NULLS FIRST or NULLS LAST, ie "name,DESC;cost NULLS LAST".
 func Parse(fieldMappings map[string]string, orderBy string, defaultOrder By) (By, error) {
-	if orderBy == "" {
+	if strings.TrimSpace(orderBy) == "" {
 		return defaultOrder, nil
 	}
 
-	orderParts := strings.Split(orderBy, ",")
+	tokens := strings.FieldsFunc(orderBy, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
 
-	orgFieldName := strings.TrimSpace(orderParts[0])
-	fieldName, exists := fieldMappings[orgFieldName]
-	if !exists {
-		return By{}, fmt.Errorf("unknown order: %s", orgFieldName)
-	}
+	var keys []By
+	fields := make(map[string]bool)
 
-	switch len(orderParts) {
-	case 1:
-		return NewBy(fieldName, ASC), nil
+	for i := 0; i < len(tokens); i++ {
+		token := strings.ToUpper(tokens[i])
 
-	case 2:
-		direction := strings.TrimSpace(orderParts[1])
-		if _, exists := directions[direction]; !exists {
-			return By{}, fmt.Errorf("unknown direction: %s", direction)
-		}
+		switch {
+		case directions[token] != "":
+			if len(keys) == 0 {
+				return By{}, fmt.Errorf("unknown order: %s", tokens[i])
+			}
+			keys[len(keys)-1].Direction = directions[token]
+
+		case token == "NULLS":
+			if len(keys) == 0 || i+1 == len(tokens) {
+				return By{}, fmt.Errorf("unknown order: %s", orderBy)
+			}
+
+			i++
+			switch strings.ToUpper(tokens[i]) {
+			case "FIRST":
+				keys[len(keys)-1].Nulls = NullsFirst
+			case "LAST":
+				keys[len(keys)-1].Nulls = NullsLast
+			default:
+				return By{}, fmt.Errorf("unknown nulls: %s", tokens[i])
+			}
 
-		return NewBy(fieldName, direction), nil
+		default:
+			fieldName, exists := fieldMappings[tokens[i]]
+			if !exists {
+				return By{}, fmt.Errorf("unknown order: %s", tokens[i])
+			}
 
-	default:
+			if fields[fieldName] {
+				return By{}, fmt.Errorf("duplicate order: %s", tokens[i])
+			}
+			fields[fieldName] = true
+
+			keys = append(keys, NewBy(fieldName, ASC))
+		}
+	}
+
+	if len(keys) == 0 {
 		return By{}, fmt.Errorf("unknown order: %s", orderBy)
 	}
+
+	by := keys[0]
+	if len(keys) > 1 {
+		by.Then = keys[1:]
+	}
+
+	return by, nil
 }
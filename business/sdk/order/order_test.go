@@ -0,0 +1,111 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/google/go-cmp/cmp"
+)
+
+var fieldMappings = map[string]string{
+	"product_id": "product_id",
+	"user_name":  "user_name",
+	"cost":       "cost",
+}
+
+var defaultOrderBy = order.NewBy("product_id", order.ASC)
+
+func Test_Parse(t *testing.T) {
+	table := []struct {
+		name    string
+		orderBy string
+		exp     order.By
+		err     bool
+	}{
+		{
+			name:    "default",
+			orderBy: "",
+			exp:     defaultOrderBy,
+		},
+		{
+			name:    "single",
+			orderBy: "cost",
+			exp:     order.NewBy("cost", order.ASC),
+		},
+		{
+			name:    "direction",
+			orderBy: "cost,DESC",
+			exp:     order.NewBy("cost", order.DESC),
+		},
+		{
+			name:    "multiple",
+			orderBy: "user_name,ASC;cost,DESC",
+			exp:     order.NewBy("user_name", order.ASC).ThenBy("cost", order.DESC),
+		},
+		{
+			name:    "commas",
+			orderBy: "user_name,desc,cost",
+			exp:     order.NewBy("user_name", order.DESC).ThenBy("cost", order.ASC),
+		},
+		{
+			name:    "nulls",
+			orderBy: "user_name DESC NULLS LAST; cost",
+			exp: order.By{
+				Field:     "user_name",
+				Direction: order.DESC,
+				Nulls:     order.NullsLast,
+				Then:      []order.By{order.NewBy("cost", order.ASC)},
+			},
+		},
+		{
+			name:    "unknown",
+			orderBy: "name",
+			err:     true,
+		},
+		{
+			name:    "duplicate",
+			orderBy: "cost,ASC;cost,DESC",
+			err:     true,
+		},
+		{
+			name:    "direction-first",
+			orderBy: "DESC,cost",
+			err:     true,
+		},
+		{
+			name:    "nulls-bad",
+			orderBy: "cost NULLS MIDDLE",
+			err:     true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := order.Parse(fieldMappings, tt.orderBy, defaultOrderBy)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("Should get an error for %q", tt.orderBy)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Should be able to parse %q: %s", tt.orderBy, err)
+			}
+
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Fatalf("Should get the expected order:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_String(t *testing.T) {
+	by := order.NewBy("user_name", order.DESC).ThenBy("cost", order.ASC)
+	by.Nulls = order.NullsFirst
+
+	exp := "user_name,DESC,NULLS FIRST;cost,ASC"
+	if got := by.String(); got != exp {
+		t.Fatalf("Should get %q, got %q", exp, got)
+	}
+}
@@ -0,0 +1,14 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+)
+
+// Event represents a delegate event that was written to the outbox.
+type Event struct {
+	Data        delegate.Data
+	DateCreated time.Time
+	DateSent    time.Time
+}
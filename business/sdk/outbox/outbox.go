@@ -0,0 +1,150 @@
+// Package outbox provides support for publishing delegate events as part of
+// the transaction of the change that raised them. Events are written to the
+// outbox table and a relay publishes them once the transaction commits.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+)
+
+// Storer interface declares the behaviour this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, evt Event) error
+	QueryPending(ctx context.Context, limit int) ([]Event, error)
+	MarkSent(ctx context.Context, eventID string, dateSent time.Time) error
+	Purge(ctx context.Context, sentBefore time.Time) (int, error)
+}
+
+// Outbox manages the set of APIs for writing and relaying events.
+type Outbox struct {
+	log      *logger.Logger
+	beginner sqldb.Beginner
+	storer   Storer
+}
+
+// New constructs an outbox for use. The beginner starts the transactions the
+// relay claims pending events with.
+func New(log *logger.Logger, beginner sqldb.Beginner, storer Storer) *Outbox {
+	return &Outbox{
+		log:      log,
+		beginner: beginner,
+		storer:   storer,
+	}
+}
+
+// Publish implements the delegate.Publisher interface. The event is written
+// to the outbox on its own.
+func (o *Outbox) Publish(ctx context.Context, data delegate.Data) error {
+	return o.create(ctx, o.storer, data)
+}
+
+// PublishWithTx implements the delegate.TxPublisher interface. The event is
+// written to the outbox as part of the transaction.
+func (o *Outbox) PublishWithTx(ctx context.Context, tx sqldb.CommitRollbacker, data delegate.Data) error {
+	storer, err := o.storer.NewWithTx(tx)
+	if err != nil {
+		return err
+	}
+
+	return o.create(ctx, storer, data)
+}
+
+// Relay publishes up to a batch of pending events and marks them as sent. The
+// events are locked while they are published so relays running at the same
+// time never publish the same events. A batch holds at most one event per
+// ordering key, the oldest pending one, so the events of a key are published
+// in the order they were written even when relays run at the same time. If
+// the relay stops before the batch is done, none of the events are marked and
+// the whole batch is published again by the next relay. The handlers have to
+// be idempotent for that reason. A batch that is published again never holds
+// a later event of a key, so the order is kept. The number of events
+// published is returned and the relay is done once it's zero.
+func (o *Outbox) Relay(ctx context.Context, pub delegate.Publisher, batch int) (int, error) {
+	tx, err := o.beginner.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+
+	// The events are only marked as sent when the whole batch is published,
+	// otherwise the transaction is rolled back.
+	hasCommitted := false
+	defer func() {
+		if hasCommitted {
+			return
+		}
+
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			o.log.Error(ctx, "outbox relay", "msg", "rollback", "ERROR", err)
+		}
+	}()
+
+	storer, err := o.storer.NewWithTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	evts, err := storer.QueryPending(ctx, batch)
+	if err != nil {
+		return 0, fmt.Errorf("querypending: %w", err)
+	}
+
+	for _, evt := range evts {
+		if err := pub.Publish(ctx, evt.Data); err != nil {
+			return 0, fmt.Errorf("publish: eventID[%s]: %w", evt.Data.ID, err)
+		}
+
+		if err := storer.MarkSent(ctx, evt.Data.ID, time.Now()); err != nil {
+			return 0, fmt.Errorf("marksent: eventID[%s]: %w", evt.Data.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+
+	hasCommitted = true
+
+	return len(evts), nil
+}
+
+// Purge permanently removes the events that were sent longer ago than the
+// specified retention window.
+func (o *Outbox) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	sentBefore := time.Now().Add(-retention)
+
+	n, err := o.storer.Purge(ctx, sentBefore)
+	if err != nil {
+		return 0, fmt.Errorf("purge: sentBefore[%s]: %w", sentBefore.Format(time.RFC3339), err)
+	}
+
+	return n, nil
+}
+
+// =============================================================================
+
+func (o *Outbox) create(ctx context.Context, storer Storer, data delegate.Data) error {
+	if data.ID == "" {
+		return errors.New("event id is missing")
+	}
+
+	evt := Event{
+		Data:        data,
+		DateCreated: time.Now(),
+	}
+
+	if err := storer.Create(ctx, evt); err != nil {
+		return fmt.Errorf("create: eventID[%s]: %w", data.ID, err)
+	}
+
+	return nil
+}
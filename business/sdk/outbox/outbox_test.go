@@ -0,0 +1,508 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"encore.dev/et"
+	"github.com/ardanlabs/encore/business/domain/userbus"
+	"github.com/ardanlabs/encore/business/domain/userbus/stores/userdb"
+	"github.com/ardanlabs/encore/business/sdk/dbtest"
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/delegate/stores/delegatedb"
+	"github.com/ardanlabs/encore/business/sdk/outbox"
+	"github.com/ardanlabs/encore/business/sdk/outbox/stores/outboxdb"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/business/sdk/unitest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Outbox(t *testing.T) {
+	t.Parallel()
+
+	edb, err := et.NewTestDatabase(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("Creating new database: %s", err)
+	}
+
+	db := dbtest.NewDatabase(t, edb)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, tran(db), "tran")
+	unitest.Run(t, relay(db), "relay")
+}
+
+// =============================================================================
+
+// errKilled stands for the relay process dying in the middle of a batch.
+var errKilled = errors.New("relay killed")
+
+// deliverer delivers the published events to the delegate handlers like the
+// delegate subscription does. The relay is killed when the event at the kill
+// position is published.
+type deliverer struct {
+	delegate  *delegate.Delegate
+	published int
+	kill      int
+	before    func(ctx context.Context) error
+}
+
+func (d *deliverer) Publish(ctx context.Context, data delegate.Data) error {
+	d.published++
+
+	if d.published == d.kill {
+		panic(errKilled)
+	}
+
+	if d.before != nil {
+		before := d.before
+		d.before = nil
+
+		if err := before(ctx); err != nil {
+			return err
+		}
+	}
+
+	return d.delegate.Handle(ctx, data)
+}
+
+// collector keeps the ids of the events in the order they were published.
+type collector struct {
+	ids []string
+}
+
+func (c *collector) Publish(ctx context.Context, data delegate.Data) error {
+	c.ids = append(c.ids, data.ID)
+	return nil
+}
+
+// effects counts how many times the handler ran for each event.
+type effects map[string]int
+
+func (e effects) handle(ctx context.Context, claim delegate.Claim, data delegate.Data) error {
+	e[data.ID]++
+	return nil
+}
+
+// once returns the events that didn't have exactly one effect.
+func (e effects) once(ids []string) []string {
+	var bad []string
+	for _, id := range ids {
+		if e[id] != 1 {
+			bad = append(bad, fmt.Sprintf("%s:%d", id, e[id]))
+		}
+	}
+
+	return bad
+}
+
+func newOutbox(db *dbtest.Database) *outbox.Outbox {
+	return outbox.New(db.Log, sqldb.NewBeginner(db.DB), outboxdb.NewStore(db.Log, db.DB))
+}
+
+// relayKilled runs the relay and recovers when it is killed.
+func relayKilled(ctx context.Context, ob *outbox.Outbox, pub delegate.Publisher, batch int) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+
+	return ob.Relay(ctx, pub, batch)
+}
+
+// drain publishes the events left behind by earlier tests.
+func drain(ctx context.Context, db *dbtest.Database, ob *outbox.Outbox) error {
+	pub := deliverer{delegate: delegate.New(db.Log, nil, nil, nil, nil)}
+
+	for {
+		n, err := ob.Relay(ctx, &pub, 100)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+}
+
+// publish writes the events to the outbox in committed transactions.
+func publish(ctx context.Context, db *dbtest.Database, d *delegate.Delegate, action string, n int) ([]string, error) {
+	return publishOrdered(ctx, db, d, action, make([]string, n))
+}
+
+// publishOrdered writes an event with each of the ordering keys to the outbox
+// in committed transactions.
+func publishOrdered(ctx context.Context, db *dbtest.Database, d *delegate.Delegate, action string, keys []string) ([]string, error) {
+	ids := make([]string, len(keys))
+	for i := range ids {
+		tx, err := sqldb.NewBeginner(db.DB).Begin()
+		if err != nil {
+			return nil, err
+		}
+
+		data := delegate.Data{ID: fmt.Sprintf("%s-%d", action, i), Domain: "test", Action: action, OrderingKey: keys[i]}
+		if err := d.NewWithTx(tx).Publish(ctx, data); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+
+		ids[i] = data.ID
+	}
+
+	return ids, nil
+}
+
+// =============================================================================
+
+func tran(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "rollback",
+			ExpResp: []any{1, []string(nil)},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				eff := effects{}
+				d.Register("test", "rollback", "effects", eff.handle)
+
+				// The event of a change that is rolled back is never sent.
+				tx, err := sqldb.NewBeginner(db.DB).Begin()
+				if err != nil {
+					return err
+				}
+
+				if err := d.NewWithTx(tx).Publish(ctx, delegate.Data{ID: "rolled-back", Domain: "test", Action: "rollback"}); err != nil {
+					return err
+				}
+
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				ids, err := publish(ctx, db, d, "rollback", 1)
+				if err != nil {
+					return err
+				}
+
+				pub := deliverer{delegate: d}
+
+				n, err := ob.Relay(ctx, &pub, 10)
+				if err != nil {
+					return err
+				}
+
+				if eff["rolled-back"] != 0 {
+					return errors.New("expected the rolled back event to not be sent")
+				}
+
+				return []any{n, eff.once(ids)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "user",
+			ExpResp: []any{0, true},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+				userBus := userbus.NewBusiness(db.Log, nil, d, userdb.NewStore(db.Log, db.DB))
+
+				usrs, err := userbus.TestSeedUsers(ctx, 1, userbus.Roles.User, db.BusDomain.User)
+				if err != nil {
+					return err
+				}
+
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				// The events of an update that is rolled back never reach
+				// the outbox.
+				tx, err := sqldb.NewBeginner(db.DB).Begin()
+				if err != nil {
+					return err
+				}
+
+				bus, err := userBus.NewWithTx(tx)
+				if err != nil {
+					tx.Rollback()
+					return err
+				}
+
+				uu := userbus.UpdateUser{
+					Department: dbtest.StringPointer("Rolled Back"),
+				}
+
+				if _, err := bus.Update(ctx, uuid.Nil, usrs[0], uu); err != nil {
+					tx.Rollback()
+					return err
+				}
+
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				rolledBack, err := ob.Relay(ctx, &deliverer{delegate: d}, 10)
+				if err != nil {
+					return err
+				}
+
+				// The same update outside of a transaction does.
+				if _, err := userBus.Update(ctx, uuid.Nil, usrs[0], uu); err != nil {
+					return err
+				}
+
+				committed, err := ob.Relay(ctx, &deliverer{delegate: d}, 10)
+				if err != nil {
+					return err
+				}
+
+				return []any{rolledBack, committed > 0}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func relay(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "killed",
+			ExpResp: []any{true, 3, 0, []string(nil)},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				eff := effects{}
+				d.Register("test", "killed", "effects", eff.handle)
+
+				ids, err := publish(ctx, db, d, "killed", 3)
+				if err != nil {
+					return err
+				}
+
+				// The first event is delivered before the relay is killed, but
+				// it isn't marked as sent.
+				_, err = relayKilled(ctx, ob, &deliverer{delegate: d, kill: 2}, 10)
+				killed := errors.Is(err, errKilled)
+
+				// The next relay publishes the whole batch again and the
+				// handler doesn't run twice for the first event.
+				n, err := ob.Relay(ctx, &deliverer{delegate: d}, 10)
+				if err != nil {
+					return err
+				}
+
+				left, err := ob.Relay(ctx, &deliverer{delegate: d}, 10)
+				if err != nil {
+					return err
+				}
+
+				return []any{killed, n, left, eff.once(ids)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "locked",
+			ExpResp: []any{1, 2, []string(nil)},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				eff := effects{}
+				d.Register("test", "locked", "effects", eff.handle)
+
+				ids, err := publish(ctx, db, d, "locked", 3)
+				if err != nil {
+					return err
+				}
+
+				// A second relay runs while the first one is publishing and
+				// skips the event the first one holds.
+				var second int
+				pub := deliverer{
+					delegate: d,
+					before: func(ctx context.Context) error {
+						var err error
+						second, err = ob.Relay(ctx, &deliverer{delegate: d}, 10)
+						return err
+					},
+				}
+
+				first, err := ob.Relay(ctx, &pub, 1)
+				if err != nil {
+					return err
+				}
+
+				return []any{first, second, eff.once(ids)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "ordered",
+			ExpResp: []any{1, []string{"ordered-2"}, []string{"ordered-1"}, 0},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				if _, err := publishOrdered(ctx, db, d, "ordered", []string{"a", "a", "b"}); err != nil {
+					return err
+				}
+
+				// A second relay runs while the first one is publishing the
+				// first event of a key and skips the later event of the key.
+				var second collector
+				pub := deliverer{
+					delegate: d,
+					before: func(ctx context.Context) error {
+						_, err := ob.Relay(ctx, &second, 10)
+						return err
+					},
+				}
+
+				first, err := ob.Relay(ctx, &pub, 1)
+				if err != nil {
+					return err
+				}
+
+				var third collector
+				if _, err := ob.Relay(ctx, &third, 10); err != nil {
+					return err
+				}
+
+				left, err := ob.Relay(ctx, &collector{}, 10)
+				if err != nil {
+					return err
+				}
+
+				return []any{first, second.ids, third.ids, left}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "serialized",
+			ExpResp: []any{true, []string{"serialized-0", "serialized-1"}},
+			ExcFunc: func(ctx context.Context) any {
+				ob := newOutbox(db)
+				if err := drain(ctx, db, ob); err != nil {
+					return err
+				}
+
+				d := delegate.New(db.Log, sqldb.NewBeginner(db.DB), ob, delegatedb.NewStore(db.Log, db.DB), nil)
+
+				tx, err := sqldb.NewBeginner(db.DB).Begin()
+				if err != nil {
+					return err
+				}
+
+				data := delegate.Data{ID: "serialized-0", Domain: "test", Action: "serialized", OrderingKey: "s"}
+				if err := d.NewWithTx(tx).Publish(ctx, data); err != nil {
+					tx.Rollback()
+					return err
+				}
+
+				// A second writer of the key has to wait for the first one to
+				// commit before it takes its place in the sequence.
+				done := make(chan error, 1)
+				go func() {
+					tx, err := sqldb.NewBeginner(db.DB).Begin()
+					if err != nil {
+						done <- err
+						return
+					}
+
+					data := delegate.Data{ID: "serialized-1", Domain: "test", Action: "serialized", OrderingKey: "s"}
+					if err := d.NewWithTx(tx).Publish(ctx, data); err != nil {
+						tx.Rollback()
+						done <- err
+						return
+					}
+
+					done <- tx.Commit()
+				}()
+
+				var waited bool
+				select {
+				case err := <-done:
+					tx.Rollback()
+					if err != nil {
+						return err
+					}
+				case <-time.After(100 * time.Millisecond):
+					waited = true
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				if waited {
+					if err := <-done; err != nil {
+						return err
+					}
+				}
+
+				var pub collector
+				for {
+					n, err := ob.Relay(ctx, &pub, 10)
+					if err != nil {
+						return err
+					}
+					if n == 0 {
+						break
+					}
+				}
+
+				return []any{waited, pub.ids}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,72 @@
+package outboxdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/outbox"
+)
+
+type event struct {
+	ID          string       `db:"event_id"`
+	Domain      string       `db:"domain"`
+	Action      string       `db:"action"`
+	Version     int          `db:"version"`
+	OrderingKey string       `db:"ordering_key"`
+	RawParams   []byte       `db:"raw_params"`
+	DateCreated time.Time    `db:"date_created"`
+	DateSent    sql.NullTime `db:"date_sent"`
+}
+
+func toDBEvent(bus outbox.Event) event {
+	db := event{
+		ID:          bus.Data.ID,
+		Domain:      bus.Data.Domain,
+		Action:      bus.Data.Action,
+		Version:     bus.Data.Version,
+		OrderingKey: bus.Data.OrderingKey,
+		RawParams:   bus.Data.RawParams,
+		DateCreated: bus.DateCreated.UTC(),
+		DateSent: sql.NullTime{
+			Time:  bus.DateSent.UTC(),
+			Valid: !bus.DateSent.IsZero(),
+		},
+	}
+
+	// The column doesn't allow null for events without parameters.
+	if db.RawParams == nil {
+		db.RawParams = []byte{}
+	}
+
+	return db
+}
+
+func toBusEvent(db event) outbox.Event {
+	bus := outbox.Event{
+		Data: delegate.Data{
+			ID:          db.ID,
+			Domain:      db.Domain,
+			Action:      db.Action,
+			Version:     db.Version,
+			OrderingKey: db.OrderingKey,
+			RawParams:   db.RawParams,
+		},
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateSent.Valid {
+		bus.DateSent = db.DateSent.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+func toBusEvents(dbs []event) []outbox.Event {
+	bus := make([]outbox.Event, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusEvent(db)
+	}
+
+	return bus
+}
@@ -0,0 +1,168 @@
+// Package outboxdb contains outbox related CRUD functionality.
+package outboxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/encore/business/sdk/outbox"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/ardanlabs/encore/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for outbox database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (outbox.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new event into the outbox. The sequence of an event is
+// taken when it's inserted, not when its transaction commits, so writers of
+// the same ordering key are serialized by a lock held until their
+// transaction ends. Otherwise a later event could commit first and be sent
+// before an earlier one. The store has to be used inside the transaction of
+// the change for the lock to last.
+func (s *Store) Create(ctx context.Context, evt outbox.Event) error {
+	dbEvt := toDBEvent(evt)
+
+	if dbEvt.OrderingKey != "" {
+		const lock = `
+		SELECT pg_advisory_xact_lock(hashtextextended(:ordering_key, 0))`
+
+		if err := sqldb.NamedExecContext(ctx, s.log, s.db, lock, dbEvt); err != nil {
+			return fmt.Errorf("namedexeccontext: lock: %w", err)
+		}
+	}
+
+	const q = `
+	INSERT INTO outbox
+		(event_id, domain, action, version, ordering_key, raw_params, date_created, date_sent)
+	VALUES
+		(:event_id, :domain, :action, :version, :ordering_key, :raw_params, :date_created, :date_sent)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbEvt); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryPending retrieves the oldest events that weren't sent yet in the order
+// they were written. Only the oldest pending event of an ordering key is
+// retrieved, so an event is never sent before an earlier one of the same key.
+// The events are locked until the transaction ends and events locked by
+// another transaction are skipped, so the store has to be used inside a
+// transaction.
+func (s *Store) QueryPending(ctx context.Context, limit int) ([]outbox.Event, error) {
+	data := struct {
+		Limit int `db:"limit"`
+	}{
+		Limit: limit,
+	}
+
+	const q = `
+	SELECT
+		o.event_id, o.domain, o.action, o.version, o.ordering_key, o.raw_params, o.date_created, o.date_sent
+	FROM
+		outbox AS o
+	WHERE
+		o.date_sent IS NULL AND
+		(o.ordering_key = '' OR NOT EXISTS (
+			SELECT 1 FROM outbox AS p
+			WHERE p.date_sent IS NULL AND p.ordering_key = o.ordering_key AND p.seq < o.seq
+		))
+	ORDER BY
+		o.seq
+	LIMIT :limit
+	FOR UPDATE OF o SKIP LOCKED`
+
+	var dbEvts []event
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEvents(dbEvts), nil
+}
+
+// MarkSent records when the event was sent.
+func (s *Store) MarkSent(ctx context.Context, eventID string, dateSent time.Time) error {
+	data := struct {
+		ID       string    `db:"event_id"`
+		DateSent time.Time `db:"date_sent"`
+	}{
+		ID:       eventID,
+		DateSent: dateSent.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		outbox
+	SET
+		date_sent = :date_sent
+	WHERE
+		event_id = :event_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Purge removes the events that were sent before the specified time.
+func (s *Store) Purge(ctx context.Context, sentBefore time.Time) (int, error) {
+	data := struct {
+		SentBefore time.Time `db:"sent_before"`
+	}{
+		SentBefore: sentBefore.UTC(),
+	}
+
+	const q = `
+	WITH purged AS (
+		DELETE FROM
+			outbox
+		WHERE
+			date_sent < :sent_before
+		RETURNING
+			event_id
+	)
+	SELECT
+		count(1) AS count
+	FROM
+		purged`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,104 @@
+package page
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+)
+
+// CursorStart is the cursor value a client provides to request the first
+// page of results in cursor mode.
+const CursorStart = "start"
+
+// Cursor represents the position of the last row of a page. It captures the
+// values of the order by fields and the id of the row so a store can resume
+// after that row without counting or skipping rows.
+type Cursor struct {
+	orderBy string
+	values  []string
+	id      string
+}
+
+// NewCursor constructs a cursor for the row with the specified order by
+// values and id. There must be a value for every field in the ordering.
+func NewCursor(orderBy order.By, values []string, id string) Cursor {
+	return Cursor{
+		orderBy: orderBy.String(),
+		values:  values,
+		id:      id,
+	}
+}
+
+// String encodes the cursor into the opaque value handed to clients.
+func (c Cursor) String() string {
+	if c.isStart() {
+		return CursorStart
+	}
+
+	d := cursorDocument{
+		OrderBy: c.orderBy,
+		Values:  c.values,
+		ID:      c.id,
+	}
+
+	data, _ := json.Marshal(d)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Values returns the order by values of the row the cursor points at, in the
+// order of the fields in the ordering.
+func (c Cursor) Values() []string {
+	return c.values
+}
+
+// ID returns the id of the row the cursor points at.
+func (c Cursor) ID() string {
+	return c.id
+}
+
+func (c Cursor) isStart() bool {
+	return c.id == ""
+}
+
+// =============================================================================
+
+type cursorDocument struct {
+	OrderBy string   `json:"o"`
+	Values  []string `json:"v"`
+	ID      string   `json:"i"`
+}
+
+func decodeCursor(cursor string, orderBy order.By) (Cursor, error) {
+	if cursor == CursorStart {
+		return Cursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("cursor decode: %w", err)
+	}
+
+	var d cursorDocument
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Cursor{}, fmt.Errorf("cursor unmarshal: %w", err)
+	}
+
+	if d.OrderBy != orderBy.String() || len(d.Values) != len(orderBy.Keys()) {
+		return Cursor{}, fmt.Errorf("cursor was created for a different order")
+	}
+
+	if d.ID == "" {
+		return Cursor{}, fmt.Errorf("cursor is missing the row id")
+	}
+
+	c := Cursor{
+		orderBy: d.OrderBy,
+		values:  d.Values,
+		id:      d.ID,
+	}
+
+	return c, nil
+}
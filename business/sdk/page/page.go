@@ -4,49 +4,65 @@ package page
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
 )
 
-// Page represents the requested page and rows per page.
+// Page represents the requested page and rows per page. A page is either
+// addressed by number or by a cursor that resumes after the last row of the
+// previous page.
 type Page struct {
 	number int
 	rows   int
+	cursor *Cursor
 }
 
 // Parse parses the strings and validates the values are in reason.
 func Parse(page string, rowsPerPage string) (Page, error) {
+	rows, err := parseRows(rowsPerPage)
+	if err != nil {
+		return Page{}, err
+	}
+
 	number := 1
 	if page != "" {
-		var err error
 		number, err = strconv.Atoi(page)
 		if err != nil {
 			return Page{}, fmt.Errorf("page conversion: %w", err)
 		}
 	}
 
-	rows := 10
-	if rowsPerPage != "" {
-		var err error
-		rows, err = strconv.Atoi(rowsPerPage)
-		if err != nil {
-			return Page{}, fmt.Errorf("rows conversion: %w", err)
-		}
-	}
-
 	if number <= 0 {
 		return Page{}, fmt.Errorf("page value too small, must be larger than 0")
 	}
 
-	if rows <= 0 {
-		return Page{}, fmt.Errorf("rows value too small, must be larger than 0")
+	p := Page{
+		number: number,
+		rows:   rows,
 	}
 
-	if rows > 100 {
-		return Page{}, fmt.Errorf("rows value too large, must be less than 100")
+	return p, nil
+}
+
+// ParseCursor parses the cursor and rows per page strings into a page that
+// resumes after the row the cursor was created for. Passing CursorStart
+// requests the first page. The cursor must have been created for the
+// specified ordering.
+func ParseCursor(cursor string, rowsPerPage string, orderBy order.By) (Page, error) {
+	rows, err := parseRows(rowsPerPage)
+	if err != nil {
+		return Page{}, err
+	}
+
+	cur, err := decodeCursor(cursor, orderBy)
+	if err != nil {
+		return Page{}, err
 	}
 
 	p := Page{
-		number: number,
+		number: 1,
 		rows:   rows,
+		cursor: &cur,
 	}
 
 	return p, nil
@@ -64,10 +80,15 @@ func MustParse(page string, rowsPerPage string) Page {
 
 // String implements the stringer interface.
 func (p Page) String() string {
+	if p.cursor != nil {
+		return fmt.Sprintf("cursor: %s rows: %d", p.cursor, p.rows)
+	}
+
 	return fmt.Sprintf("page: %d rows: %d", p.number, p.rows)
 }
 
-// Number returns the page number.
+// Number returns the page number. A page addressed by cursor always starts
+// at the first row after the cursor, so its number is always 1.
 func (p Page) Number() int {
 	return p.number
 }
@@ -76,3 +97,42 @@ func (p Page) Number() int {
 func (p Page) RowsPerPage() int {
 	return p.rows
 }
+
+// IsCursor reports whether the page is addressed by a cursor. Clients paging
+// by cursor don't need the total number of rows.
+func (p Page) IsCursor() bool {
+	return p.cursor != nil
+}
+
+// Cursor returns the cursor the page resumes after. It reports false when the
+// page isn't addressed by a cursor or is the first page.
+func (p Page) Cursor() (Cursor, bool) {
+	if p.cursor == nil || p.cursor.isStart() {
+		return Cursor{}, false
+	}
+
+	return *p.cursor, true
+}
+
+// =============================================================================
+
+func parseRows(rowsPerPage string) (int, error) {
+	rows := 10
+	if rowsPerPage != "" {
+		var err error
+		rows, err = strconv.Atoi(rowsPerPage)
+		if err != nil {
+			return 0, fmt.Errorf("rows conversion: %w", err)
+		}
+	}
+
+	if rows <= 0 {
+		return 0, fmt.Errorf("rows value too small, must be larger than 0")
+	}
+
+	if rows > 100 {
+		return 0, fmt.Errorf("rows value too large, must be less than 100")
+	}
+
+	return rows, nil
+}
@@ -2,11 +2,107 @@
 package pubsub
 
 import (
+	"context"
+	"fmt"
+
 	"encore.dev/pubsub"
 	"github.com/ardanlabs/encore/business/sdk/delegate"
+	"github.com/ardanlabs/encore/business/sdk/events"
 )
 
 // Delegate represents a topic for handling delegate calls.
 var Delegate = pubsub.NewTopic[delegate.Data]("delegate", pubsub.TopicConfig{
 	DeliveryGuarantee: pubsub.AtLeastOnce,
 })
+
+// DelegatePublisher publishes the events of the delegate system to the
+// Delegate topic.
+type DelegatePublisher struct{}
+
+// Publish implements the delegate.Publisher interface.
+func (DelegatePublisher) Publish(ctx context.Context, data delegate.Data) error {
+	_, err := Delegate.Publish(ctx, data)
+	return err
+}
+
+// =============================================================================
+
+// UserEvents represents the public topic for the changes of users. The topic
+// name carries no version since it publishes version 1 of the payload.
+var UserEvents = pubsub.NewTopic[events.UserEvent]("user-events", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+	OrderingAttribute: "entity_id",
+})
+
+// ProductEvents represents the public topic for the changes of products.
+var ProductEvents = pubsub.NewTopic[events.ProductEvent]("product-events", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+	OrderingAttribute: "entity_id",
+})
+
+// HomeEvents represents the public topic for the changes of homes.
+var HomeEvents = pubsub.NewTopic[events.HomeEvent]("home-events", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+	OrderingAttribute: "entity_id",
+})
+
+// RelayPublisher publishes the events relayed from the outbox. The public
+// events go straight to their topics with the ordering key of the event as
+// the ordering attribute, so they keep the order they were written in. The
+// other events go to the Delegate topic.
+type RelayPublisher struct{}
+
+// Publish implements the delegate.Publisher interface.
+func (RelayPublisher) Publish(ctx context.Context, data delegate.Data) error {
+	if data.Domain != events.Domain {
+		return DelegatePublisher{}.Publish(ctx, data)
+	}
+
+	switch data.Action {
+	case events.UserTopic:
+		return publishUserEvent(ctx, data)
+
+	case events.ProductTopic:
+		return publishProductEvent(ctx, data)
+
+	case events.HomeTopic:
+		return publishHomeEvent(ctx, data)
+	}
+
+	return fmt.Errorf("unknown topic %q", data.Action)
+}
+
+// =============================================================================
+
+func publishUserEvent(ctx context.Context, data delegate.Data) error {
+	evt, err := delegate.Decode[events.UserEvent](data)
+	if err != nil {
+		return err
+	}
+
+	evt.OrderingKey = data.OrderingKey
+	_, err = UserEvents.Publish(ctx, evt)
+	return err
+}
+
+func publishProductEvent(ctx context.Context, data delegate.Data) error {
+	evt, err := delegate.Decode[events.ProductEvent](data)
+	if err != nil {
+		return err
+	}
+
+	evt.OrderingKey = data.OrderingKey
+	_, err = ProductEvents.Publish(ctx, evt)
+	return err
+}
+
+func publishHomeEvent(ctx context.Context, data delegate.Data) error {
+	evt, err := delegate.Decode[events.HomeEvent](data)
+	if err != nil {
+		return err
+	}
+
+	evt.OrderingKey = data.OrderingKey
+	_, err = HomeEvents.Publish(ctx, evt)
+	return err
+}
@@ -0,0 +1,136 @@
+package sqldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+)
+
+// OrderByClause returns the ORDER BY clause for the ordering using the map of
+// order by fields to column names. The key column is added as the final
+// tiebreaker so rows with equal values are always returned in the same order.
+func OrderByClause(orderBy order.By, columns map[string]string, key string) (string, error) {
+	keys, err := orderKeys(orderBy, columns, key)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k.column + " " + k.direction
+		if k.nulls != "" {
+			parts[i] += " " + k.nulls
+		}
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// CursorClause returns the condition that resumes a query ordered by the
+// OrderByClause after the row the page cursor points at. No condition is
+// returned when the page isn't addressed by a cursor. The cursor values are
+// added to the data map.
+func CursorClause(orderBy order.By, page page.Page, columns map[string]string, key string, data map[string]any) ([]string, error) {
+	cur, ok := page.Cursor()
+	if !ok {
+		return nil, nil
+	}
+
+	keys, err := orderKeys(orderBy, columns, key)
+	if err != nil {
+		return nil, err
+	}
+
+	values := cur.Values()
+
+	names := make([]string, len(keys))
+	sameDirection := true
+	for i, k := range keys {
+		name := fmt.Sprintf("cursor_%d", i)
+		names[i] = ":" + name
+
+		switch {
+		case i < len(values):
+			data[name] = values[i]
+		default:
+			data[name] = cur.ID()
+		}
+
+		if k.direction != keys[0].direction {
+			sameDirection = false
+		}
+	}
+
+	// A row comparison can use an index, but it's only correct when every
+	// key is ordered in the same direction.
+	if sameDirection {
+		cols := make([]string, len(keys))
+		for i, k := range keys {
+			cols[i] = k.column
+		}
+
+		cond := "(" + strings.Join(cols, ", ") + ") " + comparison(keys[0]) + " (" + strings.Join(names, ", ") + ")"
+		return []string{cond}, nil
+	}
+
+	terms := make([]string, len(keys))
+	for i, k := range keys {
+		var term []string
+		for j := range i {
+			term = append(term, keys[j].column+" = "+names[j])
+		}
+		term = append(term, k.column+" "+comparison(k)+" "+names[i])
+
+		terms[i] = "(" + strings.Join(term, " AND ") + ")"
+	}
+
+	return []string{"(" + strings.Join(terms, " OR ") + ")"}, nil
+}
+
+// =============================================================================
+
+type orderKey struct {
+	column    string
+	direction string
+	nulls     string
+}
+
+func orderKeys(orderBy order.By, columns map[string]string, key string) ([]orderKey, error) {
+	var keys []orderKey
+
+	for _, by := range orderBy.Keys() {
+		column, exists := columns[by.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q does not exist", by.Field)
+		}
+
+		keys = append(keys, orderKey{
+			column:    column,
+			direction: by.Direction,
+			nulls:     by.Nulls,
+		})
+
+		// Rows are unique once ordered by the key so any remaining fields
+		// can't change the order.
+		if column == key {
+			return keys, nil
+		}
+	}
+
+	keys = append(keys, orderKey{
+		column:    key,
+		direction: keys[len(keys)-1].direction,
+	})
+
+	return keys, nil
+}
+
+func comparison(k orderKey) string {
+	if k.direction == order.DESC {
+		return "<"
+	}
+
+	return ">"
+}
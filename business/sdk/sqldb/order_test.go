@@ -0,0 +1,103 @@
+package sqldb_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/encore/business/sdk/order"
+	"github.com/ardanlabs/encore/business/sdk/page"
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
+	"github.com/google/go-cmp/cmp"
+)
+
+var columns = map[string]string{
+	"product_id": "product_id",
+	"name":       "name",
+	"cost":       "cost",
+}
+
+func Test_OrderByClause(t *testing.T) {
+	table := []struct {
+		name    string
+		orderBy order.By
+		exp     string
+	}{
+		{
+			name:    "key",
+			orderBy: order.NewBy("product_id", order.DESC),
+			exp:     " ORDER BY product_id DESC",
+		},
+		{
+			name:    "tiebreaker",
+			orderBy: order.NewBy("name", order.ASC),
+			exp:     " ORDER BY name ASC, product_id ASC",
+		},
+		{
+			name:    "multiple",
+			orderBy: order.By{Field: "name", Direction: order.ASC, Nulls: order.NullsLast}.ThenBy("cost", order.DESC),
+			exp:     " ORDER BY name ASC NULLS LAST, cost DESC, product_id DESC",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sqldb.OrderByClause(tt.orderBy, columns, "product_id")
+			if err != nil {
+				t.Fatalf("Should be able to build the clause: %s", err)
+			}
+
+			if got != tt.exp {
+				t.Fatalf("Should get %q, got %q", tt.exp, got)
+			}
+		})
+	}
+}
+
+func Test_CursorClause(t *testing.T) {
+	table := []struct {
+		name    string
+		orderBy order.By
+		values  []string
+		exp     string
+		expData map[string]any
+	}{
+		{
+			name:    "same",
+			orderBy: order.NewBy("name", order.DESC),
+			values:  []string{"Guitar"},
+			exp:     "(name, product_id) < (:cursor_0, :cursor_1)",
+			expData: map[string]any{"cursor_0": "Guitar", "cursor_1": "id"},
+		},
+		{
+			name:    "mixed",
+			orderBy: order.NewBy("name", order.ASC).ThenBy("cost", order.DESC),
+			values:  []string{"Guitar", "10.5"},
+			exp:     "((name > :cursor_0) OR (name = :cursor_0 AND cost < :cursor_1) OR (name = :cursor_0 AND cost = :cursor_1 AND product_id < :cursor_2))",
+			expData: map[string]any{"cursor_0": "Guitar", "cursor_1": "10.5", "cursor_2": "id"},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := page.NewCursor(tt.orderBy, tt.values, "id").String()
+
+			pg, err := page.ParseCursor(cursor, "10", tt.orderBy)
+			if err != nil {
+				t.Fatalf("Should be able to parse the cursor: %s", err)
+			}
+
+			data := map[string]any{}
+			got, err := sqldb.CursorClause(tt.orderBy, pg, columns, "product_id", data)
+			if err != nil {
+				t.Fatalf("Should be able to build the clause: %s", err)
+			}
+
+			if diff := cmp.Diff(got, []string{tt.exp}); diff != "" {
+				t.Fatalf("Should get the expected clause:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(data, tt.expData); diff != "" {
+				t.Fatalf("Should get the expected data:\n%s", diff)
+			}
+		})
+	}
+}
@@ -109,7 +109,12 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 		}
 	}()
 
-	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+	exec := func(db sqlx.ExtContext) error {
+		_, err := sqlx.NamedExecContext(ctx, db, query, data)
+		return err
+	}
+
+	if err := withTenant(ctx, db, exec); err != nil {
 		var pqerr *pgconn.PgError
 		if errors.As(err, &pqerr) {
 			switch pqerr.Code {
@@ -154,49 +159,24 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 		}
 	}()
 
-	var rows *sqlx.Rows
-
-	switch withIn {
-	case true:
-		rows, err = func() (*sqlx.Rows, error) {
-			named, args, err := sqlx.Named(query, data)
-			if err != nil {
-				return nil, err
-			}
-
-			query, args, err := sqlx.In(named, args...)
-			if err != nil {
-				return nil, err
+	return withTenant(ctx, db, func(db sqlx.ExtContext) error {
+		var slice []T
+		if err := queryRows(ctx, db, query, data, withIn, func(rows *sqlx.Rows) error {
+			for rows.Next() {
+				v := new(T)
+				if err := rows.StructScan(v); err != nil {
+					return err
+				}
+				slice = append(slice, *v)
 			}
-
-			query = db.Rebind(query)
-			return db.QueryxContext(ctx, query, args...)
-		}()
-
-	default:
-		rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
-	}
-
-	if err != nil {
-		var pqerr *pgconn.PgError
-		if errors.As(err, &pqerr) && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
-		}
-		return err
-	}
-	defer rows.Close()
-
-	var slice []T
-	for rows.Next() {
-		v := new(T)
-		if err := rows.StructScan(v); err != nil {
+			return nil
+		}); err != nil {
 			return err
 		}
-		slice = append(slice, *v)
-	}
-	*dest = slice
 
-	return nil
+		*dest = slice
+		return nil
+	})
 }
 
 // QueryStruct is a helper function for executing queries that return a
@@ -223,11 +203,25 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 
 	defer func() {
 		if err != nil {
-			log.Info(ctx, "database.NamedQuerySlice", "query", q, "ERROR", err)
+			log.Info(ctx, "database.NamedQueryStruct", "query", q, "ERROR", err)
 		}
 	}()
 
+	return withTenant(ctx, db, func(db sqlx.ExtContext) error {
+		return queryRows(ctx, db, query, data, withIn, func(rows *sqlx.Rows) error {
+			if !rows.Next() {
+				return ErrDBNotFound
+			}
+
+			return rows.StructScan(dest)
+		})
+	})
+}
+
+// queryRows executes the query and provides the rows to the scan function.
+func queryRows(ctx context.Context, db sqlx.ExtContext, query string, data any, withIn bool, scan func(rows *sqlx.Rows) error) error {
 	var rows *sqlx.Rows
+	var err error
 
 	switch withIn {
 	case true:
@@ -259,15 +253,14 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	}
 	defer rows.Close()
 
-	if !rows.Next() {
-		return ErrDBNotFound
-	}
-
-	if err := rows.StructScan(dest); err != nil {
-		return err
-	}
+	return scan(rows)
+}
 
-	return nil
+// EscapeLike escapes the LIKE wildcard characters in the value so it's matched
+// literally when used as part of a LIKE or ILIKE pattern.
+func EscapeLike(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return r.Replace(value)
 }
 
 // queryString provides a pretty print version of the query and parameters.
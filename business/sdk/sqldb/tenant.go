@@ -0,0 +1,102 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ctxKey int
+
+const (
+	tenantKey ctxKey = iota + 1
+	systemKey
+)
+
+// WithTenant returns a context that scopes every query executed with it to
+// the specified tenant. The row level security policies of the tenant tables
+// only make the rows of that tenant visible.
+func WithTenant(ctx context.Context, tenantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// GetTenant returns the tenant the context is scoped to.
+func GetTenant(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(tenantKey).(uuid.UUID)
+	return v, ok
+}
+
+// WithSystem returns a context that lets every query executed with it see
+// the rows of every tenant. It's meant for the jobs that act for the system
+// like crons, event handlers and sign in, never for a request of a user. A
+// tenant set on the context takes precedence.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+// IsSystem reports whether the context acts for the system.
+func IsSystem(ctx context.Context) bool {
+	v, _ := ctx.Value(systemKey).(bool)
+	return v
+}
+
+// withTenant executes the function with the tenant of the context set for
+// the row level security policies. A context that is neither scoped to a
+// tenant nor acting for the system sees none of the rows of the tenant
+// tables. The setting only lasts as long as the transaction, so a query that
+// isn't already part of one is executed inside its own transaction. That way
+// the setting can never leak to the next user of a pooled connection.
+func withTenant(ctx context.Context, db sqlx.ExtContext, f func(db sqlx.ExtContext) error) (err error) {
+	var q string
+	var arg string
+
+	tenantID, ok := GetTenant(ctx)
+	switch {
+	case ok:
+		q = `SELECT set_config('app.tenant_id', $1, true)`
+		arg = tenantID.String()
+
+	case IsSystem(ctx):
+		q = `SELECT set_config('app.system', $1, true)`
+		arg = "on"
+
+	default:
+		return f(db)
+	}
+
+	sqlxDB, ok := db.(*sqlx.DB)
+	if !ok {
+		if _, err := db.ExecContext(ctx, q, arg); err != nil {
+			return fmt.Errorf("set tenant: %w", err)
+		}
+
+		return f(db)
+	}
+
+	tx, err := sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, q, arg); err != nil {
+		return fmt.Errorf("set tenant: %w", err)
+	}
+
+	if err := f(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ardanlabs/encore/business/domain/homebus"
+	"github.com/ardanlabs/encore/business/domain/orderbus"
 	"github.com/ardanlabs/encore/business/domain/productbus"
 	"github.com/ardanlabs/encore/business/domain/userbus"
 )
@@ -13,6 +14,7 @@ type User struct {
 	userbus.User
 	Products []productbus.Product
 	Homes    []homebus.Home
+	Orders   []orderbus.Order
 }
 
 // SeedData represents data that was seeded for the test.
@@ -4,13 +4,17 @@ package unitest
 import (
 	"context"
 	"testing"
+
+	"github.com/ardanlabs/encore/business/sdk/sqldb"
 )
 
 // Run performs the actual test logic based on the table data.
 func Run(t *testing.T, table []Table, testName string) {
 	for _, tt := range table {
 		f := func(t *testing.T) {
-			gotResp := tt.ExcFunc(context.Background())
+			// The tests act for the system so they can reach the rows of
+			// every tenant. A test of tenancy scopes the context itself.
+			gotResp := tt.ExcFunc(sqldb.WithSystem(context.Background()))
 
 			diff := tt.CmpFunc(gotResp, tt.ExpResp)
 			if diff != "" {
@@ -4,23 +4,45 @@ package keystore
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// State represents where a key is in its rotation.
+type State string
+
+// Set of key states. The active key signs new tokens. A retiring key no
+// longer signs tokens but still verifies the tokens it signed before it
+// was replaced. A retired key can't be used at all.
+const (
+	StateActive   State = "active"
+	StateRetiring State = "retiring"
+	StateRetired  State = "retired"
 )
 
 // key represents key information.
 type key struct {
 	privatePEM string
 	publicPEM  string
+	state      State
 }
 
 // KeyStore represents an in memory store implementation of the
 // KeyLookup interface for use with the auth package.
 type KeyStore struct {
-	store map[string]key
+	mu        sync.RWMutex
+	store     map[string]key
+	activeKID string
 }
 
 // New constructs an empty KeyStore ready for use.
@@ -30,8 +52,9 @@ func New() *KeyStore {
 	}
 }
 
-// LoadKey takes an id and the private PEM string.
-func (ks *KeyStore) LoadKey(id string, pem string) error {
+// LoadKey takes an id, the private PEM string and the state of the key. When
+// the key is loaded as the active key, the key that was active is retiring.
+func (ks *KeyStore) LoadKey(id string, pem string, state State) error {
 	privatePEM := string(pem)
 	publicPEM, err := toPublicPEM(privatePEM)
 	if err != nil {
@@ -43,35 +66,151 @@ func (ks *KeyStore) LoadKey(id string, pem string) error {
 		publicPEM:  publicPEM,
 	}
 
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
 	ks.store[id] = key
 
+	return ks.setState(id, state)
+}
+
+// LoadDirectory loads every PEM file in the directory as a retiring key. The
+// name of the file without the extension is the id of the key. Use SetState
+// to choose the active key.
+func (ks *KeyStore) LoadDirectory(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("reading directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("reading key[%s]: %w", entry.Name(), err)
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+
+		if err := ks.LoadKey(kid, string(data), StateRetiring); err != nil {
+			return fmt.Errorf("loading key[%s]: %w", entry.Name(), err)
+		}
+	}
+
 	return nil
 }
 
-// PrivateKey searches the key store for a given kid and returns the private key.
+// SetState changes the state of the key. When the key becomes the active
+// key, the key that was active is retiring.
+func (ks *KeyStore) SetState(kid string, state State) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, found := ks.store[kid]; !found {
+		return errors.New("kid lookup failed")
+	}
+
+	return ks.setState(kid, state)
+}
+
+// ActiveKID returns the id of the key used to sign new tokens. An empty
+// string is returned when there is no active key.
+func (ks *KeyStore) ActiveKID() string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.activeKID
+}
+
+// PrivateKey searches the key store for a given kid and returns the private
+// key. Only the active key can be used to sign tokens.
 func (ks *KeyStore) PrivateKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
 	key, found := ks.store[kid]
 	if !found {
 		return "", errors.New("kid lookup failed")
 	}
 
+	if key.state != StateActive {
+		return "", fmt.Errorf("kid is %s, not active", key.state)
+	}
+
 	return key.privatePEM, nil
 }
 
-// PublicKey searches the key store for a given kid and returns the public key.
+// PublicKey searches the key store for a given kid and returns the public
+// key. Retired keys can't be used to verify tokens.
 func (ks *KeyStore) PublicKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
 	key, found := ks.store[kid]
 	if !found {
 		return "", errors.New("kid lookup failed")
 	}
 
+	if key.state == StateRetired {
+		return "", errors.New("kid is retired")
+	}
+
 	return key.publicPEM, nil
 }
 
+// PublicKeys returns the public keys that can be used to verify tokens
+// keyed by their kid.
+func (ks *KeyStore) PublicKeys() map[string]string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make(map[string]string)
+	for kid, key := range ks.store {
+		if key.state != StateRetired {
+			keys[kid] = key.publicPEM
+		}
+	}
+
+	return keys
+}
+
+// =============================================================================
+
+// setState must be called with the lock held.
+func (ks *KeyStore) setState(kid string, state State) error {
+	switch state {
+	case StateActive, StateRetiring, StateRetired:
+	default:
+		return fmt.Errorf("invalid state %q", state)
+	}
+
+	key := ks.store[kid]
+
+	switch {
+	case state == StateActive:
+		if prev, found := ks.store[ks.activeKID]; found && ks.activeKID != kid {
+			prev.state = StateRetiring
+			ks.store[ks.activeKID] = prev
+		}
+		ks.activeKID = kid
+
+	case ks.activeKID == kid:
+		ks.activeKID = ""
+	}
+
+	key.state = state
+	ks.store[kid] = key
+
+	return nil
+}
+
 func toPublicPEM(privatePEM string) (string, error) {
 	block, _ := pem.Decode([]byte(privatePEM))
 	if block == nil {
-		return "", errors.New("invalid key: Key must be a PEM encoded PKCS1 or PKCS8 key")
+		return "", errors.New("invalid key: Key must be a PEM encoded PKCS1, PKCS8 or SEC1 key")
 	}
 
 	var parsedKey any
@@ -79,16 +218,27 @@ func toPublicPEM(privatePEM string) (string, error) {
 	if err != nil {
 		parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
 		if err != nil {
-			return "", err
+			parsedKey, err = x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return "", err
+			}
 		}
 	}
 
-	pk, ok := parsedKey.(*rsa.PrivateKey)
-	if !ok {
-		return "", errors.New("key is not a valid RSA private key")
+	// RSA, ECDSA and Ed25519 keys are supported.
+	var publicKey crypto.PublicKey
+	switch pk := parsedKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &pk.PublicKey
+	case *ecdsa.PrivateKey:
+		publicKey = &pk.PublicKey
+	case ed25519.PrivateKey:
+		publicKey = pk.Public()
+	default:
+		return "", fmt.Errorf("key type %T is not supported", parsedKey)
 	}
 
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&pk.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
 		return "", fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -0,0 +1,174 @@
+package keystore_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardanlabs/encore/foundation/keystore"
+)
+
+func Test_Rotation(t *testing.T) {
+	ks := keystore.New()
+
+	for _, kid := range []string{"first", "second", "third"} {
+		if err := ks.LoadKey(kid, newPEM(t), keystore.StateRetiring); err != nil {
+			t.Fatalf("Should be able to load key %q: %s", kid, err)
+		}
+	}
+
+	if kid := ks.ActiveKID(); kid != "" {
+		t.Fatalf("Should have no active key before one is chosen, got %q", kid)
+	}
+
+	if _, err := ks.PrivateKey("first"); err == nil {
+		t.Fatal("Should NOT be able to sign with a retiring key")
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := ks.SetState("first", keystore.StateActive); err != nil {
+		t.Fatalf("Should be able to activate the first key: %s", err)
+	}
+
+	if _, err := ks.PrivateKey("first"); err != nil {
+		t.Fatalf("Should be able to sign with the active key: %s", err)
+	}
+
+	if err := ks.SetState("second", keystore.StateActive); err != nil {
+		t.Fatalf("Should be able to activate the second key: %s", err)
+	}
+
+	if kid := ks.ActiveKID(); kid != "second" {
+		t.Fatalf("Should have the second key active, got %q", kid)
+	}
+
+	if _, err := ks.PrivateKey("first"); err == nil {
+		t.Fatal("Should NOT be able to sign with the key that was active before")
+	}
+
+	if _, err := ks.PublicKey("first"); err != nil {
+		t.Fatalf("Should be able to verify with the key that was active before: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := ks.SetState("first", keystore.StateRetired); err != nil {
+		t.Fatalf("Should be able to retire the first key: %s", err)
+	}
+
+	if _, err := ks.PublicKey("first"); err == nil {
+		t.Fatal("Should NOT be able to verify with a retired key")
+	}
+
+	keys := ks.PublicKeys()
+	if _, found := keys["first"]; found {
+		t.Fatal("Should NOT publish a retired key")
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("Should publish the active and retiring keys, got %d keys", len(keys))
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := ks.SetState("second", keystore.StateRetired); err != nil {
+		t.Fatalf("Should be able to retire the active key: %s", err)
+	}
+
+	if kid := ks.ActiveKID(); kid != "" {
+		t.Fatalf("Should have no active key once it's retired, got %q", kid)
+	}
+
+	if err := ks.SetState("second", "expired"); err == nil {
+		t.Fatal("Should NOT be able to set an unknown state")
+	}
+
+	if err := ks.SetState("fourth", keystore.StateActive); err == nil {
+		t.Fatal("Should NOT be able to change the state of an unknown key")
+	}
+}
+
+func Test_LoadKeyActive(t *testing.T) {
+	ks := keystore.New()
+
+	if err := ks.LoadKey("first", newPEM(t), keystore.StateActive); err != nil {
+		t.Fatalf("Should be able to load the first key: %s", err)
+	}
+
+	if err := ks.LoadKey("second", newPEM(t), keystore.StateActive); err != nil {
+		t.Fatalf("Should be able to load the second key: %s", err)
+	}
+
+	if kid := ks.ActiveKID(); kid != "second" {
+		t.Fatalf("Should have the last key loaded as active, got %q", kid)
+	}
+
+	if _, err := ks.PrivateKey("first"); err == nil {
+		t.Fatal("Should NOT be able to sign with the key that was replaced")
+	}
+
+	if _, err := ks.PublicKey("first"); err != nil {
+		t.Fatalf("Should be able to verify with the key that was replaced: %s", err)
+	}
+}
+
+func Test_LoadDirectory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"keys/first.pem":        {Data: []byte(newPEM(t))},
+		"keys/second.pem":       {Data: []byte(newPEM(t))},
+		"keys/README.md":        {Data: []byte("not a key")},
+		"keys/nested/third.pem": {Data: []byte(newPEM(t))},
+	}
+
+	ks := keystore.New()
+	if err := ks.LoadDirectory(fsys, "keys"); err != nil {
+		t.Fatalf("Should be able to load the directory: %s", err)
+	}
+
+	keys := ks.PublicKeys()
+	if len(keys) != 2 {
+		t.Fatalf("Should only load the PEM files of the directory, got %d keys", len(keys))
+	}
+
+	for _, kid := range []string{"first", "second"} {
+		if _, found := keys[kid]; !found {
+			t.Fatalf("Should load key %q named after its file", kid)
+		}
+	}
+
+	if kid := ks.ActiveKID(); kid != "" {
+		t.Fatalf("Should load the keys as retiring, got %q active", kid)
+	}
+
+	if err := ks.LoadDirectory(fsys, "missing"); err == nil {
+		t.Fatal("Should NOT be able to load a missing directory")
+	}
+
+	bad := fstest.MapFS{
+		"keys/bad.pem": {Data: []byte("not a key")},
+	}
+
+	if err := keystore.New().LoadDirectory(bad, "keys"); err == nil {
+		t.Fatal("Should NOT be able to load an invalid PEM file")
+	}
+}
+
+// =============================================================================
+
+func newPEM(t *testing.T) string {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate a key: %s", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Should be able to marshal the key: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}